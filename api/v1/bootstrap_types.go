@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+type BootstrapStep string
+
+const (
+	BootstrapStepWaitForNodes              BootstrapStep = "WaitForNodes"
+	BootstrapStepRegisterClientCertificate BootstrapStep = "RegisterClientCertificate"
+	BootstrapStepJoinMembers               BootstrapStep = "JoinMembers"
+	BootstrapStepJoinWatchers              BootstrapStep = "JoinWatchers"
+)
+
+// BootstrapSteps are the steps of the bootstrap, in the order they run
+var BootstrapSteps = []BootstrapStep{
+	BootstrapStepWaitForNodes,
+	BootstrapStepRegisterClientCertificate,
+	BootstrapStepJoinMembers,
+	BootstrapStepJoinWatchers,
+}
+
+type BootstrapPhase string
+
+const (
+	BootstrapPhaseRunning   BootstrapPhase = "Running"
+	BootstrapPhaseFailed    BootstrapPhase = "Failed"
+	BootstrapPhaseCompleted BootstrapPhase = "Completed"
+)
+
+type BootstrapStepPhase string
+
+const (
+	BootstrapStepPending   BootstrapStepPhase = "Pending"
+	BootstrapStepRunning   BootstrapStepPhase = "Running"
+	BootstrapStepFailed    BootstrapStepPhase = "Failed"
+	BootstrapStepCompleted BootstrapStepPhase = "Completed"
+)
+
+// BootstrapStatus describes how the RavenDB cluster was formed. the bootstrapper runs one step per
+// reconcile and every step checks what was already done first, so a bootstrap that failed (or was cut
+// short by an operator restart) resumes from the step it stopped at.
+type BootstrapStatus struct {
+	// Failed means the last attempt of the current step failed, it is retried on the next reconcile
+	// +kubebuilder:validation:Enum=Running;Failed;Completed
+	Phase BootstrapPhase `json:"phase,omitempty"`
+
+	// step being run now (empty once completed)
+	// +kubebuilder:validation:Enum=WaitForNodes;RegisterClientCertificate;JoinMembers;JoinWatchers
+	CurrentStep BootstrapStep `json:"currentStep,omitempty"`
+
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// steps in the order they run
+	Steps []BootstrapStepStatus `json:"steps,omitempty"`
+}
+
+type BootstrapStepStatus struct {
+	// +kubebuilder:validation:Enum=WaitForNodes;RegisterClientCertificate;JoinMembers;JoinWatchers
+	Name BootstrapStep `json:"name"`
+
+	// +kubebuilder:validation:Enum=Pending;Running;Failed;Completed
+	Phase BootstrapStepPhase `json:"phase"`
+
+	// what the step waits for, or why its last attempt failed
+	Message string `json:"message,omitempty"`
+
+	// number of failed attempts
+	Attempts int32 `json:"attempts,omitempty"`
+
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
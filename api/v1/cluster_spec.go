@@ -64,6 +64,30 @@ type RavenDBClusterSpec struct {
 	// +kubebuilder:validation:Optional
 	CACertSecretRef *string `json:"caCertSecretRef,omitempty"`
 
-	// // +kubebuilder:validation:Optional
-	// Sidecars []Sidecar `json:"sidecars,omitempty"`
+	// +kubebuilder:validation:Optional
+	Upgrade *UpgradeSpec `json:"upgrade,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Topology *TopologySpec `json:"topology,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Notifications *NotificationsSpec `json:"notifications,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Probes *ProbesSpec `json:"probes,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	PodTemplate *PodTemplateSpec `json:"podTemplate,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	DisruptionBudget *DisruptionBudgetSpec `json:"disruptionBudget,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	NetworkPolicy *NetworkPolicySpec `json:"networkPolicy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Sidecars []Sidecar `json:"sidecars,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	InitContainers []Sidecar `json:"initContainers,omitempty"`
 }
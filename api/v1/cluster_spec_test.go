@@ -2,6 +2,7 @@ package v1
 
 import (
 	"testing"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -283,3 +284,65 @@ func TestClientCertSecretRefValidation(t *testing.T) {
 	}
 	runSpecValidationTest(t, baseClusterForClusterSpecTest, testCases)
 }
+
+func TestUpgradeSpecValidation(t *testing.T) {
+	testCases := []SpecValidationCase{
+		{
+			Name: "valid upgrade control Pause",
+			Modify: func(spec *RavenDBClusterSpec) {
+				spec.Upgrade = &UpgradeSpec{Control: UpgradeControlPause}
+			},
+			ExpectError: false,
+		},
+		{
+			Name: "valid upgrade control Abort",
+			Modify: func(spec *RavenDBClusterSpec) {
+				spec.Upgrade = &UpgradeSpec{Control: UpgradeControlAbort}
+			},
+			ExpectError: false,
+		},
+		{
+			Name: "invalid upgrade control",
+			Modify: func(spec *RavenDBClusterSpec) {
+				spec.Upgrade = &UpgradeSpec{Control: "Stop"}
+			},
+			ExpectError: true,
+			ErrorParts:  []string{"spec.upgrade.control", "Unsupported value"},
+		},
+		{
+			Name: "valid rollback policy Automatic",
+			Modify: func(spec *RavenDBClusterSpec) {
+				spec.Upgrade = &UpgradeSpec{RollbackPolicy: RollbackPolicyAutomatic}
+			},
+			ExpectError: false,
+		},
+		{
+			Name: "invalid rollback policy",
+			Modify: func(spec *RavenDBClusterSpec) {
+				spec.Upgrade = &UpgradeSpec{RollbackPolicy: "Always"}
+			},
+			ExpectError: true,
+			ErrorParts:  []string{"spec.upgrade.rollbackPolicy", "Unsupported value"},
+		},
+		{
+			Name: "valid gate override",
+			Modify: func(spec *RavenDBClusterSpec) {
+				disabled := false
+				spec.Upgrade = &UpgradeSpec{Gates: []UpgradeGateSpec{
+					{Name: "cluster_connectivity", Enabled: &disabled},
+					{Name: "node_alive", Timeout: &metav1.Duration{Duration: 10 * time.Minute}},
+				}}
+			},
+			ExpectError: false,
+		},
+		{
+			Name: "invalid gate name",
+			Modify: func(spec *RavenDBClusterSpec) {
+				spec.Upgrade = &UpgradeSpec{Gates: []UpgradeGateSpec{{Name: "Node Alive"}}}
+			},
+			ExpectError: true,
+			ErrorParts:  []string{"spec.upgrade.gates[0].name"},
+		},
+	}
+	runSpecValidationTest(t, baseClusterForClusterSpecTest, testCases)
+}
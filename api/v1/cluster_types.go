@@ -20,6 +20,12 @@ import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Upgrade",type=string,JSONPath=`.status.upgrade.phase`
+// +kubebuilder:printcolumn:name="Target",type=string,JSONPath=`.status.upgrade.targetImage`,priority=1
+// +kubebuilder:printcolumn:name="Upgrading",type=string,JSONPath=`.status.upgrade.currentNode`
+// +kubebuilder:printcolumn:name="Gate",type=string,JSONPath=`.status.upgrade.gateKind`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 type RavenDBCluster struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -43,5 +49,11 @@ type RavenDBClusterStatus struct {
 	Message            string              `json:"message,omitempty"`
 	ObservedGeneration int64               `json:"observedGeneration,omitempty"`
 	Nodes              []RavenDBNodeStatus `json:"nodes,omitempty"`
+	Bootstrap          *BootstrapStatus    `json:"bootstrap,omitempty"`
+	Upgrade            *UpgradeStatus      `json:"upgrade,omitempty"`
+	Topology           *TopologyStatus     `json:"topology,omitempty"`
+	NodeRemoval        *NodeRemovalStatus  `json:"nodeRemoval,omitempty"`
+	Databases          []DatabaseHealth    `json:"databases,omitempty"`
+	DatabasesSummary   *DatabasesSummary   `json:"databasesSummary,omitempty"`
 	Conditions         []metav1.Condition  `json:"conditions,omitempty"`
 }
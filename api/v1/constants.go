@@ -51,6 +51,9 @@ const (
 	ConditionExternalAccessReady ClusterConditionType = "ExternalAccessReady"
 	ConditionNodesHealthy        ClusterConditionType = "NodesHealthy"
 	ConditionBootstrapCompleted  ClusterConditionType = "BootstrapCompleted"
+	ConditionUpgrading           ClusterConditionType = "Upgrading"
+	ConditionTopologyInSync      ClusterConditionType = "ClusterTopologyInSync"
+	ConditionDatabasesHealthy    ClusterConditionType = "DatabasesHealthy"
 )
 
 type ClusterConditionReason string
@@ -64,7 +67,68 @@ const (
 	ReasonLoadBalancerPending   ClusterConditionReason = "LoadBalancerPending"
 	ReasonCertSecretMissing     ClusterConditionReason = "CertSecretMissing"
 	ReasonLicenseSecretMissing  ClusterConditionReason = "LicenseSecretMissing"
-	ReasonBootstrapJobRunning   ClusterConditionReason = "BootstrapJobRunning"
+	ReasonBootstrapInProgress   ClusterConditionReason = "BootstrapInProgress"
 	ReasonBootstrapFailed       ClusterConditionReason = "BootstrapFailed"
 	ReasonPVCNotBound           ClusterConditionReason = "PVCNotBound"
+	ReasonUpgradeInProgress     ClusterConditionReason = "UpgradeInProgress"
+	ReasonUpgradePausing        ClusterConditionReason = "UpgradePausing"
+	ReasonUpgradePaused         ClusterConditionReason = "UpgradePaused"
+	ReasonUpgradeAborting       ClusterConditionReason = "UpgradeAborting"
+	ReasonUpgradeAborted        ClusterConditionReason = "UpgradeAborted"
+	ReasonUpgradeRollingBack    ClusterConditionReason = "UpgradeRollingBack"
+	ReasonUpgradeRolledBack     ClusterConditionReason = "UpgradeRolledBack"
+	ReasonTopologyDrift         ClusterConditionReason = "TopologyDrift"
+	ReasonTopologyUnavailable   ClusterConditionReason = "TopologyUnavailable"
+	ReasonNodeUnreachable       ClusterConditionReason = "NodeUnreachable"
+	ReasonNodeNotInCluster      ClusterConditionReason = "NodeNotInCluster"
+	ReasonDatabasesUnavailable  ClusterConditionReason = "DatabasesUnavailable"
+	ReasonDatabaseOffline       ClusterConditionReason = "DatabaseOffline"
+	ReasonDatabaseNodesInError  ClusterConditionReason = "DatabaseNodesInError"
+	ReasonProbeFailed           ClusterConditionReason = "ProbeFailed"
+)
+
+type PVCRetentionPolicy string
+
+const (
+	PVCRetentionRetain PVCRetentionPolicy = "Retain"
+	PVCRetentionDelete PVCRetentionPolicy = "Delete"
+)
+
+type DatabaseDeletionPolicy string
+
+const (
+	DeletionPolicyRetain DatabaseDeletionPolicy = "Retain"
+	DeletionPolicyDelete DatabaseDeletionPolicy = "Delete"
+)
+
+type DatabasePhase string
+
+const (
+	DatabasePhasePending  DatabasePhase = "Pending"
+	DatabasePhaseReady    DatabasePhase = "Ready"
+	DatabasePhaseError    DatabasePhase = "Error"
+	DatabasePhaseDeleting DatabasePhase = "Deleting"
+)
+
+type DatabaseConditionType string
+
+const (
+	DatabaseConditionReady            DatabaseConditionType = "Ready"
+	DatabaseConditionClusterAvailable DatabaseConditionType = "ClusterAvailable"
+	DatabaseConditionCreated          DatabaseConditionType = "Created"
+	DatabaseConditionTopologyInSync   DatabaseConditionType = "TopologyInSync"
+	DatabaseConditionSettingsApplied  DatabaseConditionType = "SettingsApplied"
+)
+
+type DatabaseConditionReason string
+
+const (
+	DatabaseReasonCompleted         DatabaseConditionReason = "Completed"
+	DatabaseReasonClusterNotFound   DatabaseConditionReason = "ClusterNotFound"
+	DatabaseReasonClusterNotReady   DatabaseConditionReason = "ClusterNotReady"
+	DatabaseReasonRequestFailed     DatabaseConditionReason = "RequestFailed"
+	DatabaseReasonInvalidTopology   DatabaseConditionReason = "InvalidTopology"
+	DatabaseReasonTopologyUpdating  DatabaseConditionReason = "TopologyUpdating"
+	DatabaseReasonSettingsUpdating  DatabaseConditionReason = "SettingsUpdating"
+	DatabaseReasonDeletionRequested DatabaseConditionReason = "DeletionRequested"
 )
@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// DatabaseHealth is a compact summary of one database of the cluster, as /databases reports it
+type DatabaseHealth struct {
+	Name     string `json:"name"`
+	Disabled bool   `json:"disabled,omitempty"`
+
+	ReplicationFactor int `json:"replicationFactor,omitempty"`
+
+	// node tags of the database group
+	Members     []string `json:"members,omitempty"`
+	Promotables []string `json:"promotables,omitempty"`
+	Rehabs      []string `json:"rehabs,omitempty"`
+
+	// nodes of the database group whose last status isn't Ok
+	NodesInError []DatabaseNodeError `json:"nodesInError,omitempty"`
+}
+
+// status.databases lists at most this many databases (the ones with problems first), a cluster can have thousands
+const MaxDatabasesInStatus = 50
+
+// DatabasesSummary counts the databases of the cluster, including the ones status.databases leaves out
+type DatabasesSummary struct {
+	Total     int `json:"total"`
+	Unhealthy int `json:"unhealthy,omitempty"`
+	// databases not listed in status.databases
+	Omitted int `json:"omitted,omitempty"`
+}
+
+type DatabaseNodeError struct {
+	Tag    string `json:"tag"`
+	Status string `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Serving reports whether a member of the group isn't in error, i.e. clients can still use the database
+func (d DatabaseHealth) Serving() bool {
+	failing := make(map[string]struct{}, len(d.NodesInError))
+	for _, n := range d.NodesInError {
+		failing[n.Tag] = struct{}{}
+	}
+	for _, tag := range d.Members {
+		if _, ok := failing[tag]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Healthy reports whether every node of an enabled database is Ok and none is in rehab, a disabled database is
+// not looked at
+func (d DatabaseHealth) Healthy() bool {
+	return d.Disabled || (len(d.NodesInError) == 0 && len(d.Rehabs) == 0)
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (d *RavenDBDatabase) SetConditionTrue(t DatabaseConditionType, reason DatabaseConditionReason, msg string, now metav1.Time) {
+	d.setCondition(t, metav1.ConditionTrue, reason, msg, now)
+}
+
+func (d *RavenDBDatabase) SetConditionFalse(t DatabaseConditionType, reason DatabaseConditionReason, msg string, now metav1.Time) {
+	d.setCondition(t, metav1.ConditionFalse, reason, msg, now)
+}
+
+func (d *RavenDBDatabase) SetObservedGeneration(gen int64) {
+	d.Status.ObservedGeneration = gen
+}
+
+func (d *RavenDBDatabase) GetCondition(t DatabaseConditionType) (c *metav1.Condition, ok bool) {
+	for i := range d.Status.Conditions {
+		condition := &d.Status.Conditions[i]
+
+		if condition.Type == string(t) {
+			return condition, true
+		}
+	}
+	return nil, false
+}
+
+func (d *RavenDBDatabase) HasConditionTrue(t DatabaseConditionType) bool {
+	condition, exists := d.GetCondition(t)
+	if !exists {
+		return false
+	}
+	return condition.Status == metav1.ConditionTrue
+}
+
+// the deletion policy is defaulted by the API server, but objects created before
+// the default existed (or built in tests) may still carry an empty value
+func (d *RavenDBDatabase) EffectiveDeletionPolicy() DatabaseDeletionPolicy {
+	if d.Spec.DeletionPolicy == "" {
+		return DeletionPolicyRetain
+	}
+	return d.Spec.DeletionPolicy
+}
+
+// returns the replication factor we should ask RavenDB for
+func (d *RavenDBDatabase) EffectiveReplicationFactor() int {
+	if d.Spec.ReplicationFactor != nil {
+		return int(*d.Spec.ReplicationFactor)
+	}
+	if len(d.Spec.Nodes) > 0 {
+		return len(d.Spec.Nodes)
+	}
+	return 1
+}
+
+func (d *RavenDBDatabase) setCondition(t DatabaseConditionType, status metav1.ConditionStatus, reason DatabaseConditionReason, msg string, now metav1.Time) {
+	if reason == "" {
+		reason = DatabaseReasonCompleted
+	}
+
+	if c, ok := d.GetCondition(t); ok {
+
+		if c.Status != status {
+			c.LastTransitionTime = now
+		}
+
+		c.Status = status
+		c.Reason = string(reason)
+		c.Message = msg
+
+		return
+	}
+
+	d.Status.Conditions = append(d.Status.Conditions, metav1.Condition{
+		Type:               string(t),
+		Status:             status,
+		Reason:             string(reason),
+		Message:            msg,
+		LastTransitionTime: now,
+	})
+}
+
+func (d *RavenDBDatabase) ComputeReady(now metav1.Time) {
+
+	required := []DatabaseConditionType{
+		DatabaseConditionClusterAvailable,
+		DatabaseConditionCreated,
+		DatabaseConditionTopologyInSync,
+		DatabaseConditionSettingsApplied,
+	}
+
+	for i := 0; i < len(required); i++ {
+		conditionType := required[i]
+		condition, exists := d.GetCondition(conditionType)
+
+		if !exists {
+			d.setCondition(DatabaseConditionReady, metav1.ConditionFalse, DatabaseConditionReason(string(conditionType)), "", now)
+			d.Status.Message = string(conditionType) + " not satisfied"
+			return
+		}
+
+		if condition.Status != metav1.ConditionTrue {
+			d.setCondition(DatabaseConditionReady, metav1.ConditionFalse, DatabaseConditionReason(string(conditionType)), condition.Message, now)
+
+			if condition.Reason != "" {
+				d.Status.Message = condition.Reason + ": " + condition.Message
+			} else {
+				d.Status.Message = string(conditionType) + " not satisfied"
+			}
+			return
+		}
+	}
+
+	d.setCondition(DatabaseConditionReady, metav1.ConditionTrue, DatabaseReasonCompleted, "Database is ready", now)
+	d.Status.Message = "Database is ready"
+}
+
+func (d *RavenDBDatabase) UpdatePhaseFromConditions() {
+	switch {
+	case d.DeletionTimestamp != nil:
+		d.Status.Phase = DatabasePhaseDeleting
+
+	case d.HasConditionTrue(DatabaseConditionReady):
+		d.Status.Phase = DatabasePhaseReady
+
+	case hasDatabaseRequestFailure(d):
+		d.Status.Phase = DatabasePhaseError
+
+	default:
+		d.Status.Phase = DatabasePhasePending
+	}
+}
+
+func hasDatabaseRequestFailure(d *RavenDBDatabase) bool {
+	for i := range d.Status.Conditions {
+		c := d.Status.Conditions[i]
+		if c.Status != metav1.ConditionTrue &&
+			(c.Reason == string(DatabaseReasonRequestFailed) || c.Reason == string(DatabaseReasonInvalidTopology)) {
+			return true
+		}
+	}
+	return false
+}
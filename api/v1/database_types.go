@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterRef`
+// +kubebuilder:printcolumn:name="Database",type=string,JSONPath=`.spec.databaseName`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+type RavenDBDatabase struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RavenDBDatabaseSpec   `json:"spec,omitempty"`
+	Status RavenDBDatabaseStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+type RavenDBDatabaseList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RavenDBDatabase `json:"items"`
+}
+
+type RavenDBDatabaseSpec struct {
+	// name of the RavenDBCluster (same namespace) that hosts the database
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="clusterRef is immutable"
+	ClusterRef string `json:"clusterRef"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=128
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_\-\.]+$`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="databaseName is immutable"
+	DatabaseName string `json:"databaseName"`
+
+	// number of nodes holding a copy of the database.
+	// when nodes are pinned and this is empty, it defaults to len(nodes).
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	ReplicationFactor *int32 `json:"replicationFactor,omitempty"`
+
+	// explicit node tags that must host the database.
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	Nodes []string `json:"nodes,omitempty"`
+
+	// database level configuration (e.g. "Indexing.MapTimeoutInSec": "30")
+	// +kubebuilder:validation:Optional
+	Settings map[string]string `json:"settings,omitempty"`
+
+	// what happens to the RavenDB database when this resource is deleted
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +kubebuilder:default=Retain
+	DeletionPolicy DatabaseDeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+type RavenDBDatabaseStatus struct {
+	// +kubebuilder:validation:Enum=Pending;Ready;Error;Deleting
+	Phase              DatabasePhase      `json:"phase,omitempty"`
+	Message            string             `json:"message,omitempty"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	Members            []string           `json:"members,omitempty"`
+	Promotables        []string           `json:"promotables,omitempty"`
+	Rehabs             []string           `json:"rehabs,omitempty"`
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+}
@@ -1,5 +1,11 @@
 package v1
 
+import (
+	"fmt"
+
+	"ravendb-operator/pkg/webhook/adapter"
+)
+
 func (r *RavenDBCluster) GetImage() string {
 	return r.Spec.Image
 }
@@ -67,6 +73,10 @@ func (r *RavenDBCluster) GetNodeCertSecretRefs() []*string {
 	})
 }
 
+func (r *RavenDBCluster) GetNodeWatchers() []bool {
+	return mapNodes(r, func(n RavenDBNode) bool { return n.Watcher })
+}
+
 func (r *RavenDBCluster) IsExternalAccessSet() bool {
 	return r.Spec.ExternalAccessConfiguration != nil
 }
@@ -251,3 +261,77 @@ func (r *RavenDBCluster) GetClientCertSecretRef() string {
 func (r *RavenDBCluster) GetCACertSecretRef() *string {
 	return r.Spec.CACertSecretRef
 }
+
+func (r *RavenDBCluster) GetPodTemplates() []adapter.PodTemplate {
+	var out []adapter.PodTemplate
+	if r.Spec.PodTemplate != nil {
+		out = append(out, r.Spec.PodTemplate.toAdapter("spec.podTemplate"))
+	}
+	for _, n := range r.Spec.Nodes {
+		if n.PodTemplate != nil {
+			out = append(out, n.PodTemplate.toAdapter(fmt.Sprintf("spec.nodes[%s].podTemplate", n.Tag)))
+		}
+	}
+	return out
+}
+
+func (pt *PodTemplateSpec) toAdapter(path string) adapter.PodTemplate {
+	return adapter.PodTemplate{
+		Path:                      path,
+		Resources:                 pt.Resources,
+		NodeSelector:              pt.NodeSelector,
+		Tolerations:               pt.Tolerations,
+		TopologySpreadConstraints: pt.TopologySpreadConstraints,
+		PriorityClassName:         pt.PriorityClassName,
+		Labels:                    pt.Labels,
+		Annotations:               pt.Annotations,
+	}
+}
+
+// GetSidecars returns spec.sidecars followed by spec.initContainers
+func (r *RavenDBCluster) GetSidecars() []adapter.Container {
+	var out []adapter.Container
+	for i, s := range r.Spec.Sidecars {
+		out = append(out, s.toAdapter(fmt.Sprintf("spec.sidecars[%d]", i)))
+	}
+	for i, s := range r.Spec.InitContainers {
+		out = append(out, s.toAdapter(fmt.Sprintf("spec.initContainers[%d]", i)))
+	}
+	return out
+}
+
+func (s Sidecar) toAdapter(path string) adapter.Container {
+	return adapter.Container{
+		Path:         path,
+		Name:         s.Name,
+		Ports:        s.Ports,
+		VolumeMounts: s.VolumeMounts,
+	}
+}
+
+func (r *RavenDBCluster) GetUpgradeGates() []adapter.UpgradeGate {
+	if r.Spec.Upgrade == nil {
+		return nil
+	}
+	var out []adapter.UpgradeGate
+	for i, g := range r.Spec.Upgrade.Gates {
+		out = append(out, adapter.UpgradeGate{Path: fmt.Sprintf("spec.upgrade.gates[%d]", i), Name: g.Name, Params: g.Params})
+	}
+	return out
+}
+
+func (r *RavenDBCluster) IsLogsRavenSet() bool {
+	return r.Spec.StorageSpec.Logs != nil && r.Spec.StorageSpec.Logs.RavenDB != nil
+}
+
+func (r *RavenDBCluster) IsLogsAuditSet() bool {
+	return r.Spec.StorageSpec.Logs != nil && r.Spec.StorageSpec.Logs.Audit != nil
+}
+
+func (r *RavenDBCluster) IsNetworkPolicyEnabled() bool {
+	return r.Spec.NetworkPolicy != nil && r.Spec.NetworkPolicy.Enabled
+}
+
+func (r *RavenDBCluster) GetIngressControllerNamespace() string {
+	return r.EffectiveIngressControllerNamespace()
+}
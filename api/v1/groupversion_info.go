@@ -37,4 +37,5 @@ var (
 
 func init() {
 	SchemeBuilder.Register(&RavenDBCluster{}, &RavenDBClusterList{})
+	SchemeBuilder.Register(&RavenDBDatabase{}, &RavenDBDatabaseList{})
 }
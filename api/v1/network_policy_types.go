@@ -0,0 +1,40 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1
+
+import networkingv1 "k8s.io/api/networking/v1"
+
+// NetworkPolicySpec generates a NetworkPolicy for the node pods, for namespaces with default-deny policies.
+// it allows the nodes to talk to each other, the ingress controller or load balancer, the operator and
+// the listed clients, and any pod to reach the ports of the sidecars. egress isn't restricted.
+type NetworkPolicySpec struct {
+	// +kubebuilder:validation:Optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// the namespace the ingress controller runs in, by default the one its upstream chart uses:
+	// ingress-nginx, traefik or haproxy-controller. required for other ingress classes.
+	// +kubebuilder:validation:Optional
+	IngressControllerNamespace *string `json:"ingressControllerNamespace,omitempty"`
+
+	// the namespace of the operator, which calls the nodes for bootstrap, gates and health checks.
+	// defaults to ravendb-operator-system
+	// +kubebuilder:validation:Optional
+	OperatorNamespace *string `json:"operatorNamespace,omitempty"`
+
+	// more peers (namespaceSelector, podSelector or ipBlock) allowed to reach the nodes on both ports
+	// +kubebuilder:validation:Optional
+	Clients []networkingv1.NetworkPolicyPeer `json:"clients,omitempty"`
+}
@@ -34,6 +34,14 @@ type RavenDBNode struct {
 
 	// +kubebuilder:validation:Optional
 	CertSecretRef *string `json:"certSecretRef,omitempty"`
+
+	// join the node as a watcher instead of a full member (the first node is always a member)
+	// +kubebuilder:validation:Optional
+	Watcher bool `json:"watcher,omitempty"`
+
+	// merged over spec.podTemplate for this node's pod
+	// +kubebuilder:validation:Optional
+	PodTemplate *PodTemplateSpec `json:"podTemplate,omitempty"`
 }
 
 type RavenDBNodeStatusPhase string
@@ -43,6 +51,26 @@ const (
 	NodeStatusFailed  RavenDBNodeStatusPhase = "Failed"
 )
 
+type NodeJoinPhase string
+
+const (
+	NodeJoinPending        NodeJoinPhase = "Pending"
+	NodeJoinWaitingForNode NodeJoinPhase = "WaitingForNode"
+	NodeJoinJoining        NodeJoinPhase = "Joining"
+	NodeJoinJoined         NodeJoinPhase = "Joined"
+	NodeJoinFailed         NodeJoinPhase = "Failed"
+	// the node was part of the cluster and dropped out, see spec.topology.autoRejoin
+	NodeJoinLeft NodeJoinPhase = "Left"
+)
+
+type NodeClusterRole string
+
+const (
+	NodeRoleMember     NodeClusterRole = "Member"
+	NodeRolePromotable NodeClusterRole = "Promotable"
+	NodeRoleWatcher    NodeClusterRole = "Watcher"
+)
+
 type RavenDBNodeStatus struct {
 	Tag string `json:"tag"`
 
@@ -51,4 +79,20 @@ type RavenDBNodeStatus struct {
 	LastAttemptedImage string                 `json:"lastAttemptedImage,omitempty"`
 	LastError          string                 `json:"lastError,omitempty"`
 	LastAttemptTime    metav1.Time            `json:"lastAttemptTime,omitempty"`
+
+	// progress of joining the node to the RavenDB cluster
+	// +kubebuilder:validation:Enum=Pending;WaitingForNode;Joining;Joined;Failed;Left
+	JoinPhase NodeJoinPhase `json:"joinPhase,omitempty"`
+	// the role the node currently holds in the cluster topology
+	// +kubebuilder:validation:Enum=Member;Promotable;Watcher
+	Role        NodeClusterRole `json:"role,omitempty"`
+	JoinMessage string          `json:"joinMessage,omitempty"`
+}
+
+// NodeRemovalStatus is the node being taken out of the cluster after it was removed from spec.nodes
+type NodeRemovalStatus struct {
+	Tag string `json:"tag"`
+
+	// why the node can't leave the RavenDB cluster yet, empty when nothing blocks it
+	BlockedReason string `json:"blockedReason,omitempty"`
 }
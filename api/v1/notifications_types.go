@@ -0,0 +1,42 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+type NotificationSeverity string
+
+const (
+	NotificationSeverityInfo    NotificationSeverity = "Info"
+	NotificationSeverityWarning NotificationSeverity = "Warning"
+	NotificationSeverityError   NotificationSeverity = "Error"
+)
+
+type NotificationsSpec struct {
+	// watch the notification center of every node and turn new alerts (low disk space, license issues,
+	// out of memory...) into Events on the cluster and the node's StatefulSet
+	// +kubebuilder:validation:Optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// alerts below this severity are ignored (default Warning)
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Info;Warning;Error
+	MinSeverity NotificationSeverity `json:"minSeverity,omitempty"`
+
+	// at most this many alert Events per cluster and minute, the rest is dropped (default 10)
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	EventsPerMinute *int32 `json:"eventsPerMinute,omitempty"`
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// ProbesSpec tunes the probes of the RavenDB container. every probe calls /setup/alive on the https port,
+// an unset probe keeps its defaults.
+type ProbesSpec struct {
+	// +kubebuilder:validation:Optional
+	Startup *ProbeSpec `json:"startup,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Readiness *ProbeSpec `json:"readiness,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Liveness *ProbeSpec `json:"liveness,omitempty"`
+}
+
+// ProbeSpec overrides the timing of one probe, unset fields keep the defaults
+type ProbeSpec struct {
+	// don't set this probe on the container at all
+	// +kubebuilder:validation:Optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+}
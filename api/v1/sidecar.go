@@ -16,24 +16,40 @@ limitations under the License.
 
 package v1
 
-// import corev1 "k8s.io/api/core/v1"
+import corev1 "k8s.io/api/core/v1"
 
-// type Sidecar struct {
-// 	// +kubebuilder:validation:Required
-// 	Name string `json:"name"`
+// Sidecar is a container added to the pod of every RavenDB node, next to RavenDB (spec.sidecars)
+// or before it (spec.initContainers)
+type Sidecar struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
 
-// 	// +kubebuilder:validation:Required
-// 	Image string `json:"image"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
 
-// 	// +kubebuilder:validation:Optional
-// 	Command []string `json:"command,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
 
-// 	// +kubebuilder:validation:Optional
-// 	Env []corev1.EnvVar `json:"env,omitempty"`
+	// +kubebuilder:validation:Optional
+	Command []string `json:"command,omitempty"`
 
-// 	// +kubebuilder:validation:Optional
-// 	Ports []corev1.ContainerPort `json:"ports,omitempty"`
+	// +kubebuilder:validation:Optional
+	Args []string `json:"args,omitempty"`
 
-// 	// +kubebuilder:validation:Optional
-// 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
-// }
+	// +kubebuilder:validation:Optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Ports []corev1.ContainerPort `json:"ports,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// mounts of spec.storage.additionalVolumes or of the operator's ravendb-data, ravendb-logs,
+	// ravendb-audit and ravendb-cert volumes. the operator's volumes are always mounted read only.
+	// +kubebuilder:validation:Optional
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+}
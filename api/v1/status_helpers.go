@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -28,16 +30,24 @@ func (r *RavenDBCluster) SetConditionFalse(t ClusterConditionType, reason Cluste
 	r.setCondition(t, metav1.ConditionFalse, reason, msg, now)
 }
 
+func (r *RavenDBCluster) SetConditionUnknown(t ClusterConditionType, reason ClusterConditionReason, msg string, now metav1.Time) {
+	r.setCondition(t, metav1.ConditionUnknown, reason, msg, now)
+}
+
 func (r *RavenDBCluster) SetObservedGeneration(gen int64) {
 	r.Status.ObservedGeneration = gen
 }
 
+// IsBootstrapped goes by status.bootstrap, clusters bootstrapped by an older operator only have the condition
 func (r *RavenDBCluster) IsBootstrapped() bool {
+	if r.Status.Bootstrap != nil {
+		return r.Status.Bootstrap.Phase == BootstrapPhaseCompleted
+	}
 	return r.HasConditionTrue(ConditionBootstrapCompleted)
 }
 
 func (r *RavenDBCluster) SetBootstrapped(now metav1.Time) {
-	r.SetConditionTrue(ConditionBootstrapCompleted, ReasonCompleted, "Bootstrap job succeeded", now)
+	r.SetConditionTrue(ConditionBootstrapCompleted, ReasonCompleted, "cluster bootstrapped", now)
 }
 
 func (r *RavenDBCluster) GetCondition(t ClusterConditionType) (c *metav1.Condition, ok bool) {
@@ -64,6 +74,79 @@ func (r *RavenDBCluster) HasConditionTrue(t ClusterConditionType) bool {
 	return false
 }
 
+// an empty control (or no spec.upgrade at all) lets upgrades proceed
+func (r *RavenDBCluster) EffectiveUpgradeControl() UpgradeControl {
+	if r.Spec.Upgrade == nil || r.Spec.Upgrade.Control == "" {
+		return UpgradeControlProceed
+	}
+	return r.Spec.Upgrade.Control
+}
+
+func (r *RavenDBCluster) EffectiveRollbackPolicy() RollbackPolicy {
+	if r.Spec.Upgrade == nil || r.Spec.Upgrade.RollbackPolicy == "" {
+		return RollbackPolicyNone
+	}
+	return r.Spec.Upgrade.RollbackPolicy
+}
+
+const (
+	defaultTopologyCheckInterval = time.Minute
+	minTopologyCheckInterval     = 10 * time.Second
+)
+
+// EffectiveTopologyCheckInterval is spec.topology.checkInterval, defaulted and clamped
+func (r *RavenDBCluster) EffectiveTopologyCheckInterval() time.Duration {
+	if r.Spec.Topology == nil || r.Spec.Topology.CheckInterval == nil {
+		return defaultTopologyCheckInterval
+	}
+	if d := r.Spec.Topology.CheckInterval.Duration; d > minTopologyCheckInterval {
+		return d
+	}
+	return minTopologyCheckInterval
+}
+
+func (r *RavenDBCluster) TopologyAutoRejoin() bool {
+	return r.Spec.Topology != nil && r.Spec.Topology.AutoRejoin
+}
+
+const defaultNotificationEventsPerMinute = 10
+
+func (r *RavenDBCluster) NotificationsEnabled() bool {
+	return r.Spec.Notifications != nil && r.Spec.Notifications.Enabled
+}
+
+// EffectiveNotificationMinSeverity is spec.notifications.minSeverity, Warning when unset
+func (r *RavenDBCluster) EffectiveNotificationMinSeverity() NotificationSeverity {
+	if r.Spec.Notifications == nil || r.Spec.Notifications.MinSeverity == "" {
+		return NotificationSeverityWarning
+	}
+	return r.Spec.Notifications.MinSeverity
+}
+
+// EffectiveNotificationEventsPerMinute is spec.notifications.eventsPerMinute, defaulted
+func (r *RavenDBCluster) EffectiveNotificationEventsPerMinute() int {
+	if r.Spec.Notifications == nil || r.Spec.Notifications.EventsPerMinute == nil || *r.Spec.Notifications.EventsPerMinute < 1 {
+		return defaultNotificationEventsPerMinute
+	}
+	return int(*r.Spec.Notifications.EventsPerMinute)
+}
+
+// the namespaces the upstream charts of the supported ingress controllers install into
+var defaultIngressControllerNamespaces = map[string]string{
+	"nginx":   "ingress-nginx",
+	"traefik": "traefik",
+	"haproxy": "haproxy-controller",
+}
+
+// EffectiveIngressControllerNamespace is spec.networkPolicy.ingressControllerNamespace, or the namespace the
+// ingress class' controller installs into by default. empty when neither is known.
+func (r *RavenDBCluster) EffectiveIngressControllerNamespace() string {
+	if np := r.Spec.NetworkPolicy; np != nil && np.IngressControllerNamespace != nil && *np.IngressControllerNamespace != "" {
+		return *np.IngressControllerNamespace
+	}
+	return defaultIngressControllerNamespaces[r.GetIngressClassName()]
+}
+
 // to ensure we don’t accidentally pass an empty reason
 func reasonsanitize(reason ClusterConditionReason) ClusterConditionReason {
 	if reason == "" {
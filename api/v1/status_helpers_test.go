@@ -135,13 +135,13 @@ func Test_TL6_BootstrapFalse_OthersTrue(t *testing.T) {
 	setTrue(c, ConditionLicensesValid)
 	setTrue(c, ConditionStorageReady)
 	setTrue(c, ConditionNodesHealthy)
-	setFalse(c, ConditionBootstrapCompleted, ReasonBootstrapJobRunning, "bootstrap job still running")
+	setFalse(c, ConditionBootstrapCompleted, ReasonBootstrapInProgress, "step JoinMembers: join of node B requested")
 	setTrue(c, ConditionExternalAccessReady)
 
 	c.ComputeReady(now())
 	c.UpdatePhaseFromConditions()
 	assertReadyFalseWithReason(t, c, ConditionBootstrapCompleted)
-	require.Equal(t, "BootstrapJobRunning: bootstrap job still running", c.Status.Message)
+	require.Equal(t, "BootstrapInProgress: step JoinMembers: join of node B requested", c.Status.Message)
 	require.Equal(t, PhaseDeploying, c.Status.Phase)
 }
 
@@ -254,3 +254,42 @@ func Test_TL13_DegradedTrue_and_AllRequiredTrue(t *testing.T) {
 	require.Equal(t, metav1.ConditionTrue, ready.Status)
 	require.Equal(t, PhaseRunning, c.Status.Phase)
 }
+
+func Test_TL14_UpgradeControlDefaultsToProceed(t *testing.T) {
+	c := newCluster(false)
+	require.Equal(t, UpgradeControlProceed, c.EffectiveUpgradeControl())
+
+	c.Spec.Upgrade = &UpgradeSpec{}
+	require.Equal(t, UpgradeControlProceed, c.EffectiveUpgradeControl())
+
+	c.Spec.Upgrade.Control = UpgradeControlPause
+	require.Equal(t, UpgradeControlPause, c.EffectiveUpgradeControl())
+}
+
+func Test_TL15_IsBootstrappedFollowsBootstrapStatus(t *testing.T) {
+	c := newCluster(false)
+	require.False(t, c.IsBootstrapped())
+
+	// bootstrapped by an older operator - only the condition is there
+	setTrue(c, ConditionBootstrapCompleted)
+	require.True(t, c.IsBootstrapped())
+
+	c.Status.Bootstrap = &BootstrapStatus{Phase: BootstrapPhaseFailed, CurrentStep: BootstrapStepJoinMembers}
+	require.False(t, c.IsBootstrapped())
+
+	c.Status.Bootstrap.Phase = BootstrapPhaseCompleted
+	require.True(t, c.IsBootstrapped())
+}
+
+func Test_TL16_TopologyCheckIntervalIsDefaultedAndClamped(t *testing.T) {
+	c := newCluster(false)
+	require.Equal(t, time.Minute, c.EffectiveTopologyCheckInterval())
+	require.False(t, c.TopologyAutoRejoin())
+
+	c.Spec.Topology = &TopologySpec{CheckInterval: &metav1.Duration{Duration: 5 * time.Minute}, AutoRejoin: true}
+	require.Equal(t, 5*time.Minute, c.EffectiveTopologyCheckInterval())
+	require.True(t, c.TopologyAutoRejoin())
+
+	c.Spec.Topology.CheckInterval.Duration = time.Second
+	require.Equal(t, 10*time.Second, c.EffectiveTopologyCheckInterval())
+}
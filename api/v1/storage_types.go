@@ -30,6 +30,12 @@ type StorageSpec struct {
 
 	// +kubebuilder:validation:Optional
 	AdditionalVolumes *[]AdditionalVolume `json:"additionalVolumes,omitempty"`
+
+	// what happens to the PVCs of a node that is removed from spec.nodes (scale in)
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Retain;Delete
+	// +kubebuilder:default=Retain
+	PVCRetentionPolicy PVCRetentionPolicy `json:"pvcRetentionPolicy,omitempty"`
 }
 
 type VolumeSpec struct {
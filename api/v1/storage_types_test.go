@@ -131,6 +131,28 @@ func TestStorageLogsFieldValidation(t *testing.T) {
 	runSpecValidationTest(t, baseClusterForStorageTypesTest, testCases)
 }
 
+func TestStoragePVCRetentionPolicyValidation(t *testing.T) {
+	testCases := []SpecValidationCase{
+		{
+			Name: "pvc retention delete",
+			Modify: func(spec *RavenDBClusterSpec) {
+				spec.StorageSpec.PVCRetentionPolicy = PVCRetentionDelete
+			},
+			ExpectError: false,
+		},
+		{
+			Name: "pvc retention invalid",
+			Modify: func(spec *RavenDBClusterSpec) {
+				spec.StorageSpec.PVCRetentionPolicy = "Archive"
+			},
+			ExpectError: true,
+			ErrorParts:  []string{"spec.storage.pvcRetentionPolicy"},
+		},
+	}
+
+	runSpecValidationTest(t, baseClusterForStorageTypesTest, testCases)
+}
+
 func TestStorageAdditionalVolumesFieldValidation(t *testing.T) {
 	testCases := []SpecValidationCase{
 		{
@@ -0,0 +1,91 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+type TopologySpec struct {
+	// how often the Raft topology is compared with spec.nodes once the cluster is bootstrapped (default 1m, at least 10s)
+	// +kubebuilder:validation:Optional
+	CheckInterval *metav1.Duration `json:"checkInterval,omitempty"`
+
+	// add spec nodes that dropped out of the RavenDB cluster (e.g. removed by hand in the studio) back to it.
+	// by default such a node is only reported on the ClusterTopologyInSync condition.
+	// +kubebuilder:validation:Optional
+	AutoRejoin bool `json:"autoRejoin,omitempty"`
+}
+
+type TopologyDriftKind string
+
+const (
+	// a spec node is not part of the RavenDB cluster
+	TopologyDriftMissing TopologyDriftKind = "Missing"
+	// a spec node holds another role than the spec asks for (e.g. a member that was demoted to a watcher)
+	TopologyDriftRoleMismatch TopologyDriftKind = "RoleMismatch"
+	// a spec member is still a promotable, it didn't catch up with the cluster (yet)
+	TopologyDriftPromotable TopologyDriftKind = "Promotable"
+	// a node of the RavenDB cluster is not in spec.nodes
+	TopologyDriftUnexpected TopologyDriftKind = "Unexpected"
+)
+
+// TopologyStatus is the last comparison of the Raft topology with spec.nodes
+type TopologyStatus struct {
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+
+	// leader and term as seen on the last check
+	Leader string `json:"leader,omitempty"`
+	Term   int64  `json:"term,omitempty"`
+
+	// nodes the RavenDB cluster and the spec disagree about, empty when in sync
+	Drift []TopologyDrift `json:"drift,omitempty"`
+
+	// why the topology couldn't be read on the last check
+	Error string `json:"error,omitempty"`
+}
+
+type TopologyDrift struct {
+	Tag string `json:"tag"`
+
+	// +kubebuilder:validation:Enum=Missing;RoleMismatch;Promotable;Unexpected
+	Kind TopologyDriftKind `json:"kind"`
+
+	// role the spec asks for (empty for Unexpected)
+	// +kubebuilder:validation:Enum=Member;Watcher
+	ExpectedRole NodeClusterRole `json:"expectedRole,omitempty"`
+
+	// role in the Raft topology (empty for Missing)
+	// +kubebuilder:validation:Enum=Member;Promotable;Watcher
+	ActualRole NodeClusterRole `json:"actualRole,omitempty"`
+
+	// first check that saw this drift
+	Since metav1.Time `json:"since"`
+}
+
+// Describe says in a few words what is wrong with the node, e.g. "is a Watcher, spec wants a Member"
+func (d TopologyDrift) Describe() string {
+	switch d.Kind {
+	case TopologyDriftMissing:
+		return "is not part of the cluster, spec wants a " + string(d.ExpectedRole)
+	case TopologyDriftPromotable:
+		return "is still a Promotable, spec wants a " + string(d.ExpectedRole)
+	case TopologyDriftRoleMismatch:
+		return "is a " + string(d.ActualRole) + ", spec wants a " + string(d.ExpectedRole)
+	case TopologyDriftUnexpected:
+		return "is a " + string(d.ActualRole) + " of the cluster but not in spec.nodes"
+	}
+	return string(d.Kind)
+}
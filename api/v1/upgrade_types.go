@@ -0,0 +1,159 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+type UpgradeStep string
+
+const (
+	UpgradeStepPreGates    UpgradeStep = "PreGates"
+	UpgradeStepApplying    UpgradeStep = "Applying"
+	UpgradeStepGracePeriod UpgradeStep = "GracePeriod"
+	UpgradeStepPostGates   UpgradeStep = "PostGates"
+)
+
+type UpgradePhase string
+
+const (
+	UpgradePhaseRunning    UpgradePhase = "Running"
+	UpgradePhasePaused     UpgradePhase = "Paused"
+	UpgradePhaseAborted    UpgradePhase = "Aborted"
+	UpgradePhaseRolledBack UpgradePhase = "RolledBack"
+	UpgradePhaseCompleted  UpgradePhase = "Completed"
+)
+
+type UpgradeControl string
+
+const (
+	UpgradeControlProceed UpgradeControl = "Proceed"
+	UpgradeControlPause   UpgradeControl = "Pause"
+	UpgradeControlAbort   UpgradeControl = "Abort"
+)
+
+type UpgradeSpec struct {
+	// controls a rolling upgrade that is underway. it is checked between nodes and between gates:
+	// Pause stops before the next node (a node which image was already changed finishes first),
+	// Abort ends the rollout and leaves the remaining nodes on their current image,
+	// Proceed (default) resumes a paused rollout or allows a new one after an abort.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Proceed;Pause;Abort
+	Control UpgradeControl `json:"control,omitempty"`
+
+	// what happens when the post gates of a node fail or time out.
+	// Automatic puts the node back on its previous image and re-runs its gates, as long as both
+	// RavenDB versions share the data format (same major.minor). None (default) leaves the node as is.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=None;Automatic
+	RollbackPolicy RollbackPolicy `json:"rollbackPolicy,omitempty"`
+
+	// per gate overrides of the upgrade gates (see upgrade.GateKind). gates not listed keep their defaults.
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=name
+	Gates []UpgradeGateSpec `json:"gates,omitempty"`
+}
+
+type UpgradeGateSpec struct {
+	// gate kind, e.g. node_alive, cluster_connectivity, db_groups_available_excluding_target
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z0-9_]+$`
+	Name string `json:"name"`
+
+	// enables or disables the gate, unset keeps the gate's default
+	// +kubebuilder:validation:Optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// how long the gate may block before the node fails (e.g. "10m"), defaults to the pre/post wait of the phase
+	// +kubebuilder:validation:Optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// gate specific settings, e.g. thresholds
+	// +kubebuilder:validation:Optional
+	Params map[string]string `json:"params,omitempty"`
+}
+
+type UpgradeNodePhase string
+
+const (
+	UpgradeNodePending     UpgradeNodePhase = "Pending"
+	UpgradeNodeUpgrading   UpgradeNodePhase = "Upgrading"
+	UpgradeNodeCompleted   UpgradeNodePhase = "Completed"
+	UpgradeNodeFailed      UpgradeNodePhase = "Failed"
+	UpgradeNodeRollingBack UpgradeNodePhase = "RollingBack"
+	UpgradeNodeRolledBack  UpgradeNodePhase = "RolledBack"
+)
+
+type RollbackPolicy string
+
+const (
+	RollbackPolicyNone      RollbackPolicy = "None"
+	RollbackPolicyAutomatic RollbackPolicy = "Automatic"
+)
+
+// UpgradeStatus describes the current (or last) rolling upgrade and persists the state of the upgrade
+// state machine. every reconcile advances it by at most one gate check, so an upgrade survives operator restarts.
+type UpgradeStatus struct {
+	// +kubebuilder:validation:Enum=Running;Paused;Aborted;RolledBack;Completed
+	Phase UpgradePhase `json:"phase,omitempty"`
+
+	// spec.upgrade.control as last seen by the upgrader
+	// +kubebuilder:validation:Enum=Proceed;Pause;Abort
+	Control UpgradeControl `json:"control,omitempty"`
+
+	SourceImage    string       `json:"sourceImage,omitempty"`
+	TargetImage    string       `json:"targetImage,omitempty"`
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// generation of the cluster the rollout was planned (or rolled back) for.
+	// a rolled back rollout is retried once the spec changes.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// nodes in the order they are upgraded
+	Plan []UpgradeNodeProgress `json:"plan,omitempty"`
+
+	// tag of the node being upgraded now (empty between nodes)
+	CurrentNode string `json:"currentNode,omitempty"`
+
+	// +kubebuilder:validation:Enum=PreGates;Applying;GracePeriod;PostGates
+	Step UpgradeStep `json:"step,omitempty"`
+
+	// gate currently evaluated (see upgrade.GatePhase / upgrade.GateKind) and its last info message
+	GatePhase   string `json:"gatePhase,omitempty"`
+	GateKind    string `json:"gateKind,omitempty"`
+	GateMessage string `json:"gateMessage,omitempty"`
+
+	// number of failed checks of the current gate
+	Attempt int32 `json:"attempt,omitempty"`
+
+	// when the current gate (or grace period) started and when it gives up
+	GateStartTime *metav1.Time `json:"gateStartTime,omitempty"`
+	Deadline      *metav1.Time `json:"deadline,omitempty"`
+}
+
+type UpgradeNodeProgress struct {
+	Tag string `json:"tag"`
+
+	// image the node ran before the rollout, used for a rollback
+	FromImage string `json:"fromImage,omitempty"`
+
+	// +kubebuilder:validation:Enum=Pending;Upgrading;Completed;Failed;RollingBack;RolledBack
+	Phase          UpgradeNodePhase `json:"phase"`
+	StartTime      *metav1.Time     `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time     `json:"completionTime,omitempty"`
+}
@@ -38,6 +38,9 @@ func (r *RavenDBCluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	validator.Register(validator.NewNodeValidator(mgr.GetClient()))
 	validator.Register(validator.NewEaValidator(mgr.GetClient()))
 	validator.Register(validator.NewStorageValidator(mgr.GetClient()))
+	validator.Register(validator.NewNamingValidator(mgr.GetClient()))
+	validator.Register(validator.NewPodTemplateValidator(mgr.GetClient()))
+	validator.Register(validator.NewSidecarValidator(mgr.GetClient()))
 
 	return ctrl.NewWebhookManagedBy(mgr).For(r).Complete()
 }
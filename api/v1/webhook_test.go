@@ -18,14 +18,21 @@ package v1_test
 
 import (
 	"context"
+	"fmt"
 	"net/url"
+	"slices"
+	"sort"
 	"strings"
 	"testing"
 
 	v1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/naming"
 	"ravendb-operator/pkg/webhook/validator"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/stretchr/testify/require"
@@ -260,20 +267,26 @@ func TestGeneralValidatorValidateLicenseSecret(t *testing.T) {
 
 	t.Run("valid license secret", func(t *testing.T) {
 		cluster := baseClusterLetsEncrypt("valid-license")
-		errs := validator.ValidateLicenseSecret(v, ctx, cluster.GetLicenseSecretRef())
+		errs := validator.ValidateLicenseSecret(v, ctx, "ravendb", cluster.GetLicenseSecretRef())
 		require.Empty(t, errs)
 	})
 
 	t.Run("licnese secret missing", func(t *testing.T) {
-		errs := validator.ValidateLicenseSecret(v, ctx, "non-existing-secret")
+		errs := validator.ValidateLicenseSecret(v, ctx, "ravendb", "non-existing-secret")
 		require.NotEmpty(t, errs)
 		require.Contains(t, errs[0], "spec.licenseSecretRef: secret 'non-existing-secret' not found")
 	})
 
+	t.Run("license secret in another namespace", func(t *testing.T) {
+		errs := validator.ValidateLicenseSecret(v, ctx, "team-b", "license")
+		require.NotEmpty(t, errs)
+		require.Contains(t, errs[0], "spec.licenseSecretRef: secret 'license' not found in namespace 'team-b'")
+	})
+
 	t.Run("license secret with non-json key", func(t *testing.T) {
 		cluster := baseClusterLetsEncrypt("invalid-license")
 		cluster.Spec.LicenseSecretRef = "non-json-key-license"
-		errs := validator.ValidateLicenseSecret(v, ctx, cluster.GetLicenseSecretRef())
+		errs := validator.ValidateLicenseSecret(v, ctx, "ravendb", cluster.GetLicenseSecretRef())
 		require.NotEmpty(t, errs)
 		require.Contains(t, errs[0], "spec.licenseSecretRef: secret 'non-json-key-license' must contain a file ending with '.json'")
 	})
@@ -281,7 +294,7 @@ func TestGeneralValidatorValidateLicenseSecret(t *testing.T) {
 	t.Run("license secret with multiple keys", func(t *testing.T) {
 		cluster := baseClusterLetsEncrypt("invalid-license-multi-keys")
 		cluster.Spec.LicenseSecretRef = "invalid-license-multi-keys"
-		errs := validator.ValidateLicenseSecret(v, ctx, cluster.GetLicenseSecretRef())
+		errs := validator.ValidateLicenseSecret(v, ctx, "ravendb", cluster.GetLicenseSecretRef())
 		require.NotEmpty(t, errs)
 		require.Contains(t, errs[0], "spec.licenseSecretRef: secret 'invalid-license-multi-keys' must contain exactly one '.json' file")
 	})
@@ -327,7 +340,7 @@ func TestGeneralValidatorValidateClusterCertSecret(t *testing.T) {
 		cluster := baseClusterLetsEncrypt("invalid-license-multi-keys")
 		cert := "valid-cluster-cert"
 		cluster.Spec.ClusterCertSecretRef = &cert
-		errs := validator.ValidateClusterCertSecret(v, ctx, cluster.GetMode(), cluster.GetClusterCertsSecretRef())
+		errs := validator.ValidateClusterCertSecret(v, ctx, "ravendb", cluster.GetMode(), cluster.GetClusterCertsSecretRef())
 		require.NotEmpty(t, errs)
 		require.Contains(t, errs[0], "spec.clusterCertSecretRef must not be set when mode is LetsEncrypt")
 	})
@@ -336,7 +349,7 @@ func TestGeneralValidatorValidateClusterCertSecret(t *testing.T) {
 		cluster := baseCluster("missing-cert")
 		cluster.Spec.ClusterCertSecretRef = nil
 		cluster.Spec.Mode = "None"
-		errs := validator.ValidateClusterCertSecret(v, ctx, cluster.GetMode(), cluster.GetClusterCertsSecretRef())
+		errs := validator.ValidateClusterCertSecret(v, ctx, "ravendb", cluster.GetMode(), cluster.GetClusterCertsSecretRef())
 		require.NotEmpty(t, errs)
 		require.Contains(t, errs[0], "spec.clusterCertSecretRef is required when mode is None")
 	})
@@ -346,7 +359,7 @@ func TestGeneralValidatorValidateClusterCertSecret(t *testing.T) {
 		secret := "non-existent"
 		cluster.Spec.ClusterCertSecretRef = &secret
 		cluster.Spec.Mode = "None"
-		errs := validator.ValidateClusterCertSecret(v, ctx, cluster.GetMode(), cluster.GetClusterCertsSecretRef())
+		errs := validator.ValidateClusterCertSecret(v, ctx, "ravendb", cluster.GetMode(), cluster.GetClusterCertsSecretRef())
 		require.NotEmpty(t, errs)
 		require.Contains(t, errs[0], "spec.clusterCertSecretRef: secret 'non-existent' not found")
 	})
@@ -355,7 +368,7 @@ func TestGeneralValidatorValidateClusterCertSecret(t *testing.T) {
 		cluster := baseCluster("non-pfx")
 		secret := "non-pfx-cluster-cert"
 		cluster.Spec.ClusterCertSecretRef = &secret
-		errs := validator.ValidateClusterCertSecret(v, ctx, cluster.GetMode(), cluster.GetClusterCertsSecretRef())
+		errs := validator.ValidateClusterCertSecret(v, ctx, "ravendb", cluster.GetMode(), cluster.GetClusterCertsSecretRef())
 		require.NotEmpty(t, errs)
 		require.Contains(t, errs[0], "spec.clusterCertSecretRef: secret 'non-pfx-cluster-cert' must contain a file ending with '.pfx")
 	})
@@ -364,7 +377,7 @@ func TestGeneralValidatorValidateClusterCertSecret(t *testing.T) {
 		cluster := baseCluster("multi-key")
 		secret := "multi-key-cluster-cert"
 		cluster.Spec.ClusterCertSecretRef = &secret
-		errs := validator.ValidateClusterCertSecret(v, ctx, cluster.GetMode(), cluster.GetClusterCertsSecretRef())
+		errs := validator.ValidateClusterCertSecret(v, ctx, "ravendb", cluster.GetMode(), cluster.GetClusterCertsSecretRef())
 		require.NotEmpty(t, errs)
 		require.Contains(t, errs[0], "spec.clusterCertSecretRef: secret 'multi-key-cluster-cert' must contain exactly one '.pfx' file")
 	})
@@ -373,7 +386,7 @@ func TestGeneralValidatorValidateClusterCertSecret(t *testing.T) {
 		cluster := baseCluster("valid-cert")
 		secret := "valid-cluster-cert"
 		cluster.Spec.ClusterCertSecretRef = &secret
-		errs := validator.ValidateClusterCertSecret(v, ctx, cluster.GetMode(), cluster.GetClusterCertsSecretRef())
+		errs := validator.ValidateClusterCertSecret(v, ctx, "ravendb", cluster.GetMode(), cluster.GetClusterCertsSecretRef())
 		require.Empty(t, errs)
 	})
 }
@@ -493,6 +506,71 @@ func TestGeneralValidatorImmutableAfterCreation(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "spec.nodes[].publicServerUrlTcp is immutable after creation")
 	})
+
+	t.Run("appending a node is allowed", func(t *testing.T) {
+		old := baseClusterLetsEncrypt("scale-out")
+		new := baseClusterLetsEncrypt("scale-out")
+		new.Spec.Nodes = append(new.Spec.Nodes, v1.RavenDBNode{
+			Tag:                "C",
+			PublicServerUrl:    "https://c.example.com:443",
+			PublicServerUrlTcp: "tcp://c-tcp.example.com:443",
+			Watcher:            true,
+		})
+		err := v.ValidateUpdate(ctx, old, new)
+		require.NoError(t, err)
+	})
+
+	t.Run("inserting a node before existing ones is rejected", func(t *testing.T) {
+		old := baseClusterLetsEncrypt("scale-out-insert")
+		new := baseClusterLetsEncrypt("scale-out-insert")
+		new.Spec.Nodes = append([]v1.RavenDBNode{{
+			Tag:                "C",
+			PublicServerUrl:    "https://c.example.com:443",
+			PublicServerUrlTcp: "tcp://c-tcp.example.com:443",
+		}}, new.Spec.Nodes...)
+		err := v.ValidateUpdate(ctx, old, new)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "new nodes may only be appended")
+	})
+
+	t.Run("removing a node is allowed", func(t *testing.T) {
+		old := baseClusterLetsEncrypt("scale-in")
+		new := baseClusterLetsEncrypt("scale-in")
+		new.Spec.Nodes = new.Spec.Nodes[:1]
+		err := v.ValidateUpdate(ctx, old, new)
+		require.NoError(t, err)
+	})
+
+	t.Run("removing the first node is rejected", func(t *testing.T) {
+		old := baseClusterLetsEncrypt("scale-in-first")
+		new := baseClusterLetsEncrypt("scale-in-first")
+		new.Spec.Nodes = new.Spec.Nodes[1:]
+		err := v.ValidateUpdate(ctx, old, new)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "formed the cluster and can't be removed")
+	})
+
+	t.Run("watcher change on an existing node is rejected", func(t *testing.T) {
+		old := baseClusterLetsEncrypt("immutable-watcher")
+		new := baseClusterLetsEncrypt("immutable-watcher")
+		new.Spec.Nodes[1].Watcher = true
+		err := v.ValidateUpdate(ctx, old, new)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "spec.nodes[].watcher is immutable after creation")
+	})
+}
+
+func TestNodeValidatorFirstNodeIsMember(t *testing.T) {
+	t.Run("rejects watcher as first node", func(t *testing.T) {
+		errs := validator.ValidateFirstNodeIsMember([]bool{true, false})
+		require.Len(t, errs, 1)
+		require.Contains(t, errs[0], "spec.nodes[0].watcher")
+	})
+
+	t.Run("accepts watchers after the first node", func(t *testing.T) {
+		errs := validator.ValidateFirstNodeIsMember([]bool{false, true})
+		require.Empty(t, errs)
+	})
 }
 
 func TestNodeValidatorValidateNodesNotEmpty(t *testing.T) {
@@ -710,7 +788,7 @@ func TestValidateNodeCertSecret(t *testing.T) {
 		cluster.Spec.Nodes[0].CertSecretRef = nil
 		tag := cluster.Spec.Nodes[0].Tag
 		certRef := ""
-		errs := validator.ValidateNodeCertSecret(ctx, v, cluster.GetMode(), tag, certRef)
+		errs := validator.ValidateNodeCertSecret(ctx, v, "ravendb", cluster.GetMode(), tag, certRef)
 		require.NotEmpty(t, errs)
 		require.Contains(t, errs[0], "is required when mode is LetsEncrypt")
 	})
@@ -724,7 +802,7 @@ func TestValidateNodeCertSecret(t *testing.T) {
 		if certRefPtr != nil {
 			certRef = *certRefPtr
 		}
-		errs := validator.ValidateNodeCertSecret(ctx, v, cluster.GetMode(), tag, certRef)
+		errs := validator.ValidateNodeCertSecret(ctx, v, "ravendb", cluster.GetMode(), tag, certRef)
 		require.NotEmpty(t, errs)
 		require.Contains(t, errs[0], "must not be set when mode is None")
 	})
@@ -735,7 +813,7 @@ func TestValidateNodeCertSecret(t *testing.T) {
 		cluster.Spec.Nodes[0].CertSecretRef = &secret
 		tag := cluster.Spec.Nodes[0].Tag
 		certRef := *cluster.Spec.Nodes[0].CertSecretRef
-		errs := validator.ValidateNodeCertSecret(ctx, v, cluster.GetMode(), tag, certRef)
+		errs := validator.ValidateNodeCertSecret(ctx, v, "ravendb", cluster.GetMode(), tag, certRef)
 		require.NotEmpty(t, errs)
 		require.Contains(t, errs[0], "secret 'non-existent' not found")
 	})
@@ -746,7 +824,7 @@ func TestValidateNodeCertSecret(t *testing.T) {
 		cluster.Spec.Nodes[0].CertSecretRef = &secret
 		tag := cluster.Spec.Nodes[0].Tag
 		certRef := *cluster.Spec.Nodes[0].CertSecretRef
-		errs := validator.ValidateNodeCertSecret(ctx, v, cluster.GetMode(), tag, certRef)
+		errs := validator.ValidateNodeCertSecret(ctx, v, "ravendb", cluster.GetMode(), tag, certRef)
 		require.NotEmpty(t, errs)
 		require.Contains(t, errs[0], "file 'cert.pem' must end with .pfx")
 	})
@@ -757,7 +835,7 @@ func TestValidateNodeCertSecret(t *testing.T) {
 		cluster.Spec.Nodes[0].CertSecretRef = &secret
 		tag := cluster.Spec.Nodes[0].Tag
 		certRef := *cluster.Spec.Nodes[0].CertSecretRef
-		errs := validator.ValidateNodeCertSecret(ctx, v, cluster.GetMode(), tag, certRef)
+		errs := validator.ValidateNodeCertSecret(ctx, v, "ravendb", cluster.GetMode(), tag, certRef)
 		require.NotEmpty(t, errs)
 		require.Contains(t, errs[0], "must contain exactly one .pfx file")
 	})
@@ -768,7 +846,7 @@ func TestValidateNodeCertSecret(t *testing.T) {
 		cluster.Spec.Nodes[0].CertSecretRef = &secret
 		tag := cluster.Spec.Nodes[0].Tag
 		certRef := *cluster.Spec.Nodes[0].CertSecretRef
-		errs := validator.ValidateNodeCertSecret(ctx, v, cluster.GetMode(), tag, certRef)
+		errs := validator.ValidateNodeCertSecret(ctx, v, "ravendb", cluster.GetMode(), tag, certRef)
 		require.Empty(t, errs)
 	})
 }
@@ -960,6 +1038,23 @@ func TestEAValidator(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "spec.externalAccessConfiguration.type has invalid value: 'bagira'")
 	})
+
+	t.Run("requires the ingress controller namespace of an unknown class with a network policy", func(t *testing.T) {
+		cluster := baseCluster("np-ingress")
+		cluster.Spec.ExternalAccessConfiguration = &v1.ExternalAccessConfiguration{
+			Type:                            "ingress-controller",
+			IngressControllerExternalAccess: &v1.IngressControllerContext{IngressClassName: "contour"},
+		}
+		require.NoError(t, v.ValidateCreate(ctx, cluster))
+
+		cluster.Spec.NetworkPolicy = &v1.NetworkPolicySpec{Enabled: true}
+		err := v.ValidateCreate(ctx, cluster)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "spec.networkPolicy.ingressControllerNamespace is required for ingress class 'contour'")
+
+		cluster.Spec.NetworkPolicy.IngressControllerNamespace = ptr("projectcontour")
+		require.NoError(t, v.ValidateCreate(ctx, cluster))
+	})
 }
 
 func TestStorageValidatorValidateVolumeSpec(t *testing.T) {
@@ -1083,6 +1178,258 @@ func TestValidateAdditionalVolumes(t *testing.T) {
 	})
 }
 
+func TestNamingValidator(t *testing.T) {
+	ctx := context.Background()
+	controller := true
+	ownedBy := func(cluster string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{
+			APIVersion: "ravendb.ravendb.io/v1",
+			Kind:       "RavenDBCluster",
+			Name:       cluster,
+			Controller: &controller,
+		}}
+	}
+	client := fake.NewClientBuilder().
+		WithObjects(
+			&appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "taken-a", Namespace: "default"},
+			},
+			&appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "mine-a", Namespace: "default", OwnerReferences: ownedBy("mine")},
+			},
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "other-cert-hook", Namespace: "default", OwnerReferences: ownedBy("another")},
+			},
+			&corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{Name: "app-node", Namespace: "default"},
+			},
+			&networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "default", OwnerReferences: ownedBy("shared")},
+				Spec: networkingv1.IngressSpec{
+					Rules: []networkingv1.IngressRule{{Host: "A.example.com"}},
+				},
+			},
+		).Build()
+	v := validator.NewNamingValidator(client)
+
+	t.Run("accepts a cluster with free names", func(t *testing.T) {
+		require.NoError(t, v.ValidateCreate(ctx, baseCluster("fresh")))
+	})
+
+	t.Run("accepts children this cluster controls", func(t *testing.T) {
+		require.NoError(t, v.ValidateUpdate(ctx, baseCluster("mine"), baseCluster("mine")))
+	})
+
+	t.Run("rejects a StatefulSet that belongs to something else", func(t *testing.T) {
+		err := v.ValidateCreate(ctx, baseCluster("taken"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "StatefulSet 'taken-a' already exists in namespace 'default' and doesn't belong to this cluster")
+	})
+
+	t.Run("rejects a ConfigMap of another cluster", func(t *testing.T) {
+		err := v.ValidateCreate(ctx, baseCluster("other"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "ConfigMap 'other-cert-hook' already exists")
+	})
+
+	t.Run("rejects a ServiceAccount the cluster's pods would run as", func(t *testing.T) {
+		err := v.ValidateCreate(ctx, baseCluster("app"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "ServiceAccount 'app-node' already exists")
+	})
+
+	t.Run("rejects names that are too long", func(t *testing.T) {
+		err := v.ValidateCreate(ctx, baseCluster(strings.Repeat("x", 51)))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "must be no more than 52 characters")
+	})
+
+	t.Run("rejects a cluster name that isn't a valid service name", func(t *testing.T) {
+		err := v.ValidateCreate(ctx, baseCluster("1cluster"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Service name '1cluster-a' for node 'A' is invalid")
+	})
+
+	t.Run("keeps legacy names for clusters marked with the annotation", func(t *testing.T) {
+		cluster := baseCluster("legacy")
+		cluster.Annotations = map[string]string{naming.LegacyNamesAnnotation: "true"}
+		errs := validator.ValidateChildNames(cluster)
+		require.Empty(t, errs)
+		require.Equal(t, "ravendb-a", naming.For(cluster).Node("A"))
+	})
+
+	t.Run("rejects hosts another ingress routes", func(t *testing.T) {
+		cluster := baseCluster("second")
+		cluster.Spec.ExternalAccessConfiguration = &v1.ExternalAccessConfiguration{
+			Type: v1.ExternalAccessType("ingress-controller"),
+			IngressControllerExternalAccess: &v1.IngressControllerContext{
+				IngressClassName: "nginx",
+			},
+		}
+		err := v.ValidateCreate(ctx, cluster)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "host 'A.example.com' is already routed by ingress 'shared'")
+	})
+}
+
+func TestPodTemplateValidator(t *testing.T) {
+	ctx := context.Background()
+	v := validator.NewPodTemplateValidator(fake.NewClientBuilder().Build())
+
+	t.Run("accepts a cluster and node template", func(t *testing.T) {
+		cluster := baseCluster("pod-template")
+		cluster.Spec.PodTemplate = &v1.PodTemplateSpec{
+			Resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")},
+			},
+			Tolerations:       []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "ravendb", Effect: corev1.TaintEffectNoSchedule}},
+			PriorityClassName: "ravendb-critical",
+			Labels:            map[string]string{"team": "db"},
+			Annotations:       map[string]string{"prometheus.io/scrape": "true"},
+		}
+		cluster.Spec.Nodes[0].PodTemplate = &v1.PodTemplateSpec{NodeSelector: map[string]string{"disk": "nvme"}}
+		require.NoError(t, v.ValidateCreate(ctx, cluster))
+	})
+
+	t.Run("rejects overriding the operator labels", func(t *testing.T) {
+		cluster := baseCluster("reserved-label")
+		cluster.Spec.Nodes[0].PodTemplate = &v1.PodTemplateSpec{Labels: map[string]string{"nodeTag": "B"}}
+		err := v.ValidateCreate(ctx, cluster)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "spec.nodes[A].podTemplate.labels: 'nodeTag' is set by the operator")
+	})
+
+	t.Run("rejects requests above limits", func(t *testing.T) {
+		cluster := baseCluster("request-over-limit")
+		cluster.Spec.PodTemplate = &v1.PodTemplateSpec{
+			Resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+			},
+		}
+		err := v.ValidateCreate(ctx, cluster)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "spec.podTemplate.resources: cpu request 4 must not exceed the limit 2")
+	})
+
+	t.Run("rejects an Exists toleration with a value", func(t *testing.T) {
+		errs := validator.ValidateTolerations("spec.podTemplate", []corev1.Toleration{{Key: "k", Operator: corev1.TolerationOpExists, Value: "v"}})
+		require.Equal(t, []string{"spec.podTemplate.tolerations[0]: value must be empty when operator is Exists"}, errs)
+	})
+
+	t.Run("rejects incomplete topology spread constraints", func(t *testing.T) {
+		errs := validator.ValidateTopologySpreadConstraints("spec.podTemplate", []corev1.TopologySpreadConstraint{{}})
+		require.Len(t, errs, 4)
+	})
+}
+
+func TestSidecarValidator(t *testing.T) {
+	ctx := context.Background()
+	v := validator.NewSidecarValidator(fake.NewClientBuilder().Build())
+
+	t.Run("accepts a log shipper mounting the data and cert volumes", func(t *testing.T) {
+		cluster := baseCluster("sidecar")
+		cluster.Spec.Sidecars = []v1.Sidecar{{
+			Name:  "exporter",
+			Image: "example/exporter:1.0",
+			Ports: []corev1.ContainerPort{{Name: "metrics", ContainerPort: 9100}},
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: "ravendb-data", MountPath: "/data"},
+				{Name: "ravendb-cert", MountPath: "/certs"},
+			},
+		}}
+		cluster.Spec.InitContainers = []v1.Sidecar{{Name: "init", Image: "busybox:1.36"}}
+		require.NoError(t, v.ValidateCreate(ctx, cluster))
+	})
+
+	t.Run("rejects the RavenDB container name and duplicates", func(t *testing.T) {
+		cluster := baseCluster("sidecar-names")
+		cluster.Spec.Sidecars = []v1.Sidecar{{Name: "ravendb", Image: "x:1"}, {Name: "shipper", Image: "x:1"}}
+		cluster.Spec.InitContainers = []v1.Sidecar{{Name: "shipper", Image: "x:1"}}
+		err := v.ValidateCreate(ctx, cluster)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "spec.sidecars[0].name: 'ravendb' is the RavenDB container")
+		require.Contains(t, err.Error(), "spec.initContainers[0].name: 'shipper' is already used by spec.sidecars[1]")
+	})
+
+	t.Run("rejects ports of the RavenDB container", func(t *testing.T) {
+		cluster := baseCluster("sidecar-ports")
+		cluster.Spec.Sidecars = []v1.Sidecar{{
+			Name:  "proxy",
+			Image: "x:1",
+			Ports: []corev1.ContainerPort{{Name: "https", ContainerPort: 8443}, {ContainerPort: 38888}},
+		}}
+		err := v.ValidateCreate(ctx, cluster)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "spec.sidecars[0].ports[0].name: 'https' is a port of the RavenDB container")
+		require.Contains(t, err.Error(), "spec.sidecars[0].ports[1].containerPort: 38888 is a port of the RavenDB container")
+	})
+
+	t.Run("rejects unknown and private volumes", func(t *testing.T) {
+		cluster := baseCluster("sidecar-volumes")
+		cluster.Spec.Sidecars = []v1.Sidecar{{
+			Name:  "shipper",
+			Image: "x:1",
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: "ravendb-logs", MountPath: "/logs"},
+				{Name: "ravendb-license", MountPath: "/license"},
+			},
+		}}
+		err := v.ValidateCreate(ctx, cluster)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "volume 'ravendb-logs' doesn't exist")
+		require.Contains(t, err.Error(), "volume 'ravendb-license' can't be mounted by a sidecar")
+	})
+}
+
+// gateCatalog stands in for pkg/upgrade's GateRegistry, which imports this package
+type gateCatalog map[string][]string
+
+func (c gateCatalog) GateNames() []string {
+	names := make([]string, 0, len(c))
+	for n := range c {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (c gateCatalog) ValidateGateParams(name string, params map[string]string) error {
+	for k := range params {
+		if !slices.Contains(c[name], k) {
+			return fmt.Errorf("unknown param %s", k)
+		}
+	}
+	return nil
+}
+
+func TestUpgradeGateValidator(t *testing.T) {
+	ctx := context.Background()
+	v := validator.NewUpgradeGateValidator(gateCatalog{"node_alive": nil, "replication_lag": {"maxLag"}})
+
+	t.Run("accepts known gates and params", func(t *testing.T) {
+		cluster := baseCluster("gates")
+		cluster.Spec.Upgrade = &v1.UpgradeSpec{Gates: []v1.UpgradeGateSpec{
+			{Name: "node_alive"},
+			{Name: "replication_lag", Params: map[string]string{"maxLag": "500"}},
+		}}
+		require.NoError(t, v.ValidateCreate(ctx, cluster))
+	})
+
+	t.Run("rejects unknown gates and params", func(t *testing.T) {
+		cluster := baseCluster("gates-unknown")
+		cluster.Spec.Upgrade = &v1.UpgradeSpec{Gates: []v1.UpgradeGateSpec{
+			{Name: "node_alvie"},
+			{Name: "replication_lag", Params: map[string]string{"max_lag": "500"}},
+		}}
+		err := v.ValidateCreate(ctx, cluster)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "spec.upgrade.gates[0].name: unknown gate 'node_alvie', known gates are node_alive, replication_lag")
+		require.Contains(t, err.Error(), "spec.upgrade.gates[1].params: unknown param max_lag")
+	})
+}
+
 // TODO: add client and ca certs tests.
 
 func ptr(s string) *string { return &s }
@@ -37,7 +37,10 @@ import (
 
 	ravendbv1 "ravendb-operator/api/v1"
 	"ravendb-operator/internal/controller"
+	"ravendb-operator/pkg/common"
 	"ravendb-operator/pkg/director"
+	"ravendb-operator/pkg/upgrade"
+	"ravendb-operator/pkg/webhook/validator"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -70,6 +73,8 @@ func main() {
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&common.ClusterDomain, "cluster-domain", common.ClusterDomain,
+		"The DNS domain of the Kubernetes cluster, used for the nodes' internal service names.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -153,7 +158,16 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "RavenDBCluster")
 		os.Exit(1)
 	}
+	if err = (&controller.RavenDBDatabaseReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RavenDBDatabase")
+		os.Exit(1)
+	}
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		// the API package can't import the gates, see validator.GateCatalog
+		validator.Register(validator.NewUpgradeGateValidator(upgrade.DefaultGateRegistry()))
 		if err = (&ravendbv1.RavenDBCluster{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "RavenDBCluster")
 			os.Exit(1)
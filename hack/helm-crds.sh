@@ -0,0 +1,28 @@
+#!/usr/bin/env bash
+# Copies the CRDs generated into config/crd/bases into the Helm chart templates, see `make helm-crds`.
+# the chart installs them unless crds.enabled=false and labels them like the rest of the release.
+set -euo pipefail
+
+cd "$(dirname "$0")/.."
+
+for src in config/crd/bases/ravendb.ravendb.io_*.yaml; do
+  plural=${src##*_}
+  dst=helm/chart/templates/${plural%s.yaml}-crd.yaml
+  {
+    echo '{{- $crds := .Values.crds | default (dict "enabled" true) }}'
+    echo '{{- if $crds.enabled }}'
+    awk '
+      NR == 1 && $0 == "---" { next }
+      { print }
+      !labeled && /^  name: / {
+        print "  labels:"
+        print "    app.kubernetes.io/name: ravendb-operator"
+        print "    app.kubernetes.io/instance: {{ .Release.Name }}"
+        print "    app.kubernetes.io/version: {{ .Chart.AppVersion | quote }}"
+        print "    app.kubernetes.io/managed-by: {{ .Release.Service }}"
+        labeled = 1
+      }
+    ' "$src"
+    echo '{{- end }}'
+  } > "$dst"
+done
@@ -19,9 +19,15 @@ package controller
 import (
 	"context"
 	"reflect"
+	"time"
 
 	"ravendb-operator/pkg/common"
 	"ravendb-operator/pkg/director"
+	"ravendb-operator/pkg/membership"
+	"ravendb-operator/pkg/metrics"
+	"ravendb-operator/pkg/naming"
+	"ravendb-operator/pkg/notifications"
+	"ravendb-operator/pkg/ravendb"
 	"ravendb-operator/pkg/upgrade"
 
 	ravendbv1 "ravendb-operator/api/v1"
@@ -29,9 +35,10 @@ import (
 
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
-	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -64,7 +71,7 @@ Cast
 ---
 - RavenDBCluster: the single source of truth for desired state.
 - Director: a coordinator. It tells actors what to create/update.
-- Actors: small workers that own one thing (StatefulSet, Service, Ingress, Job).
+- Actors: small workers that own one thing (StatefulSet, Service, Ingress, ConfigMap).
 - Health collector + evaluator: responsiable to ask "what's actually happening?" based on the answer -> compute conditions and phase.
 
 
@@ -75,6 +82,18 @@ Step by step (detailed flow)
    - If it doesn't exist: we are done (CR deleted !!).
    - else: Keep a copy of the previous Status/Conditions so we can detect changes and emit events.
 
+1.25) child names
+   - StatefulSets, Services, the Ingress and the hook ConfigMaps are named after the cluster
+     ("<cluster>-<tag>", "<cluster>-cert-hook", ...), so several clusters can share a namespace.
+   - a cluster created before that keeps its fixed names ("ravendb-<tag>", ...) - renaming a StatefulSet means
+     new, empty PVCs. we recognize it by a StatefulSet which selector doesn't have the instance label and mark
+     it with the legacy-names annotation once.
+
+1.5) remove nodes (scale in)
+   - StatefulSets we own whose tag is no longer in spec.nodes belong to removed nodes.
+   - the remover waits until every database on such a node has another healthy replica, removes the node
+     from the RavenDB cluster, and only then deletes its StatefulSet, Service and (per policy) PVCs.
+
 2) build + apply desired objects (via director + actors)
   - The director runs:
        - per cluster actors (e.g ingress) when they should act.
@@ -90,15 +109,49 @@ Step by step (detailed flow)
    - if there's a clash on a field we own, our controller wins (with ForceOwnership).
    - this avoids the "last write wins" problem and reduces conflicts.
 
+2.25) rolling upgrade tick
+   - the upgrader is a state machine persisted in status.upgrade (current node, step, gate, attempt, deadline).
+   - every reconcile checks the current gate once and returns RequeueAfter instead of sleeping,
+     so the worker is free for other clusters and the upgrade continues after an operator restart.
+
+2.4) bootstrap
+   - the bootstrapper forms the RavenDB cluster once: it waits for every node to answer /setup/alive,
+     registers the operator's client certificate on the first member (authenticated with that node's
+     server certificate) and adds the other members and then the watchers through the leader.
+   - it is a step list persisted in status.bootstrap. every step checks first whether its work is already
+     done, so a failed step is simply retried and an operator restart resumes where it stopped.
+
+2.5) join new nodes
+   - once the cluster is bootstrapped, nodes appended to spec.nodes (scale out) are created by the
+     per node actors like any other node, and then the joiner adds them to the RavenDB cluster
+     (PUT /admin/cluster/node on the leader) as a member or watcher.
+   - join progress is kept in status.nodes[].joinPhase/role and we requeue until every node joined.
+   - a node that had joined and is no longer part of the cluster (removed by hand) is marked Left, and only
+     added back with spec.topology.autoRejoin.
+
+2.6) topology drift
+   - once bootstrapped, the topology checker reads /cluster/topology every spec.topology.checkInterval
+     (default 1m, we requeue for it) and compares members and watchers with spec.nodes.
+   - the differences (missing nodes, wrong roles, stuck promotables, nodes not in the spec) are kept in
+     status.topology and reported on the ClusterTopologyInSync condition.
+
+2.7) notification center alerts
+   - with spec.notifications.enabled the alerts watcher (a manager runnable) keeps a subscription to the
+     notification center of every node and turns new alerts into Events on the cluster and the node's
+     StatefulSet, deduplicated and rate limited per cluster. the reconcile only tells it which nodes to watch.
+
 3) observe reality
-   - the collector lists what's in the cluster that we own (StatefulSets, Jobs, Services,
+   - the collector lists what's in the cluster that we own (StatefulSets, Services,
      Ingresses, Pods, PVCs) plus relevant Secrets.
    - it translates raw K8s objects into simple "facts" (names, phases, ready flags, etc.).
+   - once bootstrapped, every node is also asked over HTTPS what clients would see: /setup/alive,
+     its own view of the cluster (leader, role) and its server version, and /databases is read into a
+     per-database summary kept in status.databases.
 
 4) work out health and phase
    - the evaluator looks at the facts and sets conditions like:
      StorageReady, CertificatesReady, LicensesValid, NodesHealthy, ExternalAccessReady
-     (if configured), BootstrapCompleted, Progressing, Degraded.
+     (if configured), BootstrapCompleted, ClusterTopologyInSync, DatabasesHealthy, Progressing, Degraded.
    - then we roll them up into a single Phase
        Ready -> Running
        else if Degraded -> Error
@@ -115,14 +168,23 @@ Step by step (detailed flow)
 
 */
 
+// how often we come back while a node is still joining or leaving the RavenDB cluster
+const membershipRequeueInterval = 10 * time.Second
+
 // RavenDBClusterReconciler reconciles a RavenDBCluster object
 type RavenDBClusterReconciler struct {
 	client.Client
-	Scheme     *runtime.Scheme
-	Director   director.Director
-	Upgrader   upgrade.Upgrader
-	Recorder   record.EventRecorder
-	BaseTiming upgrade.Timing
+	Scheme       *runtime.Scheme
+	Director     director.Director
+	Upgrader     upgrade.Upgrader
+	Bootstrapper membership.Bootstrapper
+	Joiner       membership.Joiner
+	Topology     membership.TopologyChecker
+	Remover      membership.Remover
+	Alerts       notifications.Watcher
+	Probe        health.RavenDBCollector
+	Recorder     record.EventRecorder
+	BaseTiming   upgrade.Timing
 }
 
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch
@@ -133,30 +195,53 @@ type RavenDBClusterReconciler struct {
 // +kubebuilder:rbac:groups=apps,resources=statefulsets/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=batch,resources=jobs/status,verbs=get;update;patch
-// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;delete
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch;update
 // +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch;update
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
 func (r *RavenDBClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
 	var instance ravendbv1.RavenDBCluster
 	if err := r.Get(ctx, req.NamespacedName, &instance); err != nil {
 		if kerrors.IsNotFound(err) {
+			r.Alerts.Forget(req.NamespacedName)
+			r.Probe.Forget(req.NamespacedName)
+			metrics.Forget(req.Namespace, req.Name)
+			ravendb.ForgetCluster(req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, err
 	}
 
+	start := time.Now()
+	defer func() { metrics.ObserveReconcile(req.Namespace, req.Name, time.Since(start)) }()
+	failed := func(step string) { metrics.ReconcileError(req.Namespace, req.Name, step) }
+
 	original := instance.DeepCopy()
 	prevConditions := append([]metav1.Condition(nil), original.Status.Conditions...)
 
-	_, err := r.Director.ExecutePerCluster(ctx, &instance, r.Client, r.Scheme)
+	if err := r.keepLegacyNames(ctx, &instance); err != nil {
+		failed("legacy_names")
+		return ctrl.Result{}, err
+	}
+
+	removal, removalPending, err := r.Remover.Run(ctx, &instance, r.Client)
+	if err != nil {
+		logger.Error(err, "removing nodes from the cluster failed")
+		failed("remove_nodes")
+	}
+	instance.Status.NodeRemoval = removal
+
+	_, err = r.Director.ExecutePerCluster(ctx, &instance, r.Client, r.Scheme)
 	if err != nil {
 		logger.Error(err, "failed to execute cluster-level actors")
+		failed("cluster_actors")
 		return ctrl.Result{}, err
 	}
 
@@ -167,21 +252,60 @@ func (r *RavenDBClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 	r.Upgrader.SetTiming(upgrade.ReadTimingFromAnnotations(&instance, r.BaseTiming))
 
-	nodeStatuses, err := r.Upgrader.Run(ctx, &instance, r.Client, applyNode)
+	upgradeResult, err := r.Upgrader.Run(ctx, &instance, r.Client, applyNode)
 	if err != nil {
 		logger.Error(err, "rolling upgrade failed")
+		failed("upgrade")
 		if r.Recorder != nil {
 			r.Recorder.Eventf(&instance, corev1.EventTypeWarning, "RollingUpgradeFailed", "%v", err)
 		}
 	}
+	instance.Status.Upgrade = upgradeResult.Upgrade
+
+	bootstrap, bootstrapPending, err := r.Bootstrapper.Run(ctx, &instance, r.Client)
+	if err != nil {
+		logger.Error(err, "bootstrapping the cluster failed")
+		failed("bootstrap")
+	}
+	instance.Status.Bootstrap = bootstrap
+
+	nodeStatuses, joinPending, err := r.Joiner.Run(ctx, &instance, r.Client, upgradeResult.Nodes)
+	if err != nil {
+		logger.Error(err, "joining nodes to the cluster failed")
+		failed("join_nodes")
+	}
 	instance.Status.Nodes = nodeStatuses
 
+	topology, nextTopologyCheck, err := r.Topology.Run(ctx, &instance, r.Client)
+	if err != nil {
+		logger.Error(err, "checking the cluster topology failed")
+		failed("topology")
+	}
+	instance.Status.Topology = topology
+
+	r.Alerts.Sync(&instance)
+
+	result := ctrl.Result{RequeueAfter: soonest(upgradeResult.RequeueAfter, nextTopologyCheck)}
+	if bootstrapPending || joinPending || removalPending {
+		result.RequeueAfter = soonest(result.RequeueAfter, membershipRequeueInterval)
+	}
+
 	resFacts, err := health.NewResourceCollector().Collect(ctx, r.Client, &instance)
 	if err != nil {
 		logger.Error(err, "resource translation failed")
+		failed("collect_resources")
+	}
+	if resFacts != nil {
+		resFacts.RavenDB, err = r.Probe.Collect(ctx, r.Client, &instance)
+		if err != nil {
+			logger.Error(err, "probing the RavenDB nodes failed")
+			failed("probe_nodes")
+			resFacts.RavenDBError = err.Error()
+		}
 	}
 	ev := health.NewEvaluator()
 	ev.Evaluate(ctx, &instance, resFacts, metav1.Now())
+	metrics.ObserveCluster(&instance, resFacts)
 
 	statusChanged := !reflect.DeepEqual(original.Status, instance.Status)
 	if statusChanged {
@@ -189,12 +313,55 @@ func (r *RavenDBClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			if kerrors.IsConflict(err) {
 				return ctrl.Result{Requeue: true}, nil
 			}
+			failed("status_patch")
 			return ctrl.Result{}, err
 		}
 		emitConditionTransitions(&instance, prevConditions, logger, r.Recorder)
 	}
 
-	return ctrl.Result{}, nil
+	return result, nil
+}
+
+// keepLegacyNames marks a cluster whose StatefulSets were created with the fixed "ravendb-<tag>" names,
+// so the builders keep using them (see naming.LegacyNamesAnnotation)
+func (r *RavenDBClusterReconciler) keepLegacyNames(ctx context.Context, cluster *ravendbv1.RavenDBCluster) error {
+	if naming.For(cluster).Legacy() {
+		return nil
+	}
+
+	var list appsv1.StatefulSetList
+	if err := r.List(ctx, &list,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{common.LabelInstance: cluster.Name},
+	); err != nil {
+		return err
+	}
+
+	for i := range list.Items {
+		sts := &list.Items[i]
+		if !metav1.IsControlledBy(sts, cluster) || sts.Spec.Selector == nil {
+			continue
+		}
+		if _, ok := sts.Spec.Selector.MatchLabels[common.LabelInstance]; ok {
+			continue
+		}
+
+		patch := client.MergeFrom(cluster.DeepCopy())
+		if cluster.Annotations == nil {
+			cluster.Annotations = map[string]string{}
+		}
+		cluster.Annotations[naming.LegacyNamesAnnotation] = "true"
+		return r.Patch(ctx, cluster, patch)
+	}
+	return nil
+}
+
+// soonest returns the shorter non zero requeue interval
+func soonest(a, b time.Duration) time.Duration {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
 }
 
 func emitConditionTransitions(cluster *ravendbv1.RavenDBCluster, prevConditions []metav1.Condition, logger logr.Logger, rec record.EventRecorder) {
@@ -259,15 +426,28 @@ func (r *RavenDBClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.BaseTiming = timing
 
 	r.Upgrader.SetEmitter(upgrade.NewGateEventEmitter(r.Client, r.Recorder))
+	r.Bootstrapper = membership.NewBootstrapper(r.Recorder)
+	r.Joiner = membership.NewJoiner(r.Recorder)
+	r.Topology = membership.NewTopologyChecker(r.Recorder)
+	r.Remover = membership.NewRemover(r.Recorder)
+	r.Alerts = notifications.NewWatcher(r.Client, r.Recorder)
+	r.Probe = health.NewRavenDBCollector()
+	if err := mgr.Add(r.Alerts); err != nil {
+		return err
+	}
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&ravendbv1.RavenDBCluster{},
 			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
 		).
-		Owns(&batchv1.Job{}).
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&corev1.Service{}).
 		Owns(&networkingv1.Ingress{}).
+		Owns(&networkingv1.NetworkPolicy{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
+		Owns(&corev1.ServiceAccount{}).
+		Owns(&rbacv1.Role{}).
+		Owns(&rbacv1.RoleBinding{}).
 		Owns(&corev1.Pod{}).
 		Owns(&corev1.PersistentVolumeClaim{}).
 		Owns(&corev1.Secret{}).
@@ -0,0 +1,337 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"time"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/database"
+	"ravendb-operator/pkg/ravendb"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+/*
+The RavenDBDatabase Reconciliation flow
+
+Unlike the cluster, a database has no K8s children. the "real" object lives inside RavenDB,
+so every reconcile talks to the admin REST API of the referenced cluster (mTLS with the cluster client cert):
+
+1) load the RavenDBDatabase. if it's being deleted, honor spec.deletionPolicy and drop our finalizer.
+2) resolve spec.clusterRef and wait until the cluster finished bootstrapping.
+3) read the database record. if missing -> create it (pinned nodes / replication factor / settings).
+4) compare the database group with the spec and apply ONE topology change (add or remove a node).
+   RavenDB needs time to replicate to a new node, so we requeue instead of doing everything at once.
+5) push settings that differ from the record.
+6) persist status (group members + conditions + phase) and requeue for periodic drift detection.
+*/
+
+const (
+	databaseFinalizer     = "ravendb.ravendb.io/database-finalizer"
+	databaseResyncPeriod  = 1 * time.Minute
+	databaseRetryPeriod   = 15 * time.Second
+	databaseTopologyRetry = 5 * time.Second
+)
+
+// RavenDBDatabaseReconciler reconciles a RavenDBDatabase object
+type RavenDBDatabaseReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// builds the RavenDB client for a cluster (defaults to ravendb.NewForCluster)
+	NewAdminClient func(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster) (*ravendb.Client, error)
+}
+
+// +kubebuilder:rbac:groups=ravendb.ravendb.io,resources=ravendbdatabases,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ravendb.ravendb.io,resources=ravendbdatabases/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ravendb.ravendb.io,resources=ravendbdatabases/finalizers,verbs=update
+// +kubebuilder:rbac:groups=ravendb.ravendb.io,resources=ravendbclusters,verbs=get;list;watch
+func (r *RavenDBDatabaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var db ravendbv1.RavenDBDatabase
+	if err := r.Get(ctx, req.NamespacedName, &db); err != nil {
+		if kerrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if !db.DeletionTimestamp.IsZero() {
+		return r.finalize(ctx, &db)
+	}
+
+	if !controllerutil.ContainsFinalizer(&db, databaseFinalizer) {
+		controllerutil.AddFinalizer(&db, databaseFinalizer)
+		if err := r.Update(ctx, &db); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	original := db.DeepCopy()
+	result, syncErr := r.sync(ctx, &db)
+	if syncErr != nil {
+		logger.Error(syncErr, "database sync failed", "database", db.Spec.DatabaseName)
+	}
+
+	now := metav1.Now()
+	db.SetObservedGeneration(db.Generation)
+	db.ComputeReady(now)
+	db.UpdatePhaseFromConditions()
+
+	if !reflect.DeepEqual(original.Status, db.Status) {
+		if err := r.Status().Patch(ctx, &db, client.MergeFrom(original)); err != nil {
+			if kerrors.IsConflict(err) {
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, err
+		}
+		emitDatabaseConditionTransitions(&db, original.Status.Conditions, r.Recorder)
+	}
+
+	return result, nil
+}
+
+// sync drives the RavenDB side towards the spec and records the outcome as conditions.
+// errors are reported through conditions, so the returned error is informational only.
+func (r *RavenDBDatabaseReconciler) sync(ctx context.Context, db *ravendbv1.RavenDBDatabase) (ctrl.Result, error) {
+	now := metav1.Now()
+	name := db.Spec.DatabaseName
+
+	cluster, admin, res, err := r.resolveCluster(ctx, db, now)
+	if cluster == nil || admin == nil {
+		return res, err
+	}
+
+	rec, err := admin.DatabaseRecord(ctx, name)
+	if err != nil {
+		db.SetConditionFalse(ravendbv1.DatabaseConditionClusterAvailable, ravendbv1.DatabaseReasonRequestFailed, "get database record: "+err.Error(), now)
+		return ctrl.Result{RequeueAfter: databaseRetryPeriod}, err
+	}
+	db.SetConditionTrue(ravendbv1.DatabaseConditionClusterAvailable, ravendbv1.DatabaseReasonCompleted, "Cluster "+cluster.Name+" is reachable", now)
+
+	rf := db.EffectiveReplicationFactor()
+
+	if rec == nil {
+		if err := admin.CreateDatabase(ctx, database.NewRecord(name, rf, db.Spec.Nodes, db.Spec.Settings), rf); err != nil {
+			db.SetConditionFalse(ravendbv1.DatabaseConditionCreated, ravendbv1.DatabaseReasonRequestFailed, "create database: "+err.Error(), now)
+			return ctrl.Result{RequeueAfter: databaseRetryPeriod}, err
+		}
+		r.event(db, corev1.EventTypeNormal, "DatabaseCreated", "Created database %s on cluster %s", name, cluster.Name)
+
+		// read it back on the next tick, RavenDB needs a moment to assign the topology
+		db.SetConditionTrue(ravendbv1.DatabaseConditionCreated, ravendbv1.DatabaseReasonCompleted, "Database created", now)
+		db.SetConditionFalse(ravendbv1.DatabaseConditionTopologyInSync, ravendbv1.DatabaseReasonTopologyUpdating, "Waiting for database topology", now)
+		return ctrl.Result{RequeueAfter: databaseTopologyRetry}, nil
+	}
+	db.SetConditionTrue(ravendbv1.DatabaseConditionCreated, ravendbv1.DatabaseReasonCompleted, "Database exists", now)
+
+	topology := database.TopologyOf(rec)
+	db.Status.Members = topology.Members
+	db.Status.Promotables = topology.Promotables
+	db.Status.Rehabs = topology.Rehabs
+
+	result := ctrl.Result{RequeueAfter: databaseResyncPeriod}
+
+	change, err := database.PlanTopology(topology, db.Spec.Nodes, rf, clusterTags(cluster))
+	switch {
+	case err != nil:
+		db.SetConditionFalse(ravendbv1.DatabaseConditionTopologyInSync, ravendbv1.DatabaseReasonInvalidTopology, err.Error(), now)
+
+	case change.Add != "":
+		if err := admin.AddDatabaseNode(ctx, name, change.Add); err != nil {
+			db.SetConditionFalse(ravendbv1.DatabaseConditionTopologyInSync, ravendbv1.DatabaseReasonRequestFailed, "add node "+change.Add+": "+err.Error(), now)
+			return ctrl.Result{RequeueAfter: databaseRetryPeriod}, err
+		}
+		r.event(db, corev1.EventTypeNormal, "DatabaseNodeAdded", "Added node %s to database %s", change.Add, name)
+		db.SetConditionFalse(ravendbv1.DatabaseConditionTopologyInSync, ravendbv1.DatabaseReasonTopologyUpdating, "Adding node "+change.Add, now)
+		result.RequeueAfter = databaseTopologyRetry
+
+	case change.Remove != "":
+		// never drop a member while another node is still catching up
+		catchingUp := len(topology.Promotables) > 0 || len(topology.Rehabs) > 0
+		if catchingUp && slices.Contains(topology.Members, change.Remove) {
+			db.SetConditionFalse(ravendbv1.DatabaseConditionTopologyInSync, ravendbv1.DatabaseReasonTopologyUpdating, "Waiting for promotables/rehabs before removing node "+change.Remove, now)
+			result.RequeueAfter = databaseTopologyRetry
+			break
+		}
+		if err := admin.DeleteDatabase(ctx, name, change.Remove); err != nil {
+			db.SetConditionFalse(ravendbv1.DatabaseConditionTopologyInSync, ravendbv1.DatabaseReasonRequestFailed, "remove node "+change.Remove+": "+err.Error(), now)
+			return ctrl.Result{RequeueAfter: databaseRetryPeriod}, err
+		}
+		r.event(db, corev1.EventTypeNormal, "DatabaseNodeRemoved", "Removed node %s from database %s", change.Remove, name)
+		db.SetConditionFalse(ravendbv1.DatabaseConditionTopologyInSync, ravendbv1.DatabaseReasonTopologyUpdating, "Removing node "+change.Remove, now)
+		result.RequeueAfter = databaseTopologyRetry
+
+	case len(topology.Promotables) > 0 || len(topology.Rehabs) > 0:
+		db.SetConditionFalse(ravendbv1.DatabaseConditionTopologyInSync, ravendbv1.DatabaseReasonTopologyUpdating, "Waiting for nodes to become members", now)
+		result.RequeueAfter = databaseTopologyRetry
+
+	default:
+		db.SetConditionTrue(ravendbv1.DatabaseConditionTopologyInSync, ravendbv1.DatabaseReasonCompleted, "Database group matches spec", now)
+	}
+
+	if database.SettingsInSync(rec, db.Spec.Settings) {
+		db.SetConditionTrue(ravendbv1.DatabaseConditionSettingsApplied, ravendbv1.DatabaseReasonCompleted, "Settings applied", now)
+		return result, nil
+	}
+
+	if err := admin.PutDatabaseSettings(ctx, name, db.Spec.Settings); err != nil {
+		db.SetConditionFalse(ravendbv1.DatabaseConditionSettingsApplied, ravendbv1.DatabaseReasonRequestFailed, "put settings: "+err.Error(), now)
+		return ctrl.Result{RequeueAfter: databaseRetryPeriod}, err
+	}
+	r.event(db, corev1.EventTypeNormal, "DatabaseSettingsUpdated", "Updated settings of database %s", name)
+	db.SetConditionFalse(ravendbv1.DatabaseConditionSettingsApplied, ravendbv1.DatabaseReasonSettingsUpdating, "Settings sent, waiting for record to reflect them", now)
+	result.RequeueAfter = databaseTopologyRetry
+
+	return result, nil
+}
+
+// resolveCluster loads the referenced cluster and builds an admin client for it.
+// a nil cluster/client means we can't talk to RavenDB yet and the returned result should be used.
+func (r *RavenDBDatabaseReconciler) resolveCluster(ctx context.Context, db *ravendbv1.RavenDBDatabase, now metav1.Time) (*ravendbv1.RavenDBCluster, *ravendb.Client, ctrl.Result, error) {
+	var cluster ravendbv1.RavenDBCluster
+	if err := r.Get(ctx, client.ObjectKey{Namespace: db.Namespace, Name: db.Spec.ClusterRef}, &cluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			db.SetConditionFalse(ravendbv1.DatabaseConditionClusterAvailable, ravendbv1.DatabaseReasonClusterNotFound, "RavenDBCluster "+db.Spec.ClusterRef+" not found", now)
+			return nil, nil, ctrl.Result{RequeueAfter: databaseRetryPeriod}, nil
+		}
+		return nil, nil, ctrl.Result{}, err
+	}
+
+	if !cluster.IsBootstrapped() {
+		db.SetConditionFalse(ravendbv1.DatabaseConditionClusterAvailable, ravendbv1.DatabaseReasonClusterNotReady, "RavenDBCluster "+cluster.Name+" is not bootstrapped yet", now)
+		return nil, nil, ctrl.Result{RequeueAfter: databaseRetryPeriod}, nil
+	}
+
+	admin, err := r.NewAdminClient(ctx, r.Client, &cluster)
+	if err != nil {
+		db.SetConditionFalse(ravendbv1.DatabaseConditionClusterAvailable, ravendbv1.DatabaseReasonRequestFailed, "build admin client: "+err.Error(), now)
+		return nil, nil, ctrl.Result{RequeueAfter: databaseRetryPeriod}, err
+	}
+
+	return &cluster, admin, ctrl.Result{}, nil
+}
+
+// finalize honors spec.deletionPolicy before letting K8s remove the resource.
+// when the cluster itself is gone there is nothing left to delete.
+func (r *RavenDBDatabaseReconciler) finalize(ctx context.Context, db *ravendbv1.RavenDBDatabase) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(db, databaseFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if db.EffectiveDeletionPolicy() == ravendbv1.DeletionPolicyDelete {
+		var cluster ravendbv1.RavenDBCluster
+		err := r.Get(ctx, client.ObjectKey{Namespace: db.Namespace, Name: db.Spec.ClusterRef}, &cluster)
+		switch {
+		case kerrors.IsNotFound(err):
+			// cluster removed first - the database went with it
+		case err != nil:
+			return ctrl.Result{}, err
+		case cluster.DeletionTimestamp.IsZero():
+			admin, err := r.NewAdminClient(ctx, r.Client, &cluster)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if err := admin.DeleteDatabase(ctx, db.Spec.DatabaseName); err != nil {
+				r.event(db, corev1.EventTypeWarning, "DatabaseDeleteFailed", "Failed to delete database %s: %v", db.Spec.DatabaseName, err)
+				return ctrl.Result{RequeueAfter: databaseRetryPeriod}, nil
+			}
+			r.event(db, corev1.EventTypeNormal, "DatabaseDeleted", "Deleted database %s from cluster %s", db.Spec.DatabaseName, cluster.Name)
+		}
+	}
+
+	controllerutil.RemoveFinalizer(db, databaseFinalizer)
+	return ctrl.Result{}, r.Update(ctx, db)
+}
+
+func (r *RavenDBDatabaseReconciler) event(db *ravendbv1.RavenDBDatabase, eventType, reason, format string, args ...any) {
+	if r.Recorder == nil {
+		return
+	}
+	r.Recorder.Eventf(db, eventType, reason, format, args...)
+}
+
+func emitDatabaseConditionTransitions(db *ravendbv1.RavenDBDatabase, prevConditions []metav1.Condition, rec record.EventRecorder) {
+	if rec == nil {
+		return
+	}
+
+	previousByType := make(map[string]metav1.Condition, len(prevConditions))
+	for i := 0; i < len(prevConditions); i++ {
+		previousByType[prevConditions[i].Type] = prevConditions[i]
+	}
+
+	for i := 0; i < len(db.Status.Conditions); i++ {
+		cur := db.Status.Conditions[i]
+		previous, hadPrevious := previousByType[cur.Type]
+
+		if hadPrevious && previous.Status == cur.Status && previous.Reason == cur.Reason {
+			continue
+		}
+
+		eventType := corev1.EventTypeNormal
+		if cur.Status == metav1.ConditionFalse && cur.Reason == string(ravendbv1.DatabaseReasonRequestFailed) {
+			eventType = corev1.EventTypeWarning
+		}
+
+		rec.Eventf(db, eventType, cur.Reason,
+			"Condition %s changed to %s (reason=%s): %s",
+			cur.Type, cur.Status, cur.Reason, cur.Message,
+		)
+	}
+}
+
+func clusterTags(c *ravendbv1.RavenDBCluster) []string {
+	tags := make([]string, 0, len(c.Spec.Nodes))
+	for _, n := range c.Spec.Nodes {
+		tags = append(tags, n.Tag)
+	}
+	return tags
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RavenDBDatabaseReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Recorder = mgr.GetEventRecorderFor(common.Manager)
+	if r.NewAdminClient == nil {
+		r.NewAdminClient = func(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster) (*ravendb.Client, error) {
+			return ravendb.NewForCluster(ctx, kc, c)
+		}
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&ravendbv1.RavenDBDatabase{},
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Complete(r)
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package actor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"ravendb-operator/pkg/resource"
+)
+
+func Test_GS3_ActorAppliesTheOwnedHeadlessService(t *testing.T) {
+	scheme := actorScheme(t)
+	kc, applied := applyRecorder(scheme)
+	cluster := actorCluster()
+
+	a := NewGoverningServiceActor(resource.NewGoverningServiceBuilder())
+	require.True(t, a.ShouldAct(cluster))
+	_, err := a.Act(context.Background(), cluster, kc, scheme)
+	require.NoError(t, err)
+
+	require.Len(t, *applied, 1)
+	svc := (*applied)[0].(*corev1.Service)
+	require.Equal(t, "db-headless", svc.Name)
+	require.Equal(t, corev1.ClusterIPNone, svc.Spec.ClusterIP)
+	require.True(t, metav1.IsControlledBy(svc, cluster))
+}
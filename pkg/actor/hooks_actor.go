@@ -22,6 +22,7 @@ import (
 
 	ravendbv1 "ravendb-operator/api/v1"
 	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/naming"
 	"ravendb-operator/pkg/scripts"
 
 	corev1 "k8s.io/api/core/v1"
@@ -52,41 +53,13 @@ func (a *HooksActor) Act(
 	scheme *runtime.Scheme,
 ) (bool, error) {
 
-	bootstrapperCM := &corev1.ConfigMap{
-		TypeMeta: metav1.TypeMeta{
-			APIVersion: "v1",
-			Kind:       "ConfigMap",
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      common.BootstrapperHookConfigMap,
-			Namespace: cluster.Namespace,
-			Labels: map[string]string{
-				common.LabelAppName:   common.App,
-				common.LabelManagedBy: common.Manager,
-				common.LabelInstance:  cluster.Name,
-			},
-		},
-		Data: map[string]string{
-			common.InitClusterHookKey:               scripts.InitClusterScript,
-			common.CheckNodesDiscoverabilityHookKey: scripts.CheckNodesDiscoverabilityScript,
-		},
-	}
-
-	if err := controllerutil.SetControllerReference(cluster, bootstrapperCM, scheme); err != nil {
-		return false, fmt.Errorf("set owner ref on bootstrapper hook ConfigMap: %w", err)
-	}
-
-	if _, err := applyResourceSSA(ctx, c, bootstrapperCM, "ravendb-operator/hooks"); err != nil {
-		return false, fmt.Errorf("apply bootstrapper hook ConfigMap: %w", err)
-	}
-
 	certHookCM := &corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "v1",
 			Kind:       "ConfigMap",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      common.CertHookConfigMap,
+			Name:      naming.For(cluster).CertHookConfigMap(),
 			Namespace: cluster.Namespace,
 			Labels: map[string]string{
 				common.LabelAppName:   common.App,
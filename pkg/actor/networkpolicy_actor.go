@@ -0,0 +1,69 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package actor
+
+import (
+	"context"
+	"fmt"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/naming"
+	"ravendb-operator/pkg/resource"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+type networkPolicyActor struct {
+	builder resource.PerClusterBuilder
+}
+
+func NewNetworkPolicyActor(builder resource.PerClusterBuilder) PerClusterActor {
+	return &networkPolicyActor{builder: builder}
+}
+
+func (actor *networkPolicyActor) Name() string {
+	return "NetworkPolicyActor"
+}
+
+// ShouldAct is always true, Act removes the policy once spec.networkPolicy.enabled is unset
+func (actor *networkPolicyActor) ShouldAct(cluster *ravendbv1.RavenDBCluster) bool {
+	return true
+}
+
+func (actor *networkPolicyActor) Act(ctx context.Context, cluster *ravendbv1.RavenDBCluster, c client.Client, scheme *runtime.Scheme) (bool, error) {
+	if cluster.Spec.NetworkPolicy == nil || !cluster.Spec.NetworkPolicy.Enabled {
+		return deleteIfControlled(ctx, c, cluster, &networkingv1.NetworkPolicy{}, naming.For(cluster).NetworkPolicy())
+	}
+
+	np, err := actor.builder.Build(ctx, cluster)
+	if err != nil {
+		return false, fmt.Errorf("failed to build NetworkPolicy: %w", err)
+	}
+
+	if err := controllerutil.SetControllerReference(cluster, np, scheme); err != nil {
+		return false, fmt.Errorf("set owner ref on NetworkPolicy: %w", err)
+	}
+
+	changed, err := applyResourceSSA(ctx, c, np, "ravendb-operator/networkpolicy")
+	if err != nil {
+		return false, fmt.Errorf("failed to apply NetworkPolicy: %w", err)
+	}
+
+	return changed, nil
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package actor
+
+import (
+	"context"
+	"fmt"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/resource"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// NodeRBACActor gives the RavenDB pods their ServiceAccount, Role and RoleBinding in the cluster's namespace
+type NodeRBACActor struct{}
+
+func NewNodeRBACActor() PerClusterActor {
+	return &NodeRBACActor{}
+}
+
+func (a *NodeRBACActor) Name() string {
+	return "NodeRBACActor"
+}
+
+func (a *NodeRBACActor) ShouldAct(cluster *ravendbv1.RavenDBCluster) bool {
+	return true
+}
+
+func (a *NodeRBACActor) Act(ctx context.Context, cluster *ravendbv1.RavenDBCluster, c client.Client, scheme *runtime.Scheme) (bool, error) {
+	sa, role, binding := resource.BuildNodeRBAC(cluster)
+
+	for _, obj := range []client.Object{sa, role, binding} {
+		if err := controllerutil.SetControllerReference(cluster, obj, scheme); err != nil {
+			return false, fmt.Errorf("set owner ref on %s %s: %w", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetName(), err)
+		}
+		if _, err := applyResourceSSA(ctx, c, obj, "ravendb-operator/node-rbac"); err != nil {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
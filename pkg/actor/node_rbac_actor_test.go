@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package actor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"ravendb-operator/pkg/naming"
+	"ravendb-operator/pkg/resource"
+)
+
+func Test_NR1_ActorAppliesTheNodeRBACInTheClusterNamespace(t *testing.T) {
+	scheme := actorScheme(t)
+	kc, applied := applyRecorder(scheme)
+	cluster := actorCluster()
+
+	a := NewNodeRBACActor()
+	require.True(t, a.ShouldAct(cluster))
+	_, err := a.Act(context.Background(), cluster, kc, scheme)
+	require.NoError(t, err)
+
+	require.Len(t, *applied, 3)
+	for _, obj := range *applied {
+		require.Equal(t, "ravendb", obj.GetNamespace())
+		require.True(t, metav1.IsControlledBy(obj, cluster))
+	}
+	sa := (*applied)[0].(*corev1.ServiceAccount)
+	role := (*applied)[1].(*rbacv1.Role)
+	binding := (*applied)[2].(*rbacv1.RoleBinding)
+	require.Equal(t, naming.For(cluster).NodeServiceAccount(), sa.Name)
+	require.Equal(t, "db-node", sa.Name)
+	require.Equal(t, []rbacv1.Subject{{Kind: "ServiceAccount", Name: "db-node", Namespace: "ravendb"}}, binding.Subjects)
+	require.Equal(t, rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "Role", Name: role.Name}, binding.RoleRef)
+}
+
+func Test_NR2_LegacyClusterKeepsTheHandMadeNames(t *testing.T) {
+	cluster := actorCluster()
+	cluster.Annotations = map[string]string{naming.LegacyNamesAnnotation: "true"}
+
+	sa, role, binding := resource.BuildNodeRBAC(cluster)
+	require.Equal(t, "ravendb-ops-sa", sa.Name)
+	require.Equal(t, "ravendb-ops", role.Name)
+	require.Equal(t, "ravendb-ops-role-binding", binding.Name)
+	require.Equal(t, "ravendb-ops-sa", binding.Subjects[0].Name)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actor
+
+import (
+	"context"
+	"fmt"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/naming"
+	"ravendb-operator/pkg/resource"
+
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+type pdbActor struct {
+	builder resource.PerClusterBuilder
+}
+
+func NewPodDisruptionBudgetActor(builder resource.PerClusterBuilder) PerClusterActor {
+	return &pdbActor{builder: builder}
+}
+
+func (actor *pdbActor) Name() string {
+	return "PodDisruptionBudgetActor"
+}
+
+// ShouldAct is always true, Act removes the budget when spec.disruptionBudget.disabled is set
+func (actor *pdbActor) ShouldAct(cluster *ravendbv1.RavenDBCluster) bool {
+	return true
+}
+
+func (actor *pdbActor) Act(ctx context.Context, cluster *ravendbv1.RavenDBCluster, c client.Client, scheme *runtime.Scheme) (bool, error) {
+	if cluster.Spec.DisruptionBudget != nil && cluster.Spec.DisruptionBudget.Disabled {
+		return deleteIfControlled(ctx, c, cluster, &policyv1.PodDisruptionBudget{}, naming.For(cluster).PodDisruptionBudget())
+	}
+
+	pdb, err := actor.builder.Build(ctx, cluster)
+	if err != nil {
+		return false, fmt.Errorf("failed to build PodDisruptionBudget: %w", err)
+	}
+
+	if err := controllerutil.SetControllerReference(cluster, pdb, scheme); err != nil {
+		return false, fmt.Errorf("set owner ref on PodDisruptionBudget: %w", err)
+	}
+
+	changed, err := applyResourceSSA(ctx, c, pdb, "ravendb-operator/pdb")
+	if err != nil {
+		return false, fmt.Errorf("failed to apply PodDisruptionBudget: %w", err)
+	}
+
+	return changed, nil
+}
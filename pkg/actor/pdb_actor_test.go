@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package actor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	policyv1 "k8s.io/api/policy/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/resource"
+)
+
+func actorScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, ravendbv1.AddToScheme(scheme))
+	return scheme
+}
+
+func actorCluster() *ravendbv1.RavenDBCluster {
+	return &ravendbv1.RavenDBCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "ravendb", UID: "uid-db"},
+		Spec:       ravendbv1.RavenDBClusterSpec{Nodes: []ravendbv1.RavenDBNode{{Tag: "A"}, {Tag: "B"}, {Tag: "C"}}},
+	}
+}
+
+// the fake client doesn't support server side apply, the applied objects are captured instead
+func applyRecorder(scheme *runtime.Scheme, objs ...client.Object) (client.Client, *[]client.Object) {
+	var applied []client.Object
+	kc := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithInterceptorFuncs(interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if patch.Type() == "application/apply-patch+yaml" {
+				applied = append(applied, obj)
+				return nil
+			}
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+	}).Build()
+	return kc, &applied
+}
+
+func Test_PDB3_ActorAppliesTheOwnedBudget(t *testing.T) {
+	scheme := actorScheme(t)
+	kc, applied := applyRecorder(scheme)
+	cluster := actorCluster()
+
+	a := NewPodDisruptionBudgetActor(resource.NewPodDisruptionBudgetBuilder())
+	require.True(t, a.ShouldAct(cluster))
+	_, err := a.Act(context.Background(), cluster, kc, scheme)
+	require.NoError(t, err)
+
+	require.Len(t, *applied, 1)
+	pdb := (*applied)[0].(*policyv1.PodDisruptionBudget)
+	require.Equal(t, "db", pdb.Name)
+	require.Equal(t, int32(1), pdb.Spec.MaxUnavailable.IntVal)
+	require.True(t, metav1.IsControlledBy(pdb, cluster))
+}
+
+func Test_PDB4_DisablingDeletesOnlyTheOwnedBudget(t *testing.T) {
+	scheme := actorScheme(t)
+	cluster := actorCluster()
+	cluster.Spec.DisruptionBudget = &ravendbv1.DisruptionBudgetSpec{Disabled: true}
+
+	foreign := &policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "ravendb"}}
+	kc, applied := applyRecorder(scheme, foreign)
+	a := NewPodDisruptionBudgetActor(resource.NewPodDisruptionBudgetBuilder())
+
+	changed, err := a.Act(context.Background(), cluster, kc, scheme)
+	require.NoError(t, err)
+	require.False(t, changed)
+	require.NoError(t, kc.Get(context.Background(), client.ObjectKeyFromObject(foreign), &policyv1.PodDisruptionBudget{}))
+
+	owned := foreign.DeepCopy()
+	require.NoError(t, kc.Get(context.Background(), client.ObjectKeyFromObject(foreign), owned))
+	require.NoError(t, controllerutil.SetControllerReference(cluster, owned, scheme))
+	require.NoError(t, kc.Update(context.Background(), owned))
+
+	changed, err = a.Act(context.Background(), cluster, kc, scheme)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Empty(t, *applied)
+	err = kc.Get(context.Background(), client.ObjectKeyFromObject(foreign), &policyv1.PodDisruptionBudget{})
+	require.True(t, kerrors.IsNotFound(err))
+}
@@ -61,6 +61,10 @@ func (actor *StatefulSetActor) Name() string {
 //	     	common.UpgradeImageAnnotation on the existing StatefulSet. Seeing that marker,
 //	    	we do not freeze: we leave the builder's new image in place. SSA then updates
 //	     	the PodTemplate and Kubernetes performs a controlled rollout for this node only.
+//
+//	  (2.3) When the Upgrader rolls a node back, it places common.RollbackImageAnnotation
+//	     	with the previous image on the StatefulSet. That image wins over both the builder's
+//	     	image and the frozen one until the Upgrader removes the marker again.
 func (actor *StatefulSetActor) Act(ctx context.Context, cluster *ravendbv1.RavenDBCluster, node ravendbv1.RavenDBNode, kc client.Client, scheme *runtime.Scheme) (bool, error) {
 	sts, err := actor.builder.Build(ctx, cluster, node)
 	if err != nil {
@@ -97,6 +101,11 @@ func (actor *StatefulSetActor) Act(ctx context.Context, cluster *ravendbv1.Raven
 			if !marked {
 				desired.Spec.Template.Spec.Containers[0].Image = curImg
 			}
+
+			// (2.3)
+			if rollbackImg := existing.Annotations[common.RollbackImageAnnotation]; rollbackImg != "" {
+				desired.Spec.Template.Spec.Containers[0].Image = rollbackImg
+			}
 		}
 	}
 
@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package actor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+)
+
+// stsBuilder builds a bare StatefulSet for the node with the given image
+type stsBuilder struct{ image string }
+
+func (b stsBuilder) Build(_ context.Context, c *ravendbv1.RavenDBCluster, n ravendbv1.RavenDBNode) (client.Object, error) {
+	sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: c.Name + "-a", Namespace: c.Namespace}}
+	sts.Spec.Template.Spec.Containers = []corev1.Container{{Name: common.App, Image: b.image}}
+	return sts, nil
+}
+
+func liveSTS(image string, annotations map[string]string) *appsv1.StatefulSet {
+	sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "db-a", Namespace: "ravendb", Annotations: annotations}}
+	sts.Spec.Template.Spec.Containers = []corev1.Container{{Name: common.App, Image: image}}
+	return sts
+}
+
+func Test_STS1_ImageFreeze(t *testing.T) {
+	tests := []struct {
+		name        string
+		live        *appsv1.StatefulSet
+		wantImage   string
+		wantMarkers []string
+	}{
+		{
+			name:      "new StatefulSet gets the spec image",
+			wantImage: "ravendb/ravendb:6.2.5",
+		},
+		{
+			name:      "unmarked StatefulSet keeps its live image",
+			live:      liveSTS("ravendb/ravendb:6.2.1", nil),
+			wantImage: "ravendb/ravendb:6.2.1",
+		},
+		{
+			name:        "marked StatefulSet gets the spec image",
+			live:        liveSTS("ravendb/ravendb:6.2.1", map[string]string{common.UpgradeImageAnnotation: "ravendb/ravendb:6.2.5"}),
+			wantImage:   "ravendb/ravendb:6.2.5",
+			wantMarkers: []string{common.UpgradeImageAnnotation},
+		},
+		{
+			name:        "rollback marker wins over the spec image",
+			live:        liveSTS("ravendb/ravendb:6.2.5", map[string]string{common.RollbackImageAnnotation: "ravendb/ravendb:6.2.1"}),
+			wantImage:   "ravendb/ravendb:6.2.1",
+			wantMarkers: []string{common.RollbackImageAnnotation},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := actorScheme(t)
+			var objs []client.Object
+			if tt.live != nil {
+				objs = append(objs, tt.live)
+			}
+			kc, applied := applyRecorder(scheme, objs...)
+			a := NewStatefulSetActor(stsBuilder{image: "ravendb/ravendb:6.2.5"})
+
+			_, err := a.Act(context.Background(), actorCluster(), ravendbv1.RavenDBNode{Tag: "A"}, kc, scheme)
+			require.NoError(t, err)
+			require.Len(t, *applied, 1)
+			sts := (*applied)[0].(*appsv1.StatefulSet)
+			require.Equal(t, tt.wantImage, sts.Spec.Template.Spec.Containers[0].Image)
+			for _, m := range tt.wantMarkers {
+				require.Contains(t, sts.Annotations, m)
+			}
+		})
+	}
+}
@@ -20,6 +20,10 @@ import (
 	"context"
 	"fmt"
 
+	ravendbv1 "ravendb-operator/api/v1"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -30,3 +34,24 @@ func applyResourceSSA(ctx context.Context, c client.Client, desired client.Objec
 	}
 	return false, nil
 }
+
+// deleteIfControlled deletes the object named name when it exists and the cluster controls it.
+// it's used for optional resources that were switched off in the spec.
+func deleteIfControlled(ctx context.Context, c client.Client, cluster *ravendbv1.RavenDBCluster, obj client.Object, name string) (bool, error) {
+	key := client.ObjectKey{Namespace: cluster.Namespace, Name: name}
+	if err := c.Get(ctx, key, obj); err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("get %T %s: %w", obj, key, err)
+	}
+
+	if !metav1.IsControlledBy(obj, cluster) {
+		return false, nil
+	}
+
+	if err := c.Delete(ctx, obj); err != nil && !kerrors.IsNotFound(err) {
+		return false, fmt.Errorf("delete %T %s: %w", obj, key, err)
+	}
+	return true, nil
+}
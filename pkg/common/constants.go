@@ -18,39 +18,30 @@ package common
 
 // paths
 const (
-	LicensePath                         = "/ravendb/license/license.json"
-	DataMountPath                       = "/var/lib/ravendb/data"
-	CertMountPath                       = "/ravendb/certs"
-	ClientCertMountPath                 = "/ravendb/client-certs"
-	CACertMountPath                     = "/ravendb/ca-cert"
-	LicenseMountPath                    = "/ravendb/license"
-	LogsMountPath                       = "/var/log/ravendb/logs"
-	AuditMountPath                      = "/var/log/ravendb/audit"
-	CertSourcePath                      = "ravendb/cert-source"
-	UpdateCertScriptPath                = "/ravendb/scripts/update-cert.sh"
-	GetCertScriptPath                   = "/ravendb/scripts/get-server-cert.sh"
-	InitClusterScriptPath               = "/ravendb/scripts/init-cluster.sh"
-	CheckNodesDiscoverabilityScriptPath = "/ravendb/scripts/check-nodes-discoverability.sh"
+	LicensePath          = "/ravendb/license/license.json"
+	DataMountPath        = "/var/lib/ravendb/data"
+	CertMountPath        = "/ravendb/certs"
+	LicenseMountPath     = "/ravendb/license"
+	LogsMountPath        = "/var/log/ravendb/logs"
+	AuditMountPath       = "/var/log/ravendb/audit"
+	CertSourcePath       = "ravendb/cert-source"
+	UpdateCertScriptPath = "/ravendb/scripts/update-cert.sh"
+	GetCertScriptPath    = "/ravendb/scripts/get-server-cert.sh"
 )
 
 // identifiers
 const (
-	App                        = "ravendb"
-	Manager                    = "ravendb-operator"
-	Prefix                     = "ravendb-"
-	HttpsPortName              = "https"
-	TcpPortName                = "tcp"
-	CertVolumeName             = "ravendb-cert"
-	LicenseVolumeName          = "ravendb-license"
-	DataVolumeName             = "ravendb-data"
-	LogsVolumeName             = "ravendb-logs"
-	AuditVolumeName            = "ravendb-audit"
-	ClientCertVolumeName       = "ravendb-client-cert"
-	CACertVolumeName           = "ravendb-ca-cert"
-	CertHookVolumeName         = "ravendb-cert-hook"
-	BootstrapperHookVolumeName = "ravendb-bootstrapper-hook"
-	RavenDbNodeServiceAccount  = "ravendb-ops-sa"
-	RavenDbBootstrapperJob     = "ravendb-cluster-init"
+	App                = "ravendb"
+	Manager            = "ravendb-operator"
+	HttpsPortName      = "https"
+	TcpPortName        = "tcp"
+	CertVolumeName     = "ravendb-cert"
+	LicenseVolumeName  = "ravendb-license"
+	DataVolumeName     = "ravendb-data"
+	LogsVolumeName     = "ravendb-logs"
+	AuditVolumeName    = "ravendb-audit"
+	CertHookVolumeName = "ravendb-cert-hook"
+	OperatorNamespace  = "ravendb-operator-system"
 )
 
 // labels
@@ -63,6 +54,14 @@ const (
 	TopologyZoneLabel = "topology.kubernetes.io/zone"
 )
 
+// network policy peers
+const (
+	NamespaceNameLabel = "kubernetes.io/metadata.name"
+	// set on the operator's manager pods, see config/manager
+	LabelControlPlane      = "control-plane"
+	ControlPlaneController = "controller-manager"
+)
+
 // annotations
 const (
 	IngressSSLPassthroughAnnotation         = "ingress.kubernetes.io/ssl-passthrough"
@@ -74,6 +73,7 @@ const (
 	AWSLoadBalancerEIPAllocationsAnnotation = "service.beta.kubernetes.io/aws-load-balancer-eip-allocations"
 	AWSLoadBalancerSubnetsAnnotation        = "service.beta.kubernetes.io/aws-load-balancer-subnets"
 	UpgradeImageAnnotation                  = "ravendb.ravendb.io/upgrade-image"
+	RollbackImageAnnotation                 = "ravendb.ravendb.io/rollback-image"
 	UpgradePreWaitAnnotation                = "ravendb.io/upgrade-pre-wait"
 	UpgradePostWaitAnnotation               = "ravendb.io/upgrade-post-wait"
 	UpgradePingIntervalAnnotation           = "ravendb.io/upgrade-ping-interval"
@@ -100,15 +100,10 @@ const (
 
 // other
 const (
-	NumOfReplicas                    = 1
-	ConfigMapExecMode                = 0755
-	CertExecTimeout                  = "60"
-	ClusterFQDNSuffix                = ".ravendb.svc.cluster.local"
-	ProtocolTcp                      = "tcp://"
-	UpdateCertHookKey                = "update-cert.sh"
-	GetCertHookKey                   = "get-server-cert.sh"
-	CertHookConfigMap                = "ravendb-cert-hook"
-	InitClusterHookKey               = "init-cluster.sh"
-	CheckNodesDiscoverabilityHookKey = "check-nodes-discoverability.sh"
-	BootstrapperHookConfigMap        = "ravendb-bootstrapper-hook"
+	NumOfReplicas     = 1
+	ConfigMapExecMode = 0755
+	CertExecTimeout   = "60"
+	ProtocolTcp       = "tcp://"
+	UpdateCertHookKey = "update-cert.sh"
+	GetCertHookKey    = "get-server-cert.sh"
 )
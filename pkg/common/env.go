@@ -19,14 +19,29 @@ package common
 import (
 	"fmt"
 	ravendbv1 "ravendb-operator/api/v1"
-	"strings"
+	"ravendb-operator/pkg/naming"
 
 	corev1 "k8s.io/api/core/v1"
 )
 
+// ClusterDomain is the DNS domain of the Kubernetes cluster, set from the operator's --cluster-domain flag
+var ClusterDomain = "cluster.local"
+
+// ServiceFQDN is the cluster-internal DNS name of a Service
+func ServiceFQDN(name, namespace string) string {
+	return fmt.Sprintf("%s.%s.svc.%s", name, namespace, ClusterDomain)
+}
+
+// PodFQDN is the DNS name the governing (headless) Service gives a StatefulSet pod
+func PodFQDN(pod, governingService, namespace string) string {
+	return fmt.Sprintf("%s.%s", pod, ServiceFQDN(governingService, namespace))
+}
+
 func BuildCommonEnvVars(cluster *ravendbv1.RavenDBCluster, node ravendbv1.RavenDBNode) []corev1.EnvVar {
 
-	ravendbNodeTcpEndpoint := fmt.Sprintf("%s%s%s%s:%d", ProtocolTcp, Prefix, node.Tag, ClusterFQDNSuffix, InternalTcpPort)
+	// the nodes reach each other's TCP port through the pod's DNS name, not through the node's Service
+	names := naming.For(cluster)
+	ravendbNodeTcpEndpoint := fmt.Sprintf("%s%s:%d", ProtocolTcp, PodFQDN(names.Pod(node.Tag), names.GoverningService(), cluster.Namespace), InternalTcpPort)
 	return []corev1.EnvVar{
 		{Name: "RAVEN_Setup_Mode", Value: string(cluster.Spec.Mode)},
 		{Name: "RAVEN_License_Path", Value: LicensePath},
@@ -37,6 +52,7 @@ func BuildCommonEnvVars(cluster *ravendbv1.RavenDBCluster, node ravendbv1.RavenD
 		{Name: "RAVEN_ServerUrl_Tcp", Value: InternalTcpUrl},
 		{Name: "RAVEN_PublicServerUrl_Tcp_Cluster", Value: ravendbNodeTcpEndpoint},
 		{Name: "NODE_TAG", Value: node.Tag},
+		{Name: "CLUSTER_NAME", Value: cluster.Name},
 	}
 }
 
@@ -64,13 +80,3 @@ func BuildAdditionalEnvVars(cluster *ravendbv1.RavenDBCluster) []corev1.EnvVar {
 	}
 	return envVars
 }
-
-func BuildClusterBootstrapperEnvVars(leaderURL string, memberURLs []string, allURLs []string, allTags []string, tcpHosts []string) []corev1.EnvVar {
-	return []corev1.EnvVar{
-		{Name: "LEADER_URL", Value: leaderURL},
-		{Name: "MEMBER_URLS", Value: strings.Join(memberURLs, " ")},
-		{Name: "URLS", Value: strings.Join(allURLs, " ")},
-		{Name: "TAGS", Value: strings.Join(allTags, " ")},
-		{Name: "TCP_HOSTS", Value: strings.Join(tcpHosts, " ")},
-	}
-}
@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package database
+
+import (
+	"strings"
+
+	"ravendb-operator/pkg/ravendb"
+)
+
+// Topology is the database group as PlanTopology sees it
+type Topology struct {
+	Members           []string
+	Promotables       []string
+	Rehabs            []string
+	ReplicationFactor int
+}
+
+// TopologyOf returns the database group stored on the record (empty when RavenDB didn't assign one yet)
+func TopologyOf(rec *ravendb.DatabaseRecord) Topology {
+	if rec == nil || rec.Topology == nil {
+		return Topology{}
+	}
+	return Topology{
+		Members:           rec.Topology.Members,
+		Promotables:       rec.Topology.Promotables,
+		Rehabs:            rec.Topology.Rehabs,
+		ReplicationFactor: rec.Topology.ReplicationFactor,
+	}
+}
+
+// NewRecord is the record a database is created with. when nodes is non-empty the database is pinned
+// to those nodes, otherwise RavenDB picks the replication factor's nodes by itself.
+func NewRecord(name string, replicationFactor int, nodes []string, settings map[string]string) ravendb.DatabaseRecord {
+	rec := ravendb.DatabaseRecord{
+		DatabaseName: name,
+		Settings:     settings,
+	}
+	if len(nodes) > 0 {
+		rec.Topology = &ravendb.RecordTopology{
+			Members:           normalizeTags(nodes),
+			ReplicationFactor: replicationFactor,
+		}
+	}
+	return rec
+}
+
+func normalizeTags(tags []string) []string {
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		out = append(out, strings.ToUpper(strings.TrimSpace(t)))
+	}
+	return out
+}
+
+// SettingsInSync reports whether every desired setting is already stored on the record.
+// settings that exist on the record but not in the spec are left alone.
+func SettingsInSync(rec *ravendb.DatabaseRecord, desired map[string]string) bool {
+	for k, v := range desired {
+		if rec == nil || rec.Settings == nil {
+			return false
+		}
+		if cur, ok := rec.Settings[k]; !ok || cur != v {
+			return false
+		}
+	}
+	return true
+}
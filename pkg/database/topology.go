@@ -0,0 +1,111 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package database
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TopologyChange is a single step towards the desired database group.
+// at most one of Add/Remove is set; both empty means the group is in sync.
+type TopologyChange struct {
+	Add    string
+	Remove string
+}
+
+func (tc TopologyChange) InSync() bool { return tc.Add == "" && tc.Remove == "" }
+
+// PlanTopology compares the current database group with the desired one and returns the next step.
+// we change one node per reconcile so RavenDB can replicate to a new node before we drop an old one.
+//
+//   - pinned (nodes set): the group must be exactly those nodes.
+//   - not pinned: the group must have replicationFactor nodes, chosen from the cluster in spec order.
+func PlanTopology(current Topology, pinned []string, replicationFactor int, clusterTags []string) (TopologyChange, error) {
+	cluster := normalizeTags(clusterTags)
+	group := groupTags(current)
+
+	if len(pinned) > 0 {
+		desired := normalizeTags(pinned)
+
+		if replicationFactor != len(desired) {
+			return TopologyChange{}, fmt.Errorf("replicationFactor %d does not match %d pinned nodes", replicationFactor, len(desired))
+		}
+		for _, t := range desired {
+			if !contains(cluster, t) {
+				return TopologyChange{}, fmt.Errorf("pinned node %q is not part of the cluster", t)
+			}
+		}
+
+		for _, t := range desired {
+			if !contains(group, t) {
+				return TopologyChange{Add: t}, nil
+			}
+		}
+		for _, t := range group {
+			if !contains(desired, t) {
+				return TopologyChange{Remove: t}, nil
+			}
+		}
+		return TopologyChange{}, nil
+	}
+
+	if replicationFactor > len(cluster) {
+		return TopologyChange{}, fmt.Errorf("replicationFactor %d exceeds cluster size %d", replicationFactor, len(cluster))
+	}
+
+	if len(group) < replicationFactor {
+		for _, t := range cluster {
+			if !contains(group, t) {
+				return TopologyChange{Add: t}, nil
+			}
+		}
+	}
+
+	if len(group) > replicationFactor {
+		// drop nodes that are not serving first
+		for _, candidates := range [][]string{current.Rehabs, current.Promotables, current.Members} {
+			c := normalizeTags(candidates)
+			if len(c) > 0 {
+				return TopologyChange{Remove: c[len(c)-1]}, nil
+			}
+		}
+	}
+
+	return TopologyChange{}, nil
+}
+
+func groupTags(t Topology) []string {
+	out := []string{}
+	for _, list := range [][]string{t.Members, t.Promotables, t.Rehabs} {
+		for _, tag := range normalizeTags(list) {
+			if !contains(out, tag) {
+				out = append(out, tag)
+			}
+		}
+	}
+	return out
+}
+
+func contains(list []string, tag string) bool {
+	for _, t := range list {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DB1_PlanTopology(t *testing.T) {
+	cluster := []string{"A", "B", "C", "D"}
+
+	cases := []struct {
+		name    string
+		current Topology
+		pinned  []string
+		rf      int
+		want    TopologyChange
+		wantErr string
+	}{
+		{name: "replication factor met", current: Topology{Members: []string{"A", "B"}}, rf: 2},
+		{name: "replication factor grows in spec order", current: Topology{Members: []string{"B"}}, rf: 3, want: TopologyChange{Add: "A"}},
+		{name: "new database group", rf: 1, want: TopologyChange{Add: "A"}},
+		{name: "catching up nodes count towards the factor", current: Topology{Members: []string{"A"}, Promotables: []string{"B"}}, rf: 2},
+		{name: "shrinking drops a rehab first", current: Topology{Members: []string{"A", "B"}, Rehabs: []string{"C"}}, rf: 2, want: TopologyChange{Remove: "C"}},
+		{name: "shrinking drops a promotable before a member", current: Topology{Members: []string{"A", "B"}, Promotables: []string{"D"}}, rf: 2, want: TopologyChange{Remove: "D"}},
+		{name: "shrinking drops the last member", current: Topology{Members: []string{"A", "B", "C"}}, rf: 2, want: TopologyChange{Remove: "C"}},
+		{name: "factor above the cluster size", rf: 5, wantErr: "exceeds cluster size 4"},
+
+		{name: "pinned in sync", current: Topology{Members: []string{"B", "D"}}, pinned: []string{"d", "b"}, rf: 2},
+		{name: "pinned adds the missing node", current: Topology{Members: []string{"B"}}, pinned: []string{"B", "D"}, rf: 2, want: TopologyChange{Add: "D"}},
+		{name: "pinned adds before it removes", current: Topology{Members: []string{"A", "B"}}, pinned: []string{"B", "C"}, rf: 2, want: TopologyChange{Add: "C"}},
+		{name: "pinned removes the unlisted node", current: Topology{Members: []string{"A", "B", "C"}}, pinned: []string{"B", "C"}, rf: 2, want: TopologyChange{Remove: "A"}},
+		{name: "pinned removes an unlisted rehab", current: Topology{Members: []string{"B"}, Rehabs: []string{"A"}}, pinned: []string{"B"}, rf: 1, want: TopologyChange{Remove: "A"}},
+		{name: "pinned node outside the cluster", pinned: []string{"A", "E"}, rf: 2, wantErr: `pinned node "E" is not part of the cluster`},
+		{name: "pinned count differs from the factor", pinned: []string{"A", "B"}, rf: 3, wantErr: "replicationFactor 3 does not match 2 pinned nodes"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := PlanTopology(tc.current, tc.pinned, tc.rf, cluster)
+			if tc.wantErr != "" {
+				require.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+			require.Equal(t, tc.want == TopologyChange{}, got.InSync())
+		})
+	}
+}
@@ -53,9 +53,12 @@ type DefaultDirector struct {
 func NewDefaultDirector() Director {
 	return &DefaultDirector{
 		perClusterActors: []actor.PerClusterActor{
+			actor.NewGoverningServiceActor(resource.NewGoverningServiceBuilder()),
 			actor.NewIngressActor(resource.NewIngressBuilder()),
-			actor.NewBootstrapperActor(resource.NewJobBuilder()),
+			actor.NewNodeRBACActor(),
 			actor.NewHooksActor(),
+			actor.NewPodDisruptionBudgetActor(resource.NewPodDisruptionBudgetBuilder()),
+			actor.NewNetworkPolicyActor(resource.NewNetworkPolicyBuilder()),
 		},
 		perNodeActors: []actor.PerNodeActor{
 			actor.NewStatefulSetActor(resource.NewStatefulSetBuilder()),
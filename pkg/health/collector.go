@@ -34,8 +34,14 @@ type ResourceFacts struct {
 	PVCs         []PVCFact
 	Services     []ServiceFact
 	Ingresses    []IngressFact
-	Jobs         []JobFact
 	Secrets      []SecretFact
+	// the cluster's PodDisruptionBudget, nil when there is none (e.g. spec.disruptionBudget.disabled)
+	DisruptionBudget *PodDisruptionBudgetFact
+
+	// what the nodes themselves report, nil until the cluster is bootstrapped
+	RavenDB *RavenDBFacts
+	// why RavenDB is nil although the cluster is bootstrapped (the nodes couldn't be probed)
+	RavenDBError string
 }
 
 type StatefulSetFact struct {
@@ -51,9 +57,11 @@ type StatefulSetFact struct {
 type PodFact struct {
 	Name      string
 	Namespace string
+	Tag       string
 	Phase     string
 	Ready     bool
 	Restarts  int32
+	Image     string // of the RavenDB container
 }
 
 type PVCFact struct {
@@ -79,13 +87,13 @@ type IngressFact struct {
 	LBReady   bool
 }
 
-type JobFact struct {
-	Name      string
-	Namespace string
-	Succeeded bool
-	Active    int32
-	Failed    int32
-	Completed bool
+type PodDisruptionBudgetFact struct {
+	Name               string
+	Namespace          string
+	ExpectedPods       int32
+	CurrentHealthy     int32
+	DesiredHealthy     int32
+	DisruptionsAllowed int32
 }
 
 type SecretFact struct {
@@ -93,3 +101,26 @@ type SecretFact struct {
 	Namespace string
 	Type      string
 }
+
+type RavenDBFacts struct {
+	Nodes []RavenDBNodeFact
+
+	// summary of /databases by name, DatabasesError is set instead when no node answered
+	Databases      []ravendbv1.DatabaseHealth
+	DatabasesError string
+}
+
+// RavenDBNodeFact is one node as it answers over HTTPS, in spec.nodes order
+type RavenDBNodeFact struct {
+	Tag string
+	// /setup/alive answered with a 2xx
+	Alive bool
+	// why the node couldn't be (fully) read
+	Error string
+	// the node's own view of the cluster: its leader, its state (e.g. Leader, Follower, Passive)
+	// and its own role ("" when it doesn't see itself in the topology)
+	Leader  string
+	State   string
+	Role    ravendbv1.NodeClusterRole
+	Version string
+}
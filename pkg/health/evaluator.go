@@ -18,6 +18,7 @@ package health
 
 import (
 	"context"
+	"fmt"
 	"sort"
 	"strings"
 
@@ -52,6 +53,10 @@ func (e *evaluator) Evaluate(_ context.Context, cluster *ravendbv1.RavenDBCluste
 	e.apply(cluster, ravendbv1.ConditionNodesHealthy, e.evalNodesHealthy(cluster, res), now)
 	e.apply(cluster, ravendbv1.ConditionExternalAccessReady, e.evalExternalAccessReady(cluster, res), now)
 	e.apply(cluster, ravendbv1.ConditionBootstrapCompleted, e.evalBootstrap(cluster, res), now)
+	e.apply(cluster, ravendbv1.ConditionUpgrading, e.evalUpgrading(cluster), now)
+	e.apply(cluster, ravendbv1.ConditionTopologyInSync, e.evalTopologyInSync(cluster), now)
+	e.recordDatabases(cluster, res)
+	e.apply(cluster, ravendbv1.ConditionDatabasesHealthy, e.evalDatabasesHealthy(cluster, res), now)
 	e.apply(cluster, ravendbv1.ConditionProgressing, e.evalProgressingCase(cluster, res), now)
 	e.apply(cluster, ravendbv1.ConditionDegraded, e.evalDegradingCase(cluster, res), now)
 
@@ -66,9 +71,13 @@ func (e *evaluator) apply(cluster *ravendbv1.RavenDBCluster, condType ravendbv1.
 		return
 	}
 
-	if r.status == metav1.ConditionTrue {
+	switch r.status {
+	case metav1.ConditionTrue:
 		cluster.SetConditionTrue(condType, r.reason, r.message, now)
 		return
+	case metav1.ConditionUnknown:
+		cluster.SetConditionUnknown(condType, r.reason, r.message, now)
+		return
 	}
 
 	cluster.SetConditionFalse(condType, r.reason, r.message, now)
@@ -99,23 +108,31 @@ func (e *evaluator) evalStorage(cluster *ravendbv1.RavenDBCluster, res *Resource
 
 func (e *evaluator) evalBootstrap(cluster *ravendbv1.RavenDBCluster, res *ResourceFacts) conditionResult {
 
-	if res == nil || len(res.Jobs) == 0 {
-		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonBootstrapJobRunning, message: "bootstrap job not observed yet"}
+	st := cluster.Status.Bootstrap
+	if st == nil {
+		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonBootstrapInProgress, message: "bootstrap not started yet"}
 	}
 
-	for i := 0; i < len(res.Jobs); i++ {
-		if res.Jobs[i].Completed {
-			return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonCompleted, message: "bootstrap job succeeded"}
-		}
+	switch st.Phase {
+	case ravendbv1.BootstrapPhaseCompleted:
+		return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonCompleted, message: "cluster bootstrapped"}
+
+	case ravendbv1.BootstrapPhaseFailed:
+		step := currentBootstrapStep(st)
+		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonBootstrapFailed, message: fmt.Sprintf("step %s failed (attempt %d), retrying: %s", step.Name, step.Attempts, step.Message)}
 	}
 
-	for i := 0; i < len(res.Jobs); i++ {
-		if res.Jobs[i].Failed > 0 {
-			return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonBootstrapFailed, message: "bootstrap job failed"}
+	step := currentBootstrapStep(st)
+	return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonBootstrapInProgress, message: fmt.Sprintf("step %s: %s", step.Name, step.Message)}
+}
+
+func currentBootstrapStep(st *ravendbv1.BootstrapStatus) ravendbv1.BootstrapStepStatus {
+	for _, s := range st.Steps {
+		if s.Name == st.CurrentStep {
+			return s
 		}
 	}
-
-	return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonBootstrapJobRunning, message: "bootstrap job still running"}
+	return ravendbv1.BootstrapStepStatus{Name: st.CurrentStep}
 }
 
 func (e *evaluator) evalCertificates(cluster *ravendbv1.RavenDBCluster, res *ResourceFacts) conditionResult {
@@ -170,7 +187,43 @@ func (e *evaluator) evalNodesHealthy(cluster *ravendbv1.RavenDBCluster, res *Res
 		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonPodsNotReady, message: "pods not ready: " + joinNames(notReady)}
 	}
 
-	return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonCompleted, message: "all node pods ready"}
+	return e.evalRavenDBNodes(cluster, res)
+}
+
+// a ready pod is not enough once the cluster is bootstrapped: RavenDB may have dropped out of the cluster, or
+// answer the readiness probe on /setup/alive locally but not on its public URL (or spec.probes disabled it).
+// every node has to answer /setup/alive and see itself in a cluster with a leader.
+func (e *evaluator) evalRavenDBNodes(cluster *ravendbv1.RavenDBCluster, res *ResourceFacts) conditionResult {
+
+	facts := res.RavenDB
+	if facts == nil {
+		if cluster.IsBootstrapped() {
+			// ready pods say nothing about RavenDB here, we just don't know
+			return probeFailed(res)
+		}
+		return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonCompleted, message: "all node pods ready"}
+	}
+
+	unreachable, outside, versions := bucketizeRavenDBNodes(facts.Nodes)
+
+	if len(unreachable) > 0 {
+		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonNodeUnreachable, message: "RavenDB not answering on nodes: " + joinNames(unreachable)}
+	}
+
+	if len(outside) > 0 {
+		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonNodeNotInCluster, message: "nodes not serving the cluster: " + joinNames(outside)}
+	}
+
+	// mixed versions are expected while a rolling upgrade runs, so they are only mentioned
+	msg := "all nodes ready and serving"
+	switch {
+	case len(versions) > 1:
+		msg += ", mixed server versions: " + joinNames(versions)
+	case len(versions) == 1:
+		msg += " on RavenDB " + versions[0]
+	}
+
+	return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonCompleted, message: msg}
 }
 
 func (e *evaluator) evalLicense(cluster *ravendbv1.RavenDBCluster, res *ResourceFacts) conditionResult {
@@ -223,7 +276,178 @@ func (e *evaluator) evalExternalAccessReady(cluster *ravendbv1.RavenDBCluster, r
 	return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonLoadBalancerPending, message: "no ingress/load balancer service observed"}
 }
 
-// Progressing=True when any of the STSs is updating or bootstrap job is active.
+// Upgrading=True while a rolling upgrade runs (including a node finishing before a pause/abort or rolling back),
+// False once it is paused, aborted, rolled back or completed. skipped until the cluster was upgraded once.
+func (e *evaluator) evalUpgrading(cluster *ravendbv1.RavenDBCluster) conditionResult {
+	st := cluster.Status.Upgrade
+	if st == nil || st.Phase == "" {
+		return conditionResult{skip: true}
+	}
+
+	done := 0
+	for i := 0; i < len(st.Plan); i++ {
+		if st.Plan[i].Phase == ravendbv1.UpgradeNodeCompleted {
+			done++
+		}
+	}
+	progress := fmt.Sprintf("%d/%d nodes on %s", done, len(st.Plan), st.TargetImage)
+
+	switch st.Phase {
+	case ravendbv1.UpgradePhaseRunning:
+		if st.CurrentNode == "" {
+			return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonUpgradeInProgress, message: progress}
+		}
+		for i := 0; i < len(st.Plan); i++ {
+			if st.Plan[i].Tag == st.CurrentNode && st.Plan[i].Phase == ravendbv1.UpgradeNodeRollingBack {
+				return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonUpgradeRollingBack, message: "rolling back node " + st.CurrentNode + " to " + st.Plan[i].FromImage + ", " + progress}
+			}
+		}
+		switch st.Control {
+		case ravendbv1.UpgradeControlPause:
+			return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonUpgradePausing, message: "finishing node " + st.CurrentNode + " before pausing, " + progress}
+		case ravendbv1.UpgradeControlAbort:
+			return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonUpgradeAborting, message: "finishing node " + st.CurrentNode + " before aborting, " + progress}
+		}
+		return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonUpgradeInProgress, message: "upgrading node " + st.CurrentNode + ", " + progress}
+
+	case ravendbv1.UpgradePhasePaused:
+		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonUpgradePaused, message: "paused by spec.upgrade.control, " + progress}
+
+	case ravendbv1.UpgradePhaseAborted:
+		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonUpgradeAborted, message: "aborted by spec.upgrade.control, " + progress}
+
+	case ravendbv1.UpgradePhaseRolledBack:
+		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonUpgradeRolledBack, message: "rolled back after failed post gates, " + progress}
+	}
+
+	return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonCompleted, message: progress}
+}
+
+// ClusterTopologyInSync=True when the Raft topology has every spec node in the role the spec asks for,
+// and no other node. skipped until the topology was checked once (after the bootstrap).
+func (e *evaluator) evalTopologyInSync(cluster *ravendbv1.RavenDBCluster) conditionResult {
+	st := cluster.Status.Topology
+	if st == nil || st.LastCheckTime == nil {
+		return conditionResult{skip: true}
+	}
+
+	if st.Error != "" {
+		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonTopologyUnavailable, message: "could not read the cluster topology: " + st.Error}
+	}
+
+	if len(st.Drift) > 0 {
+		drift := make([]string, 0, len(st.Drift))
+		for i := 0; i < len(st.Drift); i++ {
+			drift = append(drift, "node "+st.Drift[i].Tag+" "+st.Drift[i].Describe())
+		}
+		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonTopologyDrift, message: strings.Join(drift, "; ")}
+	}
+
+	return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonCompleted, message: "topology matches spec.nodes, leader " + st.Leader}
+}
+
+// recordDatabases keeps the per-database summary in status.databases. when /databases couldn't be read
+// the previous summary stays, the DatabasesHealthy condition says why it is stale.
+// the list is capped at MaxDatabasesInStatus with the unhealthy databases first, status.databasesSummary counts all.
+func (e *evaluator) recordDatabases(cluster *ravendbv1.RavenDBCluster, res *ResourceFacts) {
+	if res == nil || res.RavenDB == nil || res.RavenDB.DatabasesError != "" {
+		return
+	}
+
+	dbs := res.RavenDB.Databases
+	summary := &ravendbv1.DatabasesSummary{Total: len(dbs)}
+	listed := make([]ravendbv1.DatabaseHealth, 0, min(len(dbs), ravendbv1.MaxDatabasesInStatus))
+	for _, healthy := range []bool{false, true} {
+		for i := 0; i < len(dbs); i++ {
+			if dbs[i].Healthy() != healthy {
+				continue
+			}
+			if !healthy {
+				summary.Unhealthy++
+			}
+			if len(listed) < ravendbv1.MaxDatabasesInStatus {
+				listed = append(listed, dbs[i])
+			}
+		}
+	}
+	summary.Omitted = len(dbs) - len(listed)
+
+	if len(listed) == 0 {
+		listed = nil
+	}
+	cluster.Status.Databases = listed
+	cluster.Status.DatabasesSummary = summary
+}
+
+// DatabasesHealthy=True when every enabled database has all its nodes Ok and nothing in rehab.
+// skipped until the cluster is bootstrapped.
+func (e *evaluator) evalDatabasesHealthy(cluster *ravendbv1.RavenDBCluster, res *ResourceFacts) conditionResult {
+	if res == nil || !cluster.IsBootstrapped() {
+		return conditionResult{skip: true}
+	}
+	if res.RavenDB == nil {
+		return probeFailed(res)
+	}
+
+	if res.RavenDB.DatabasesError != "" {
+		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonDatabasesUnavailable, message: "could not read the databases: " + res.RavenDB.DatabasesError}
+	}
+
+	dbs := res.RavenDB.Databases
+	if offline := offlineDatabases(dbs); len(offline) > 0 {
+		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonDatabaseOffline, message: "no member serving databases: " + joinNames(offline)}
+	}
+
+	troubled := make([]string, 0, len(dbs))
+	for i := 0; i < len(dbs); i++ {
+		db := dbs[i]
+		if db.Disabled {
+			continue
+		}
+
+		nodes := make([]string, 0, len(db.NodesInError)+len(db.Rehabs))
+		for _, n := range db.NodesInError {
+			nodes = append(nodes, withWhy(n.Tag, n.Status))
+		}
+		for _, tag := range db.Rehabs {
+			nodes = append(nodes, withWhy(tag, "rehab"))
+		}
+		if len(nodes) > 0 {
+			troubled = append(troubled, db.Name+" on "+joinNames(nodes))
+		}
+	}
+
+	if len(troubled) > 0 {
+		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonDatabaseNodesInError, message: strings.Join(troubled, "; ")}
+	}
+
+	if len(dbs) == 0 {
+		return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonCompleted, message: "no databases"}
+	}
+	return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonCompleted, message: fmt.Sprintf("%d databases healthy", len(dbs))}
+}
+
+// probeFailed is the result of the conditions that need the RavenDB facts when the collector couldn't get them
+func probeFailed(res *ResourceFacts) conditionResult {
+	why := res.RavenDBError
+	if why == "" {
+		why = "no answer collected"
+	}
+	return conditionResult{status: metav1.ConditionUnknown, reason: ravendbv1.ReasonProbeFailed, message: "could not probe the RavenDB nodes: " + why}
+}
+
+// offlineDatabases returns the enabled databases clients can't use at all
+func offlineDatabases(dbs []ravendbv1.DatabaseHealth) []string {
+	var out []string
+	for i := 0; i < len(dbs); i++ {
+		if !dbs[i].Disabled && !dbs[i].Serving() {
+			out = append(out, dbs[i].Name)
+		}
+	}
+	return out
+}
+
+// Progressing=True when any of the STSs is updating or the bootstrap is not completed yet.
 func (e *evaluator) evalProgressingCase(cluster *ravendbv1.RavenDBCluster, res *ResourceFacts) conditionResult {
 	if res == nil {
 		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonCompleted, message: "no active rollouts"}
@@ -235,21 +459,25 @@ func (e *evaluator) evalProgressingCase(cluster *ravendbv1.RavenDBCluster, res *
 		}
 	}
 
-	for i := 0; i < len(res.Jobs); i++ {
-		if res.Jobs[i].Active > 0 {
-			return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonStatefulSetUpdating, message: "rollout in progress"}
-		}
+	if st := cluster.Status.Bootstrap; st != nil && st.Phase != ravendbv1.BootstrapPhaseCompleted {
+		return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonBootstrapInProgress, message: "bootstrap in progress"}
 	}
 
 	return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonCompleted, message: "no active rollouts"}
 }
 
-// Degraded=True when bootstrap job failed or pods have high restart counts.
+// a bootstrap step that fails once is usually a node that is still starting, it is retried anyway
+const bootstrapFailuresDegraded int32 = 3
+
+// Degraded=True when a bootstrap step keeps failing, pods have high restart counts, a node's pod is ready
+// while RavenDB on it doesn't answer (clients get routed to a node that can't serve them) or a database
+// has no member left that serves it.
 func (e *evaluator) evalDegradingCase(cluster *ravendbv1.RavenDBCluster, res *ResourceFacts) conditionResult {
 
-	if c, ok := cluster.GetCondition(ravendbv1.ConditionBootstrapCompleted); ok &&
-		c.Status == metav1.ConditionFalse && c.Reason == string(ravendbv1.ReasonBootstrapFailed) {
-		return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonBootstrapFailed, message: "bootstrap job failed"}
+	if st := cluster.Status.Bootstrap; st != nil && st.Phase == ravendbv1.BootstrapPhaseFailed {
+		if step := currentBootstrapStep(st); step.Attempts >= bootstrapFailuresDegraded {
+			return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonBootstrapFailed, message: fmt.Sprintf("bootstrap step %s failed %d times: %s", step.Name, step.Attempts, step.Message)}
+		}
 	}
 
 	if res == nil {
@@ -270,6 +498,16 @@ func (e *evaluator) evalDegradingCase(cluster *ravendbv1.RavenDBCluster, res *Re
 		return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonPodsNotReady, message: "high restart count: " + joinNames(offendersPods)}
 	}
 
+	if silent := readyPodsNotServing(res); len(silent) > 0 {
+		return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonNodeUnreachable, message: "pods ready but RavenDB not answering on nodes: " + joinNames(silent)}
+	}
+
+	if res.RavenDB != nil {
+		if offline := offlineDatabases(res.RavenDB.Databases); len(offline) > 0 {
+			return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonDatabaseOffline, message: "no member serving databases: " + joinNames(offline)}
+		}
+	}
+
 	return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonCompleted, message: "no degradation detected"}
 }
 
@@ -370,6 +608,79 @@ func bucketizePodsByState(pods []PodFact) (pending, failed, unknown, notReady []
 	return
 }
 
+// bucketizeRavenDBNodes lists the nodes that don't answer, the ones that answer but aren't part of a cluster
+// with a leader (both as "TAG (why)") and the distinct server versions as "TAG=version" when they differ
+func bucketizeRavenDBNodes(nodes []RavenDBNodeFact) (unreachable, outside, versions []string) {
+	distinct := map[string]struct{}{}
+	for i := 0; i < len(nodes); i++ {
+		n := nodes[i]
+
+		switch {
+		case !n.Alive:
+			unreachable = append(unreachable, withWhy(n.Tag, n.Error))
+			continue
+		case n.Error != "":
+			outside = append(outside, withWhy(n.Tag, n.Error))
+			continue
+		case n.Leader == "":
+			outside = append(outside, withWhy(n.Tag, "no leader"))
+			continue
+		case n.Role == "":
+			outside = append(outside, withWhy(n.Tag, "not in its own topology"))
+			continue
+		}
+
+		if n.Version != "" {
+			distinct[n.Version] = struct{}{}
+		}
+	}
+
+	switch len(distinct) {
+	case 0:
+	case 1:
+		for v := range distinct {
+			versions = append(versions, v)
+		}
+	default:
+		for i := 0; i < len(nodes); i++ {
+			if nodes[i].Version != "" {
+				versions = append(versions, nodes[i].Tag+"="+nodes[i].Version)
+			}
+		}
+	}
+	return
+}
+
+// readyPodsNotServing returns the tags of the nodes whose pod is ready while /setup/alive fails
+func readyPodsNotServing(res *ResourceFacts) []string {
+	if res.RavenDB == nil {
+		return nil
+	}
+
+	ready := make(map[string]bool, len(res.Pods))
+	for i := 0; i < len(res.Pods); i++ {
+		if res.Pods[i].Ready {
+			ready[strings.ToUpper(res.Pods[i].Tag)] = true
+		}
+	}
+
+	var out []string
+	for i := 0; i < len(res.RavenDB.Nodes); i++ {
+		n := res.RavenDB.Nodes[i]
+		if !n.Alive && ready[n.Tag] {
+			out = append(out, withWhy(n.Tag, n.Error))
+		}
+	}
+	return out
+}
+
+func withWhy(tag, why string) string {
+	if why == "" {
+		return tag
+	}
+	return tag + " (" + why + ")"
+}
+
 func getIngressesStatus(ing []IngressFact) (observed bool, ready bool) {
 
 	for i := 0; i < len(ing); i++ {
@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package health
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ravendbv1 "ravendb-operator/api/v1"
+)
+
+func Test_E1_UpgradingFollowsTheControl(t *testing.T) {
+	plan := []ravendbv1.UpgradeNodeProgress{
+		{Tag: "B", Phase: ravendbv1.UpgradeNodeCompleted},
+		{Tag: "C", Phase: ravendbv1.UpgradeNodeUpgrading},
+		{Tag: "A", Phase: ravendbv1.UpgradeNodePending},
+	}
+	tests := []struct {
+		name       string
+		phase      ravendbv1.UpgradePhase
+		control    ravendbv1.UpgradeControl
+		current    string
+		wantStatus metav1.ConditionStatus
+		wantReason ravendbv1.ClusterConditionReason
+	}{
+		{"running", ravendbv1.UpgradePhaseRunning, ravendbv1.UpgradeControlProceed, "C", metav1.ConditionTrue, ravendbv1.ReasonUpgradeInProgress},
+		{"finishing a node before pausing", ravendbv1.UpgradePhaseRunning, ravendbv1.UpgradeControlPause, "C", metav1.ConditionTrue, ravendbv1.ReasonUpgradePausing},
+		{"finishing a node before aborting", ravendbv1.UpgradePhaseRunning, ravendbv1.UpgradeControlAbort, "C", metav1.ConditionTrue, ravendbv1.ReasonUpgradeAborting},
+		{"paused", ravendbv1.UpgradePhasePaused, ravendbv1.UpgradeControlPause, "", metav1.ConditionFalse, ravendbv1.ReasonUpgradePaused},
+		{"aborted", ravendbv1.UpgradePhaseAborted, ravendbv1.UpgradeControlAbort, "", metav1.ConditionFalse, ravendbv1.ReasonUpgradeAborted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &ravendbv1.RavenDBCluster{}
+			c.Status.Upgrade = &ravendbv1.UpgradeStatus{Phase: tt.phase, Control: tt.control, CurrentNode: tt.current, TargetImage: "ravendb/ravendb:6.2.5", Plan: plan}
+
+			r := (&evaluator{}).evalUpgrading(c)
+			require.Equal(t, tt.wantStatus, r.status)
+			require.Equal(t, tt.wantReason, r.reason)
+			require.Contains(t, r.message, "1/3 nodes on ravendb/ravendb:6.2.5")
+		})
+	}
+}
+
+func Test_E2_UnprobedNodesAreUnknown(t *testing.T) {
+	c := bootstrappedCluster("A", "B")
+	res := &ResourceFacts{RavenDBError: "get cert secret \"client\": not found"}
+	for _, n := range c.Spec.Nodes {
+		res.Pods = append(res.Pods, PodFact{Name: "ravendb-" + n.Tag, Tag: n.Tag, Phase: string(corev1.PodRunning), Ready: true})
+	}
+
+	(&evaluator{}).Evaluate(context.Background(), c, res, metav1.Now())
+	for _, ct := range []ravendbv1.ClusterConditionType{ravendbv1.ConditionNodesHealthy, ravendbv1.ConditionDatabasesHealthy} {
+		cond, ok := c.GetCondition(ct)
+		require.True(t, ok, ct)
+		require.Equal(t, metav1.ConditionUnknown, cond.Status, ct)
+		require.Equal(t, string(ravendbv1.ReasonProbeFailed), cond.Reason, ct)
+		require.Equal(t, `could not probe the RavenDB nodes: get cert secret "client": not found`, cond.Message, ct)
+	}
+	ready, _ := c.GetCondition(ravendbv1.ConditionReady)
+	require.Equal(t, metav1.ConditionFalse, ready.Status)
+
+	// before the bootstrap the ready pods are all there is to check
+	c = bootstrappedCluster("A", "B")
+	c.Status.Bootstrap = nil
+	res.RavenDBError = ""
+	r := (&evaluator{}).evalNodesHealthy(c, res)
+	require.Equal(t, metav1.ConditionTrue, r.status)
+	require.True(t, (&evaluator{}).evalDatabasesHealthy(c, res).skip)
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/ravendb"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// its purpose is to ask every node over HTTPS what clients would see: is it serving, which cluster
+// does it belong to and which server version does it run, plus how the databases of the cluster are doing.
+// the answers end up in ResourceFacts.RavenDB.
+
+// a node that doesn't answer within this is reported as unreachable, we don't hold the reconcile for it
+const ravendbProbeTimeout = 5 * time.Second
+
+// the nodes of a cluster are probed at most this often, the reconciles in between (e.g. the short requeues
+// of a rolling upgrade) get the last sample. a spec change probes again right away.
+const ravendbProbeInterval = 30 * time.Second
+
+type RavenDBCollector interface {
+	// Collect returns nil facts until the cluster is bootstrapped, before that the nodes aren't expected
+	// to be part of a cluster. the facts may be shared with later calls and must not be modified.
+	Collect(ctx context.Context, c client.Client, cluster *ravendbv1.RavenDBCluster) (*RavenDBFacts, error)
+	// Forget drops the last sample of a deleted cluster
+	Forget(key types.NamespacedName)
+}
+
+func NewRavenDBCollector() RavenDBCollector {
+	return &ravendbCollector{
+		buildClient: ravendb.NewForCluster,
+		interval:    ravendbProbeInterval,
+		samples:     map[types.NamespacedName]ravendbSample{},
+	}
+}
+
+type ravendbCollector struct {
+	buildClient func(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster, opts ...ravendb.Option) (*ravendb.Client, error)
+	// 0 probes on every Collect
+	interval time.Duration
+
+	mu      sync.Mutex
+	samples map[types.NamespacedName]ravendbSample
+}
+
+type ravendbSample struct {
+	at         time.Time
+	generation int64
+	facts      *RavenDBFacts
+}
+
+func (t *ravendbCollector) Collect(ctx context.Context, cli client.Client, cluster *ravendbv1.RavenDBCluster) (*RavenDBFacts, error) {
+
+	if !cluster.IsBootstrapped() || len(cluster.Spec.Nodes) == 0 {
+		return nil, nil
+	}
+
+	key := client.ObjectKeyFromObject(cluster)
+	if facts, ok := t.recent(key, cluster.Generation); ok {
+		return facts, nil
+	}
+	facts, err := t.probe(ctx, cli, cluster)
+	if err != nil {
+		return nil, err
+	}
+	t.remember(key, cluster.Generation, facts)
+	return facts, nil
+}
+
+func (t *ravendbCollector) Forget(key types.NamespacedName) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.samples, key)
+}
+
+// recent returns the last sample when it was taken within the interval for the same generation
+func (t *ravendbCollector) recent(key types.NamespacedName, generation int64) (*RavenDBFacts, bool) {
+	if t.interval <= 0 {
+		return nil, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, ok := t.samples[key]
+	if !ok || s.generation != generation || time.Since(s.at) >= t.interval {
+		return nil, false
+	}
+	return s.facts, true
+}
+
+func (t *ravendbCollector) remember(key types.NamespacedName, generation int64, facts *RavenDBFacts) {
+	if t.interval <= 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.samples[key] = ravendbSample{at: time.Now(), generation: generation, facts: facts}
+}
+
+// probe asks every node and /databases once
+func (t *ravendbCollector) probe(ctx context.Context, cli client.Client, cluster *ravendbv1.RavenDBCluster) (*RavenDBFacts, error) {
+
+	// a probe is answered or not, retrying only delays the reconcile
+	rc, err := t.buildClient(ctx, cli, cluster, ravendb.WithRetry(ravendb.RetryPolicy{Attempts: 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	facts := &RavenDBFacts{Nodes: make([]RavenDBNodeFact, len(cluster.Spec.Nodes))}
+
+	var wg sync.WaitGroup
+	for i := range cluster.Spec.Nodes {
+		wg.Add(1)
+		go func(i int, tag string) {
+			defer wg.Done()
+			facts.Nodes[i] = probeNode(ctx, rc, tag)
+		}(i, strings.ToUpper(cluster.Spec.Nodes[i].Tag))
+	}
+	wg.Wait()
+
+	dbCtx, cancel := context.WithTimeout(ctx, ravendbProbeTimeout)
+	defer cancel()
+	dbs, err := rc.Databases(dbCtx)
+	if err != nil {
+		facts.DatabasesError = probeError(err)
+		return facts, nil
+	}
+	facts.Databases = summarizeDatabases(dbs)
+
+	return facts, nil
+}
+
+// summarizeDatabases keeps what on-call wants to see per database, sorted by name
+func summarizeDatabases(dbs []ravendb.Database) []ravendbv1.DatabaseHealth {
+	out := make([]ravendbv1.DatabaseHealth, 0, len(dbs))
+	for _, db := range dbs {
+		topo := db.NodesTopology
+		h := ravendbv1.DatabaseHealth{
+			Name:              db.Name,
+			Disabled:          db.Disabled,
+			ReplicationFactor: db.ReplicationFactor,
+			Members:           nodeTags(topo.Members),
+			Promotables:       nodeTags(topo.Promotables),
+			Rehabs:            nodeTags(topo.Rehabs),
+		}
+
+		for _, tag := range topo.Tags() {
+			st, ok := topo.Status[tag]
+			if !ok || strings.EqualFold(strings.TrimSpace(st.LastStatus), "ok") {
+				continue
+			}
+			h.NodesInError = append(h.NodesInError, ravendbv1.DatabaseNodeError{
+				Tag:    tag,
+				Status: st.LastStatus,
+				Error:  firstLine(st.LastError),
+			})
+		}
+
+		out = append(out, h)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func nodeTags(nodes []ravendb.DatabaseNode) []string {
+	if len(nodes) == 0 {
+		return nil
+	}
+	tags := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		tags = append(tags, n.NodeTag)
+	}
+	return tags
+}
+
+// RavenDB reports the whole exception with its stack trace, the status only keeps the first line of it
+func firstLine(s string) string {
+	const maxLen = 200
+	s = strings.TrimSpace(s)
+	if i := strings.IndexAny(s, "\r\n"); i >= 0 {
+		s = s[:i]
+	}
+	if len(s) > maxLen {
+		s = s[:maxLen] + "..."
+	}
+	return s
+}
+
+func probeNode(ctx context.Context, rc *ravendb.Client, tag string) RavenDBNodeFact {
+	ctx, cancel := context.WithTimeout(ctx, ravendbProbeTimeout)
+	defer cancel()
+
+	fact := RavenDBNodeFact{Tag: tag}
+
+	if err := rc.Alive(ctx, tag); err != nil {
+		fact.Error = probeError(err)
+		return fact
+	}
+	fact.Alive = true
+
+	topo, err := rc.NodeTopology(ctx, tag)
+	if err != nil {
+		fact.Error = probeError(err)
+		return fact
+	}
+	fact.Leader = topo.Leader
+	fact.State = topo.CurrentState
+	fact.Role = topo.RoleOf(tag)
+
+	v, err := rc.BuildVersion(ctx, tag)
+	if err != nil {
+		fact.Error = probeError(err)
+		return fact
+	}
+	fact.Version = v.FullVersion
+
+	return fact
+}
+
+// probeError keeps the error short and stable, it ends up in the NodesHealthy message
+func probeError(err error) string {
+	var se *ravendb.StatusError
+	switch {
+	case errors.As(err, &se):
+		return fmt.Sprintf("HTTP %d", se.Code)
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timed out"
+	}
+	return err.Error()
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/ravendb"
+	"ravendb-operator/pkg/ravendb/fake"
+)
+
+func bootstrappedCluster(tags ...string) *ravendbv1.RavenDBCluster {
+	c := &ravendbv1.RavenDBCluster{}
+	for _, tag := range tags {
+		c.Spec.Nodes = append(c.Spec.Nodes, ravendbv1.RavenDBNode{Tag: tag, PublicServerUrl: fake.URL(tag)})
+	}
+	c.Status.Bootstrap = &ravendbv1.BootstrapStatus{Phase: ravendbv1.BootstrapPhaseCompleted}
+	return c
+}
+
+func collectFrom(t *testing.T, srv *fake.Server, c *ravendbv1.RavenDBCluster) *ResourceFacts {
+	t.Helper()
+	col := &ravendbCollector{
+		buildClient: func(context.Context, client.Client, *ravendbv1.RavenDBCluster, ...ravendb.Option) (*ravendb.Client, error) {
+			return srv.Client(), nil
+		},
+	}
+
+	res := &ResourceFacts{}
+	for _, n := range c.Spec.Nodes {
+		res.Pods = append(res.Pods, PodFact{Name: "ravendb-" + n.Tag, Tag: n.Tag, Phase: string(corev1.PodRunning), Ready: true})
+	}
+	var err error
+	res.RavenDB, err = col.Collect(context.Background(), nil, c)
+	require.NoError(t, err)
+	return res
+}
+
+func Test_H1_NodesServing(t *testing.T) {
+	srv := fake.New("A", "B")
+	c := bootstrappedCluster("A", "B")
+	res := collectFrom(t, srv, c)
+
+	require.Equal(t, RavenDBNodeFact{Tag: "B", Alive: true, Leader: "A", State: "Follower", Role: ravendbv1.NodeRoleMember, Version: "6.2.5"}, res.RavenDB.Nodes[1])
+
+	r := (&evaluator{}).evalNodesHealthy(c, res)
+	require.Equal(t, metav1.ConditionTrue, r.status)
+	require.Equal(t, "all nodes ready and serving on RavenDB 6.2.5", r.message)
+
+	srv.SetVersion("B", "6.2.6")
+	r = (&evaluator{}).evalNodesHealthy(c, collectFrom(t, srv, c))
+	require.Equal(t, metav1.ConditionTrue, r.status)
+	require.Equal(t, "all nodes ready and serving, mixed server versions: A=6.2.5, B=6.2.6", r.message)
+}
+
+func Test_H2_ReadyPodWithoutRavenDB(t *testing.T) {
+	srv := fake.New("A", "B")
+	srv.Fail("B", http.MethodGet, "/setup/alive", http.StatusServiceUnavailable, 1)
+	c := bootstrappedCluster("A", "B")
+	res := collectFrom(t, srv, c)
+
+	r := (&evaluator{}).evalNodesHealthy(c, res)
+	require.Equal(t, metav1.ConditionFalse, r.status)
+	require.Equal(t, ravendbv1.ReasonNodeUnreachable, r.reason)
+	require.Equal(t, "RavenDB not answering on nodes: B (HTTP 503)", r.message)
+
+	d := (&evaluator{}).evalDegradingCase(c, res)
+	require.Equal(t, metav1.ConditionTrue, d.status)
+	require.Equal(t, ravendbv1.ReasonNodeUnreachable, d.reason)
+}
+
+func Test_H3_NodeOutsideTheCluster(t *testing.T) {
+	srv := fake.New("A", "B")
+	srv.SetRole("B", "")
+	c := bootstrappedCluster("A", "B")
+
+	r := (&evaluator{}).evalNodesHealthy(c, collectFrom(t, srv, c))
+	require.Equal(t, metav1.ConditionFalse, r.status)
+	require.Equal(t, ravendbv1.ReasonNodeNotInCluster, r.reason)
+	require.Equal(t, "nodes not serving the cluster: B (no leader)", r.message)
+}
+
+func Test_H4_NotCollectedBeforeBootstrap(t *testing.T) {
+	srv := fake.New("A")
+	c := bootstrappedCluster("A")
+	c.Status.Bootstrap = nil
+
+	res := collectFrom(t, srv, c)
+	require.Nil(t, res.RavenDB)
+	require.Empty(t, srv.Requests())
+
+	r := (&evaluator{}).evalNodesHealthy(c, res)
+	require.Equal(t, "all node pods ready", r.message)
+}
+
+func Test_H5_DatabasesSummary(t *testing.T) {
+	srv := fake.New("A", "B", "C")
+	srv.SetDatabases(
+		ravendb.Database{
+			Name:              "orders",
+			ReplicationFactor: 3,
+			NodesTopology: ravendb.DatabaseTopology{
+				Members: []ravendb.DatabaseNode{{NodeTag: "A"}, {NodeTag: "B"}},
+				Rehabs:  []ravendb.DatabaseNode{{NodeTag: "C"}},
+				Status: map[string]ravendb.DatabaseNodeStatus{
+					"A": {LastStatus: "Ok"},
+					"B": {LastStatus: "NotResponding", LastError: "Raven.Server.Exceptions.DatabaseLoadFailureException: failed\n   at Raven.Server..."},
+				},
+			},
+		},
+		ravendb.Database{
+			Name:              "audit",
+			ReplicationFactor: 1,
+			NodesTopology: ravendb.DatabaseTopology{
+				Members: []ravendb.DatabaseNode{{NodeTag: "A"}},
+				Status:  map[string]ravendb.DatabaseNodeStatus{"A": {LastStatus: "Ok"}},
+			},
+		},
+	)
+	c := bootstrappedCluster("A", "B", "C")
+	res := collectFrom(t, srv, c)
+
+	cond := (&evaluator{}).evalDatabasesHealthy(c, res)
+	require.Equal(t, metav1.ConditionFalse, cond.status)
+	require.Equal(t, ravendbv1.ReasonDatabaseNodesInError, cond.reason)
+	require.Equal(t, "orders on B (NotResponding), C (rehab)", cond.message)
+
+	(&evaluator{}).recordDatabases(c, res)
+	require.Len(t, c.Status.Databases, 2)
+	require.Equal(t, "audit", c.Status.Databases[1].Name)
+	require.Equal(t, &ravendbv1.DatabasesSummary{Total: 2, Unhealthy: 1}, c.Status.DatabasesSummary)
+	require.Equal(t, ravendbv1.DatabaseHealth{
+		Name:              "orders",
+		ReplicationFactor: 3,
+		Members:           []string{"A", "B"},
+		Rehabs:            []string{"C"},
+		NodesInError:      []ravendbv1.DatabaseNodeError{{Tag: "B", Status: "NotResponding", Error: "Raven.Server.Exceptions.DatabaseLoadFailureException: failed"}},
+	}, c.Status.Databases[0])
+
+	d := (&evaluator{}).evalDegradingCase(c, res)
+	require.Equal(t, metav1.ConditionFalse, d.status)
+}
+
+func Test_H6_OfflineDatabaseDegradesTheCluster(t *testing.T) {
+	srv := fake.New("A", "B")
+	srv.SetDatabases(ravendb.Database{
+		Name:              "audit",
+		ReplicationFactor: 1,
+		NodesTopology: ravendb.DatabaseTopology{
+			Members: []ravendb.DatabaseNode{{NodeTag: "B"}},
+			Status:  map[string]ravendb.DatabaseNodeStatus{"B": {LastStatus: "NotResponding"}},
+		},
+	})
+	c := bootstrappedCluster("A", "B")
+	res := collectFrom(t, srv, c)
+
+	cond := (&evaluator{}).evalDatabasesHealthy(c, res)
+	require.Equal(t, ravendbv1.ReasonDatabaseOffline, cond.reason)
+
+	d := (&evaluator{}).evalDegradingCase(c, res)
+	require.Equal(t, metav1.ConditionTrue, d.status)
+	require.Equal(t, "no member serving databases: audit", d.message)
+
+	// a stale summary is kept when /databases can't be read
+	(&evaluator{}).recordDatabases(c, res)
+	srv.SetDown("A", true)
+	srv.SetDown("B", true)
+	res = collectFrom(t, srv, c)
+	(&evaluator{}).recordDatabases(c, res)
+	require.Len(t, c.Status.Databases, 1)
+	require.Equal(t, ravendbv1.ReasonDatabasesUnavailable, (&evaluator{}).evalDatabasesHealthy(c, res).reason)
+}
+
+func Test_H7_ProbesAreThrottled(t *testing.T) {
+	srv := fake.New("A", "B")
+	col := NewRavenDBCollector().(*ravendbCollector)
+	col.buildClient = func(context.Context, client.Client, *ravendbv1.RavenDBCluster, ...ravendb.Option) (*ravendb.Client, error) {
+		return srv.Client(), nil
+	}
+	c := bootstrappedCluster("A", "B")
+	c.Name, c.Namespace, c.Generation = "db", "ravendb", 1
+
+	first, err := col.Collect(context.Background(), nil, c)
+	require.NoError(t, err)
+	sent := len(srv.Requests())
+	require.NotZero(t, sent)
+
+	// a requeue right after gets the same sample
+	again, err := col.Collect(context.Background(), nil, c)
+	require.NoError(t, err)
+	require.Same(t, first, again)
+	require.Len(t, srv.Requests(), sent)
+
+	// a spec change probes again, so does an old sample
+	c.Generation++
+	_, err = col.Collect(context.Background(), nil, c)
+	require.NoError(t, err)
+	require.Len(t, srv.Requests(), 2*sent)
+
+	col.interval = time.Millisecond
+	time.Sleep(2 * time.Millisecond)
+	_, err = col.Collect(context.Background(), nil, c)
+	require.NoError(t, err)
+	require.Len(t, srv.Requests(), 3*sent)
+
+	col.Forget(client.ObjectKeyFromObject(c))
+	require.Empty(t, col.samples)
+}
+
+func Test_H8_DatabasesInStatusAreCapped(t *testing.T) {
+	var dbs []ravendb.Database
+	for i := 0; i < ravendbv1.MaxDatabasesInStatus+10; i++ {
+		db := ravendb.Database{
+			Name:              fmt.Sprintf("db%03d", i),
+			ReplicationFactor: 1,
+			NodesTopology:     ravendb.DatabaseTopology{Members: []ravendb.DatabaseNode{{NodeTag: "A"}}},
+		}
+		if i%20 == 19 {
+			db.NodesTopology.Status = map[string]ravendb.DatabaseNodeStatus{"A": {LastStatus: "NotResponding"}}
+		}
+		dbs = append(dbs, db)
+	}
+	srv := fake.New("A")
+	srv.SetDatabases(dbs...)
+	c := bootstrappedCluster("A")
+
+	(&evaluator{}).recordDatabases(c, collectFrom(t, srv, c))
+	require.Len(t, c.Status.Databases, ravendbv1.MaxDatabasesInStatus)
+	require.Equal(t, []string{"db019", "db039", "db059"}, []string{c.Status.Databases[0].Name, c.Status.Databases[1].Name, c.Status.Databases[2].Name})
+	require.Equal(t, "db000", c.Status.Databases[3].Name)
+	require.Equal(t, &ravendbv1.DatabasesSummary{Total: 60, Unhealthy: 3, Omitted: 10}, c.Status.DatabasesSummary)
+}
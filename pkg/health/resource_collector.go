@@ -20,11 +20,14 @@ import (
 	"context"
 
 	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/naming"
 
 	appsv1 "k8s.io/api/apps/v1"
-	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -48,7 +51,6 @@ func (t *resourceCollector) Collect(ctx context.Context, cli client.Client, clus
 		PVCs:         make([]PVCFact, 0),
 		Services:     make([]ServiceFact, 0),
 		Ingresses:    make([]IngressFact, 0),
-		Jobs:         make([]JobFact, 0),
 		Secrets:      make([]SecretFact, 0),
 	}
 
@@ -58,12 +60,6 @@ func (t *resourceCollector) Collect(ctx context.Context, cli client.Client, clus
 	}
 	facts.StatefulSets = ssFacts
 
-	jobFacts, err := collectJobs(ctx, cli, ns, cluster)
-	if err != nil {
-		return facts, err
-	}
-	facts.Jobs = jobFacts
-
 	podFacts, ownedPodUIDs, claimedPVCNames, err := collectPodsAndPVCRefs(ctx, cli, ns, ownedSSUIDs)
 	if err != nil {
 		return facts, err
@@ -88,6 +84,12 @@ func (t *resourceCollector) Collect(ctx context.Context, cli client.Client, clus
 	}
 	facts.Ingresses = ingFacts
 
+	pdbFact, err := collectDisruptionBudget(ctx, cli, ns, cluster)
+	if err != nil {
+		return facts, err
+	}
+	facts.DisruptionBudget = pdbFact
+
 	secFacts, err := collectSecrets(ctx, cli, ns)
 	if err != nil {
 		return facts, err
@@ -138,35 +140,6 @@ func collectStatefulSets(ctx context.Context, cli client.Client, ns string, clus
 	return facts, owned, nil
 }
 
-func collectJobs(ctx context.Context, cli client.Client, ns string, cluster *ravendbv1.RavenDBCluster) ([]JobFact, error) {
-
-	var list batchv1.JobList
-	if err := cli.List(ctx, &list, client.InNamespace(ns)); err != nil {
-		return nil, err
-	}
-
-	facts := make([]JobFact, 0, len(list.Items))
-	for i := 0; i < len(list.Items); i++ {
-		j := &list.Items[i]
-		if !isOwnedByCluster(j.OwnerReferences, cluster) {
-			continue
-		}
-
-		jobSucceeded := j.Status.Succeeded >= 1
-
-		facts = append(facts, JobFact{
-			Name:      j.Name,
-			Namespace: j.Namespace,
-			Succeeded: jobSucceeded,
-			Active:    j.Status.Active,
-			Failed:    j.Status.Failed,
-			Completed: jobSucceeded,
-		})
-	}
-
-	return facts, nil
-}
-
 func collectPodsAndPVCRefs(ctx context.Context, cli client.Client, ns string, ownedSSUIDs map[string]struct{}) ([]PodFact, map[string]struct{}, map[string]struct{}, error) {
 
 	var list corev1.PodList
@@ -188,9 +161,11 @@ func collectPodsAndPVCRefs(ctx context.Context, cli client.Client, ns string, ow
 		podFacts = append(podFacts, PodFact{
 			Name:      p.Name,
 			Namespace: p.Namespace,
+			Tag:       p.Labels[common.LabelNodeTag],
 			Phase:     string(p.Status.Phase),
 			Ready:     isPodReady(p),
 			Restarts:  getPodsContainersTotalRestarts(p),
+			Image:     ravendbImage(p),
 		})
 
 		for _, vol := range p.Spec.Volumes {
@@ -308,6 +283,30 @@ func collectIngresses(ctx context.Context, cli client.Client, ns string, cluster
 	return facts, nil
 }
 
+func collectDisruptionBudget(ctx context.Context, cli client.Client, ns string, cluster *ravendbv1.RavenDBCluster) (*PodDisruptionBudgetFact, error) {
+
+	var pdb policyv1.PodDisruptionBudget
+	err := cli.Get(ctx, client.ObjectKey{Namespace: ns, Name: naming.For(cluster).PodDisruptionBudget()}, &pdb)
+	if kerrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !isOwnedByCluster(pdb.OwnerReferences, cluster) {
+		return nil, nil
+	}
+
+	return &PodDisruptionBudgetFact{
+		Name:               pdb.Name,
+		Namespace:          pdb.Namespace,
+		ExpectedPods:       pdb.Status.ExpectedPods,
+		CurrentHealthy:     pdb.Status.CurrentHealthy,
+		DesiredHealthy:     pdb.Status.DesiredHealthy,
+		DisruptionsAllowed: pdb.Status.DisruptionsAllowed,
+	}, nil
+}
+
 func collectSecrets(ctx context.Context, cli client.Client, ns string) ([]SecretFact, error) {
 
 	var secretList corev1.SecretList
@@ -356,6 +355,15 @@ func isPodReady(p *corev1.Pod) bool {
 	return false
 }
 
+func ravendbImage(p *corev1.Pod) string {
+	for i := 0; i < len(p.Spec.Containers); i++ {
+		if p.Spec.Containers[i].Name == common.App {
+			return p.Spec.Containers[i].Image
+		}
+	}
+	return ""
+}
+
 func getPodsContainersTotalRestarts(p *corev1.Pod) int32 {
 	var sum int32
 
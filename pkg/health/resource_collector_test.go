@@ -0,0 +1,52 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package health
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ravendbv1 "ravendb-operator/api/v1"
+)
+
+func Test_R1_DisruptionBudgetFact(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+
+	c := &ravendbv1.RavenDBCluster{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "ravendb", UID: "uid-db"}}
+	owner := metav1.OwnerReference{Kind: "RavenDBCluster", Name: "db", UID: "uid-db"}
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "ravendb", OwnerReferences: []metav1.OwnerReference{owner}},
+		Status:     policyv1.PodDisruptionBudgetStatus{ExpectedPods: 3, CurrentHealthy: 3, DesiredHealthy: 2, DisruptionsAllowed: 1},
+	}
+
+	kc := fakeclient.NewClientBuilder().WithScheme(scheme).Build()
+	facts, err := NewResourceCollector().Collect(context.Background(), kc, c)
+	require.NoError(t, err)
+	require.Nil(t, facts.DisruptionBudget)
+
+	kc = fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(pdb).Build()
+	facts, err = NewResourceCollector().Collect(context.Background(), kc, c)
+	require.NoError(t, err)
+	require.Equal(t, &PodDisruptionBudgetFact{Name: "db", Namespace: "ravendb", ExpectedPods: 3, CurrentHealthy: 3, DesiredHealthy: 2, DisruptionsAllowed: 1}, facts.DisruptionBudget)
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package membership
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/ravendb"
+)
+
+// clientCertificateName is how the operator's client certificate shows up in the studio
+const clientCertificateName = "ravendb-operator"
+
+// Bootstrapper forms the RavenDB cluster out of the spec nodes, until then every node runs as a
+// standalone (passive) server. everything is done over the REST API from the operator, nothing is
+// downloaded into or exec'd in the pods.
+type Bootstrapper interface {
+	// Run advances the bootstrap and returns the new status.bootstrap, and whether the bootstrap
+	// isn't completed yet, so the caller can requeue.
+	Run(ctx context.Context, cluster *ravendbv1.RavenDBCluster, kc client.Client) (*ravendbv1.BootstrapStatus, bool, error)
+}
+
+type bootstrapper struct {
+	rec               record.EventRecorder
+	buildClient       func(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster, opts ...ravendb.Option) (*ravendb.Client, error)
+	buildServerClient func(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster, tag string, opts ...ravendb.Option) (*ravendb.Client, error)
+	clientCert        func(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster) (*x509.Certificate, error)
+}
+
+func NewBootstrapper(rec record.EventRecorder) Bootstrapper {
+	return &bootstrapper{
+		rec:               rec,
+		buildClient:       ravendb.NewForCluster,
+		buildServerClient: ravendb.NewForClusterWithServerCert,
+		clientCert:        ravendb.ClientCertificate,
+	}
+}
+
+// Run performs one bootstrap "tick". the steps run in order (see ravendbv1.BootstrapSteps) and each one
+// checks first whether its work is already done, so running a step again is always safe:
+//  1. WaitForNodes: every spec node answers /setup/alive.
+//  2. RegisterClientCertificate: the seed node (the first member in spec.nodes) trusts the operator's
+//     client certificate as a cluster admin. the request is authenticated with the seed's own server
+//     certificate, and the first registered certificate also turns the seed into a single node cluster.
+//  3. JoinMembers: the other members are added through the leader with PUT /admin/cluster/node,
+//     one node per tick.
+//  4. JoinWatchers: same for the watchers.
+//
+// a failed step is retried on the next tick. a cluster that was bootstrapped by the cluster-init Job
+// of an older operator is just marked Completed.
+func (b *bootstrapper) Run(ctx context.Context, cluster *ravendbv1.RavenDBCluster, kc client.Client) (*ravendbv1.BootstrapStatus, bool, error) {
+	st := cluster.Status.Bootstrap.DeepCopy()
+
+	if st == nil && cluster.IsBootstrapped() {
+		return &ravendbv1.BootstrapStatus{Phase: ravendbv1.BootstrapPhaseCompleted}, false, nil
+	}
+	if st != nil && st.Phase == ravendbv1.BootstrapPhaseCompleted {
+		return st, false, nil
+	}
+	if len(cluster.Spec.Nodes) == 0 {
+		return st, false, nil
+	}
+
+	now := metav1.Now()
+	if st == nil {
+		st = &ravendbv1.BootstrapStatus{Phase: ravendbv1.BootstrapPhaseRunning, StartTime: &now}
+	}
+	alignSteps(st)
+
+	for i := range st.Steps {
+		step := &st.Steps[i]
+		if step.Phase == ravendbv1.BootstrapStepCompleted {
+			continue
+		}
+
+		st.CurrentStep = step.Name
+		if step.StartTime == nil {
+			step.StartTime = &now
+		}
+
+		done, msg, err := b.runStep(ctx, cluster, kc, step.Name)
+		if err != nil {
+			step.Phase = ravendbv1.BootstrapStepFailed
+			step.Message = err.Error()
+			step.Attempts++
+			st.Phase = ravendbv1.BootstrapPhaseFailed
+			b.event(cluster, corev1.EventTypeWarning, "BootstrapStepFailed", "Bootstrap step %s failed (attempt %d): %v", step.Name, step.Attempts, err)
+			return st, true, err
+		}
+
+		st.Phase = ravendbv1.BootstrapPhaseRunning
+		step.Message = msg
+		if !done {
+			step.Phase = ravendbv1.BootstrapStepRunning
+			return st, true, nil
+		}
+
+		step.Phase = ravendbv1.BootstrapStepCompleted
+		step.CompletionTime = &now
+		b.event(cluster, corev1.EventTypeNormal, "BootstrapStepCompleted", "Bootstrap step %s completed", step.Name)
+	}
+
+	st.Phase = ravendbv1.BootstrapPhaseCompleted
+	st.CurrentStep = ""
+	st.CompletionTime = &now
+	b.event(cluster, corev1.EventTypeNormal, "BootstrapCompleted", "RavenDB cluster bootstrapped with %d nodes", len(cluster.Spec.Nodes))
+	return st, false, nil
+}
+
+// runStep returns whether the step is done, or what it waits for
+func (b *bootstrapper) runStep(ctx context.Context, cluster *ravendbv1.RavenDBCluster, kc client.Client, step ravendbv1.BootstrapStep) (bool, string, error) {
+	switch step {
+	case ravendbv1.BootstrapStepWaitForNodes:
+		return b.waitForNodes(ctx, cluster, kc)
+	case ravendbv1.BootstrapStepRegisterClientCertificate:
+		return b.registerClientCertificate(ctx, cluster, kc)
+	case ravendbv1.BootstrapStepJoinMembers:
+		return b.joinNodes(ctx, cluster, kc, false)
+	case ravendbv1.BootstrapStepJoinWatchers:
+		return b.joinNodes(ctx, cluster, kc, true)
+	}
+	return false, "", fmt.Errorf("unknown bootstrap step %q", step)
+}
+
+// waitForNodes probes every node with the seed's server certificate, the client certificate isn't
+// trusted by anyone yet
+func (b *bootstrapper) waitForNodes(ctx context.Context, cluster *ravendbv1.RavenDBCluster, kc client.Client) (bool, string, error) {
+	rc, err := b.buildServerClient(ctx, kc, cluster, seedNode(cluster).Tag)
+	if err != nil {
+		return false, "", err
+	}
+
+	var waiting []string
+	for _, n := range cluster.Spec.Nodes {
+		if err := rc.Alive(ctx, n.Tag); err != nil {
+			waiting = append(waiting, n.Tag)
+		}
+	}
+	if len(waiting) > 0 {
+		return false, "waiting for /setup/alive on " + strings.Join(waiting, ", "), nil
+	}
+	return true, "all nodes are up", nil
+}
+
+func (b *bootstrapper) registerClientCertificate(ctx context.Context, cluster *ravendbv1.RavenDBCluster, kc client.Client) (bool, string, error) {
+	seed := seedNode(cluster)
+
+	cert, err := b.clientCert(ctx, kc, cluster)
+	if err != nil {
+		return false, "", err
+	}
+	rc, err := b.buildServerClient(ctx, kc, cluster, seed.Tag)
+	if err != nil {
+		return false, "", err
+	}
+
+	tp := ravendb.Thumbprint(cert)
+	registered, err := rc.CertificateRegistered(ctx, seed.Tag, tp)
+	if err != nil {
+		return false, "", err
+	}
+	if registered {
+		return true, fmt.Sprintf("client certificate %s is registered", tp), nil
+	}
+
+	if err := rc.RegisterCertificate(ctx, seed.Tag, clientCertificateName, cert.Raw); err != nil {
+		return false, "", err
+	}
+	return true, fmt.Sprintf("client certificate %s registered on node %s", tp, seed.Tag), nil
+}
+
+// joinNodes adds the members (or the watchers) that aren't part of the topology yet, one per tick
+func (b *bootstrapper) joinNodes(ctx context.Context, cluster *ravendbv1.RavenDBCluster, kc client.Client, watcher bool) (bool, string, error) {
+	rc, err := b.buildClient(ctx, kc, cluster)
+	if err != nil {
+		return false, "", err
+	}
+
+	topo, err := rc.Topology(ctx)
+	if err != nil {
+		return false, "", err
+	}
+
+	var joined []string
+	for _, n := range cluster.Spec.Nodes {
+		if n.Watcher != watcher {
+			continue
+		}
+		if topo.RoleOf(n.Tag) != "" {
+			joined = append(joined, n.Tag)
+			continue
+		}
+
+		if err := rc.Alive(ctx, n.Tag); err != nil {
+			return false, fmt.Sprintf("waiting for /setup/alive on %s: %v", n.Tag, err), nil
+		}
+		if err := rc.AddNode(ctx, n.PublicServerUrl, n.Tag, watcher); err != nil {
+			return false, "", fmt.Errorf("add node %s: %w", n.Tag, err)
+		}
+		b.event(cluster, corev1.EventTypeNormal, "NodeJoinRequested", "Adding node %s to the cluster as %s", n.Tag, roleName(watcher))
+		return false, fmt.Sprintf("join of node %s requested", n.Tag), nil
+	}
+
+	if len(joined) == 0 {
+		return true, fmt.Sprintf("no %ss", strings.ToLower(roleName(watcher))), nil
+	}
+	return true, fmt.Sprintf("%ss joined: %s", strings.ToLower(roleName(watcher)), strings.Join(joined, ", ")), nil
+}
+
+// alignSteps makes sure st has one entry per bootstrap step, in order. steps that are already known keep
+// their progress.
+func alignSteps(st *ravendbv1.BootstrapStatus) {
+	prev := make(map[ravendbv1.BootstrapStep]ravendbv1.BootstrapStepStatus, len(st.Steps))
+	for _, s := range st.Steps {
+		prev[s.Name] = s
+	}
+
+	steps := make([]ravendbv1.BootstrapStepStatus, 0, len(ravendbv1.BootstrapSteps))
+	for _, name := range ravendbv1.BootstrapSteps {
+		s, ok := prev[name]
+		if !ok {
+			s = ravendbv1.BootstrapStepStatus{Name: name, Phase: ravendbv1.BootstrapStepPending}
+		}
+		steps = append(steps, s)
+	}
+	st.Steps = steps
+}
+
+// seedNode is the node the cluster is formed on: the first member in spec.nodes
+func seedNode(cluster *ravendbv1.RavenDBCluster) ravendbv1.RavenDBNode {
+	for _, n := range cluster.Spec.Nodes {
+		if !n.Watcher {
+			return n
+		}
+	}
+	return cluster.Spec.Nodes[0]
+}
+
+func (b *bootstrapper) event(cluster *ravendbv1.RavenDBCluster, eventType, reason, format string, args ...any) {
+	if b.rec == nil {
+		return
+	}
+	b.rec.Eventf(cluster, eventType, reason, format, args...)
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package membership
+
+import (
+	"context"
+	"crypto/x509"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/ravendb"
+	"ravendb-operator/pkg/ravendb/fake"
+)
+
+var operatorCert = &x509.Certificate{Raw: []byte("operator client certificate")}
+
+// a cluster that was never bootstrapped, watchers are given in lower case
+func newCluster(tags ...string) *ravendbv1.RavenDBCluster {
+	c := &ravendbv1.RavenDBCluster{}
+	for _, tag := range tags {
+		watcher := strings.ToLower(tag) == tag
+		tag = strings.ToUpper(tag)
+		c.Spec.Nodes = append(c.Spec.Nodes, ravendbv1.RavenDBNode{Tag: tag, PublicServerUrl: fake.URL(tag), Watcher: watcher})
+	}
+	return c
+}
+
+// standalone returns a server where only the seed (the first tag) runs as a (single node) cluster
+func standalone(tags ...string) *fake.Server {
+	srv := fake.New(tags...)
+	for _, tag := range tags[1:] {
+		srv.SetRole(tag, "")
+	}
+	return srv
+}
+
+func newTestBootstrapper(srv *fake.Server) (*bootstrapper, *record.FakeRecorder) {
+	rec := record.NewFakeRecorder(50)
+	return &bootstrapper{
+		rec:         rec,
+		buildClient: fakeClient(srv),
+		buildServerClient: func(context.Context, client.Client, *ravendbv1.RavenDBCluster, string, ...ravendb.Option) (*ravendb.Client, error) {
+			return srv.Client(), nil
+		},
+		clientCert: func(context.Context, client.Client, *ravendbv1.RavenDBCluster) (*x509.Certificate, error) {
+			return operatorCert, nil
+		},
+	}, rec
+}
+
+// tick runs the bootstrapper once and stores the result in the cluster status, as the controller does
+func tick(t *testing.T, b *bootstrapper, c *ravendbv1.RavenDBCluster) (*ravendbv1.BootstrapStatus, bool, error) {
+	t.Helper()
+	st, pending, err := b.Run(context.Background(), c, nil)
+	c.Status.Bootstrap = st
+	return st, pending, err
+}
+
+// resumedAt is a status persisted while step ran, the steps before it completed
+func resumedAt(step ravendbv1.BootstrapStep) *ravendbv1.BootstrapStatus {
+	now := metav1.Now()
+	st := &ravendbv1.BootstrapStatus{Phase: ravendbv1.BootstrapPhaseRunning, StartTime: &now, CurrentStep: step}
+	for _, name := range ravendbv1.BootstrapSteps {
+		if name == step {
+			st.Steps = append(st.Steps, ravendbv1.BootstrapStepStatus{Name: name, Phase: ravendbv1.BootstrapStepRunning, StartTime: &now})
+			break
+		}
+		st.Steps = append(st.Steps, ravendbv1.BootstrapStepStatus{Name: name, Phase: ravendbv1.BootstrapStepCompleted, StartTime: &now, CompletionTime: &now})
+	}
+	return st
+}
+
+func requestsTo(srv *fake.Server, route string) int {
+	n := 0
+	for _, r := range srv.Requests() {
+		if strings.HasSuffix(r, route) {
+			n++
+		}
+	}
+	return n
+}
+
+func stepPhases(st *ravendbv1.BootstrapStatus) []ravendbv1.BootstrapStepPhase {
+	var out []ravendbv1.BootstrapStepPhase
+	for _, s := range st.Steps {
+		out = append(out, s.Phase)
+	}
+	return out
+}
+
+func Test_BS1_FreshClusterIsFormedOneNodePerTick(t *testing.T) {
+	srv := standalone("A", "B", "C", "D")
+	c := newCluster("A", "B", "C", "d")
+	b, rec := newTestBootstrapper(srv)
+
+	st, pending, err := tick(t, b, c)
+	require.NoError(t, err)
+	require.True(t, pending)
+	require.Equal(t, ravendbv1.BootstrapStepJoinMembers, st.CurrentStep)
+	require.Equal(t, map[string]string{ravendb.Thumbprint(operatorCert): clientCertificateName}, srv.Certificates())
+	require.Equal(t, ravendbv1.NodeRoleMember, srv.Role("B"))
+	require.Equal(t, ravendbv1.NodeClusterRole(""), srv.Role("C"))
+
+	_, pending, err = tick(t, b, c)
+	require.NoError(t, err)
+	require.True(t, pending)
+	require.Equal(t, ravendbv1.NodeRoleMember, srv.Role("C"))
+	require.Equal(t, ravendbv1.NodeClusterRole(""), srv.Role("D"))
+
+	st, pending, err = tick(t, b, c)
+	require.NoError(t, err)
+	require.True(t, pending)
+	require.Equal(t, ravendbv1.BootstrapStepJoinWatchers, st.CurrentStep)
+	require.Equal(t, ravendbv1.NodeRoleWatcher, srv.Role("D"))
+
+	st, pending, err = tick(t, b, c)
+	require.NoError(t, err)
+	require.False(t, pending)
+	require.Equal(t, ravendbv1.BootstrapPhaseCompleted, st.Phase)
+	require.Empty(t, st.CurrentStep)
+	require.NotNil(t, st.CompletionTime)
+	require.Equal(t, "watchers joined: D", st.Steps[3].Message)
+	require.True(t, c.IsBootstrapped())
+	require.Equal(t, 3, requestsTo(srv, "PUT /admin/cluster/node"))
+
+	// a completed bootstrap does nothing
+	before := len(srv.Requests())
+	_, pending, err = tick(t, b, c)
+	require.NoError(t, err)
+	require.False(t, pending)
+	require.Len(t, srv.Requests(), before)
+
+	var completed []string
+	for len(rec.Events) > 0 {
+		if e := <-rec.Events; strings.Contains(e, "BootstrapCompleted") {
+			completed = append(completed, e)
+		}
+	}
+	require.Equal(t, []string{"Normal BootstrapCompleted RavenDB cluster bootstrapped with 4 nodes"}, completed)
+}
+
+func Test_BS2_BootstrapResumesFromThePersistedStep(t *testing.T) {
+	for _, step := range ravendbv1.BootstrapSteps {
+		t.Run(string(step), func(t *testing.T) {
+			srv := standalone("A", "B", "C")
+			c := newCluster("A", "B", "c")
+			c.Status.Bootstrap = resumedAt(step)
+			b, _ := newTestBootstrapper(srv)
+
+			for i := 0; i < 5 && !c.IsBootstrapped(); i++ {
+				_, _, err := tick(t, b, c)
+				require.NoError(t, err)
+			}
+			require.True(t, c.IsBootstrapped())
+			require.Equal(t, []ravendbv1.BootstrapStepPhase{
+				ravendbv1.BootstrapStepCompleted, ravendbv1.BootstrapStepCompleted,
+				ravendbv1.BootstrapStepCompleted, ravendbv1.BootstrapStepCompleted,
+			}, stepPhases(c.Status.Bootstrap))
+
+			// the completed steps are not run again
+			registers := step == ravendbv1.BootstrapStepWaitForNodes || step == ravendbv1.BootstrapStepRegisterClientCertificate
+			require.Equal(t, registers, requestsTo(srv, "GET /certificates") > 0)
+			joinsMembers := step != ravendbv1.BootstrapStepJoinWatchers
+			require.Equal(t, joinsMembers, srv.Role("B") == ravendbv1.NodeRoleMember)
+			require.Equal(t, ravendbv1.NodeRoleWatcher, srv.Role("C"))
+		})
+	}
+}
+
+func Test_BS3_RegisteredCertificateIsNotRegisteredAgain(t *testing.T) {
+	srv := standalone("A")
+	require.NoError(t, srv.Client().RegisterCertificate(context.Background(), "A", "added by hand", operatorCert.Raw))
+	c := newCluster("A")
+	b, _ := newTestBootstrapper(srv)
+
+	st, pending, err := tick(t, b, c)
+	require.NoError(t, err)
+	require.False(t, pending)
+	require.Equal(t, ravendbv1.BootstrapPhaseCompleted, st.Phase)
+	require.Equal(t, 1, requestsTo(srv, "PUT /admin/certificates"))
+	require.Equal(t, "added by hand", srv.Certificates()[ravendb.Thumbprint(operatorCert)])
+	require.Equal(t, "client certificate "+ravendb.Thumbprint(operatorCert)+" is registered", st.Steps[1].Message)
+}
+
+func Test_BS4_JoinedAndPromotableMembersAreNotAddedAgain(t *testing.T) {
+	srv := standalone("A", "B", "C")
+	srv.SetRole("B", ravendbv1.NodeRoleMember)
+	srv.SetRole("C", ravendbv1.NodeRolePromotable)
+	c := newCluster("A", "B", "C")
+	b, _ := newTestBootstrapper(srv)
+
+	st, pending, err := tick(t, b, c)
+	require.NoError(t, err)
+	require.False(t, pending)
+	require.Equal(t, ravendbv1.BootstrapPhaseCompleted, st.Phase)
+	require.Zero(t, requestsTo(srv, "PUT /admin/cluster/node"))
+	require.Equal(t, "members joined: A, B, C", st.Steps[2].Message)
+	require.Equal(t, ravendbv1.NodeRolePromotable, srv.Role("C"))
+}
+
+func Test_BS5_FailedStepIsRetried(t *testing.T) {
+	srv := standalone("A", "B")
+	srv.Fail("A", "PUT", "/admin/certificates", 500, 1)
+	c := newCluster("A", "B")
+	b, rec := newTestBootstrapper(srv)
+
+	st, pending, err := tick(t, b, c)
+	require.Error(t, err)
+	require.True(t, pending)
+	require.Equal(t, ravendbv1.BootstrapPhaseFailed, st.Phase)
+	require.Equal(t, ravendbv1.BootstrapStepRegisterClientCertificate, st.CurrentStep)
+	require.Equal(t, ravendbv1.BootstrapStepFailed, st.Steps[1].Phase)
+	require.Equal(t, int32(1), st.Steps[1].Attempts)
+	require.Equal(t, ravendbv1.BootstrapStepCompleted, st.Steps[0].Phase)
+	require.Contains(t, <-rec.Events, "Bootstrap step WaitForNodes completed")
+	require.Equal(t, "Warning BootstrapStepFailed Bootstrap step RegisterClientCertificate failed (attempt 1): "+st.Steps[1].Message, <-rec.Events)
+
+	st, pending, err = tick(t, b, c)
+	require.NoError(t, err)
+	require.True(t, pending)
+	require.Equal(t, ravendbv1.BootstrapPhaseRunning, st.Phase)
+	require.Equal(t, ravendbv1.BootstrapStepCompleted, st.Steps[1].Phase)
+	require.Len(t, srv.Certificates(), 1)
+
+	st, pending, err = tick(t, b, c)
+	require.NoError(t, err)
+	require.False(t, pending)
+	require.Equal(t, ravendbv1.BootstrapPhaseCompleted, st.Phase)
+}
+
+func Test_BS6_ClusterBootstrappedByTheInitJobIsCompleted(t *testing.T) {
+	srv := fake.New("A", "B")
+	c := newCluster("A", "B")
+	c.SetBootstrapped(metav1.Now())
+	b, _ := newTestBootstrapper(srv)
+
+	st, pending, err := tick(t, b, c)
+	require.NoError(t, err)
+	require.False(t, pending)
+	require.Equal(t, &ravendbv1.BootstrapStatus{Phase: ravendbv1.BootstrapPhaseCompleted}, st)
+	require.Empty(t, srv.Requests())
+}
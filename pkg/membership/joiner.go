@@ -0,0 +1,205 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package membership
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/ravendb"
+)
+
+// Joiner brings nodes that were appended to spec.nodes into the RavenDB (Raft) cluster.
+// the initial cluster is formed by the bootstrapper, so the joiner only acts once the cluster is bootstrapped.
+type Joiner interface {
+	// Run fills the join fields of statuses (one entry per spec node, in spec order) and reports
+	// whether some node still has to join, so the caller can requeue.
+	Run(ctx context.Context, cluster *ravendbv1.RavenDBCluster, kc client.Client, statuses []ravendbv1.RavenDBNodeStatus) ([]ravendbv1.RavenDBNodeStatus, bool, error)
+}
+
+type joiner struct {
+	rec         record.EventRecorder
+	buildClient func(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster, opts ...ravendb.Option) (*ravendb.Client, error)
+}
+
+func NewJoiner(rec record.EventRecorder) Joiner {
+	return &joiner{
+		rec:         rec,
+		buildClient: ravendb.NewForCluster,
+	}
+}
+
+// Run performs one join "tick":
+//  1. read the cluster topology from any reachable node that knows the leader.
+//  2. nodes already in the topology are marked Joined (or Joining while still promotable).
+//  3. the first node that is missing gets probed on /setup/alive and, once up, is added
+//     through the leader with PUT /admin/cluster/node. one node per tick keeps Raft changes serialized.
+//     a node that had joined and dropped out again is marked Left instead, and only added back
+//     with spec.topology.autoRejoin.
+func (j *joiner) Run(ctx context.Context, cluster *ravendbv1.RavenDBCluster, kc client.Client, statuses []ravendbv1.RavenDBNodeStatus) ([]ravendbv1.RavenDBNodeStatus, bool, error) {
+	out := alignStatuses(cluster, statuses)
+
+	if !cluster.IsBootstrapped() || len(cluster.Spec.Nodes) == 0 {
+		return out, false, nil
+	}
+
+	rc, err := j.buildClient(ctx, kc, cluster)
+	if err != nil {
+		return out, true, err
+	}
+
+	topo, err := rc.Topology(ctx)
+	if err != nil {
+		return out, true, err
+	}
+
+	pending := false
+	requested := false
+
+	for i, node := range cluster.Spec.Nodes {
+		st := &out[i]
+		prevPhase := st.JoinPhase
+
+		role := topo.RoleOf(node.Tag)
+		switch role {
+		case ravendbv1.NodeRoleMember, ravendbv1.NodeRoleWatcher:
+			st.JoinPhase = ravendbv1.NodeJoinJoined
+			st.Role = role
+			st.JoinMessage = ""
+			if prevPhase != "" && prevPhase != ravendbv1.NodeJoinJoined {
+				j.event(cluster, corev1.EventTypeNormal, "NodeJoined", "Node %s joined the cluster as %s", node.Tag, role)
+			}
+			continue
+
+		case ravendbv1.NodeRolePromotable:
+			pending = true
+			st.JoinPhase = ravendbv1.NodeJoinJoining
+			st.Role = role
+			st.JoinMessage = "waiting for the node to catch up and get promoted"
+			continue
+		}
+
+		st.Role = ""
+
+		// a node that was part of the cluster was taken out of it behind our back, the topology checker
+		// reports it. it is only added back when asked to.
+		rejoin := prevPhase == ravendbv1.NodeJoinJoined || prevPhase == ravendbv1.NodeJoinLeft
+		if rejoin && !cluster.TopologyAutoRejoin() {
+			st.JoinPhase = ravendbv1.NodeJoinLeft
+			st.JoinMessage = "the node dropped out of the cluster, set spec.topology.autoRejoin to add it back"
+			if prevPhase == ravendbv1.NodeJoinJoined {
+				j.event(cluster, corev1.EventTypeWarning, "NodeDroppedOut", "Node %s is no longer part of the RavenDB cluster", node.Tag)
+			}
+			continue
+		}
+
+		pending = true
+		if requested {
+			st.JoinPhase = ravendbv1.NodeJoinPending
+			st.JoinMessage = "waiting for the previous node to join"
+			continue
+		}
+
+		if err := rc.Alive(ctx, node.Tag); err != nil {
+			st.JoinPhase = ravendbv1.NodeJoinWaitingForNode
+			st.JoinMessage = "waiting for /setup/alive: " + err.Error()
+			continue
+		}
+
+		requested = true
+		if err := rc.AddNode(ctx, node.PublicServerUrl, node.Tag, node.Watcher); err != nil {
+			st.JoinPhase = ravendbv1.NodeJoinFailed
+			st.JoinMessage = err.Error()
+			j.event(cluster, corev1.EventTypeWarning, "NodeJoinFailed", "Failed to add node %s to the cluster: %v", node.Tag, err)
+			continue
+		}
+
+		st.JoinPhase = ravendbv1.NodeJoinJoining
+		st.JoinMessage = fmt.Sprintf("join requested as %s", roleName(node.Watcher))
+		if rejoin {
+			j.event(cluster, corev1.EventTypeNormal, "NodeRejoinRequested", "Adding node %s back to the cluster as %s (spec.topology.autoRejoin)", node.Tag, roleName(node.Watcher))
+			continue
+		}
+		j.event(cluster, corev1.EventTypeNormal, "NodeJoinRequested", "Adding node %s to the cluster as %s", node.Tag, roleName(node.Watcher))
+	}
+
+	return out, pending, nil
+}
+
+// alignStatuses returns one entry per spec node (in spec order). the upgrader may return a partial
+// list on errors and rebuilds the entries it touched, so missing entries and the join fields
+// are carried over from the previous status.
+func alignStatuses(cluster *ravendbv1.RavenDBCluster, statuses []ravendbv1.RavenDBNodeStatus) []ravendbv1.RavenDBNodeStatus {
+	prev := make(map[string]ravendbv1.RavenDBNodeStatus, len(cluster.Status.Nodes))
+	for _, s := range cluster.Status.Nodes {
+		prev[strings.ToUpper(s.Tag)] = s
+	}
+	cur := make(map[string]ravendbv1.RavenDBNodeStatus, len(statuses))
+	for _, s := range statuses {
+		cur[strings.ToUpper(s.Tag)] = s
+	}
+
+	out := make([]ravendbv1.RavenDBNodeStatus, 0, len(cluster.Spec.Nodes))
+	for _, n := range cluster.Spec.Nodes {
+		key := strings.ToUpper(n.Tag)
+		p, hadPrev := prev[key]
+
+		s, ok := cur[key]
+		if !ok {
+			s = p
+			if !hadPrev {
+				s = ravendbv1.RavenDBNodeStatus{Tag: n.Tag, Status: ravendbv1.NodeStatusCreated}
+			}
+		}
+		if hadPrev && s.JoinPhase == "" {
+			s.JoinPhase = p.JoinPhase
+			s.Role = p.Role
+			s.JoinMessage = p.JoinMessage
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+func nodeURL(cluster *ravendbv1.RavenDBCluster, tag string) string {
+	for _, n := range cluster.Spec.Nodes {
+		if strings.EqualFold(n.Tag, tag) {
+			return n.PublicServerUrl
+		}
+	}
+	return ""
+}
+
+func roleName(watcher bool) string {
+	if watcher {
+		return string(ravendbv1.NodeRoleWatcher)
+	}
+	return string(ravendbv1.NodeRoleMember)
+}
+
+func (j *joiner) event(cluster *ravendbv1.RavenDBCluster, eventType, reason, format string, args ...any) {
+	if j.rec == nil {
+		return
+	}
+	j.rec.Eventf(cluster, eventType, reason, format, args...)
+}
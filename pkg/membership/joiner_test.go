@@ -0,0 +1,145 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package membership
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/record"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/ravendb/fake"
+)
+
+func joinPhases(statuses []ravendbv1.RavenDBNodeStatus) []ravendbv1.NodeJoinPhase {
+	var out []ravendbv1.NodeJoinPhase
+	for _, s := range statuses {
+		out = append(out, s.JoinPhase)
+	}
+	return out
+}
+
+func Test_J1_AppendedNodesJoinOnePerTick(t *testing.T) {
+	srv := fake.New("A", "B", "C", "D")
+	srv.SetRole("C", "")
+	srv.SetRole("D", "")
+	c := bootstrappedCluster("A", "B")
+	c.Spec.Nodes = append(c.Spec.Nodes,
+		ravendbv1.RavenDBNode{Tag: "C", PublicServerUrl: fake.URL("C")},
+		ravendbv1.RavenDBNode{Tag: "D", PublicServerUrl: fake.URL("D"), Watcher: true},
+	)
+	rec := record.NewFakeRecorder(10)
+	j := &joiner{rec: rec, buildClient: fakeClient(srv)}
+
+	out, pending, err := j.Run(context.Background(), c, nil, c.Status.Nodes)
+	require.NoError(t, err)
+	require.True(t, pending)
+	require.Equal(t, []ravendbv1.NodeJoinPhase{
+		ravendbv1.NodeJoinJoined, ravendbv1.NodeJoinJoined, ravendbv1.NodeJoinJoining, ravendbv1.NodeJoinPending,
+	}, joinPhases(out))
+	require.Equal(t, ravendbv1.NodeRoleMember, srv.Role("C"))
+	require.Equal(t, ravendbv1.NodeClusterRole(""), srv.Role("D"))
+	require.Equal(t, "Normal NodeJoinRequested Adding node C to the cluster as Member", <-rec.Events)
+
+	c.Status.Nodes = out
+	out, pending, err = j.Run(context.Background(), c, nil, c.Status.Nodes)
+	require.NoError(t, err)
+	require.True(t, pending)
+	require.Equal(t, ravendbv1.NodeJoinJoined, out[2].JoinPhase)
+	require.Equal(t, ravendbv1.NodeJoinJoining, out[3].JoinPhase)
+	require.Equal(t, ravendbv1.NodeRoleWatcher, srv.Role("D"))
+	require.Equal(t, "Normal NodeJoined Node C joined the cluster as Member", <-rec.Events)
+	require.Equal(t, "Normal NodeJoinRequested Adding node D to the cluster as Watcher", <-rec.Events)
+
+	c.Status.Nodes = out
+	out, pending, err = j.Run(context.Background(), c, nil, c.Status.Nodes)
+	require.NoError(t, err)
+	require.False(t, pending)
+	require.Equal(t, ravendbv1.NodeJoinJoined, out[3].JoinPhase)
+	require.Equal(t, ravendbv1.NodeRoleWatcher, out[3].Role)
+	require.Equal(t, 2, requestsTo(srv, "PUT /admin/cluster/node"))
+}
+
+func Test_J2_NodeThatIsStillJoiningIsNotAddedAgain(t *testing.T) {
+	srv := fake.New("A", "B", "C")
+	srv.SetRole("C", ravendbv1.NodeRolePromotable)
+	c := bootstrappedCluster("A", "B")
+	c.Spec.Nodes = append(c.Spec.Nodes, ravendbv1.RavenDBNode{Tag: "C", PublicServerUrl: fake.URL("C")})
+	j := &joiner{buildClient: fakeClient(srv)}
+
+	out, pending, err := j.Run(context.Background(), c, nil, c.Status.Nodes)
+	require.NoError(t, err)
+	require.True(t, pending)
+	require.Equal(t, ravendbv1.NodeJoinJoining, out[2].JoinPhase)
+	require.Equal(t, ravendbv1.NodeRolePromotable, out[2].Role)
+	require.Equal(t, "waiting for the node to catch up and get promoted", out[2].JoinMessage)
+	require.Zero(t, requestsTo(srv, "PUT /admin/cluster/node"))
+
+	// a new node that isn't up yet waits for it
+	srv.SetRole("C", "")
+	srv.SetDown("C", true)
+	out, pending, err = j.Run(context.Background(), c, nil, out)
+	require.NoError(t, err)
+	require.True(t, pending)
+	require.Equal(t, ravendbv1.NodeJoinWaitingForNode, out[2].JoinPhase)
+	require.Zero(t, requestsTo(srv, "PUT /admin/cluster/node"))
+}
+
+func Test_J3_NodeThatLeftStaysOutWithoutAutoRejoin(t *testing.T) {
+	srv := fake.New("A", "B", "C")
+	srv.SetRole("C", "")
+	c := bootstrappedCluster("A", "B", "C")
+	rec := record.NewFakeRecorder(10)
+	j := &joiner{rec: rec, buildClient: fakeClient(srv)}
+
+	for i := 0; i < 2; i++ {
+		out, pending, err := j.Run(context.Background(), c, nil, c.Status.Nodes)
+		require.NoError(t, err)
+		require.False(t, pending)
+		require.Equal(t, ravendbv1.NodeJoinLeft, out[2].JoinPhase)
+		require.Contains(t, out[2].JoinMessage, "spec.topology.autoRejoin")
+		c.Status.Nodes = out
+	}
+	require.Zero(t, requestsTo(srv, "PUT /admin/cluster/node"))
+	require.Equal(t, ravendbv1.NodeClusterRole(""), srv.Role("C"))
+	require.Len(t, rec.Events, 1)
+	require.Equal(t, "Warning NodeDroppedOut Node C is no longer part of the RavenDB cluster", <-rec.Events)
+}
+
+func Test_J4_NodeThatLeftRejoinsWithAutoRejoin(t *testing.T) {
+	srv := fake.New("A", "B", "C")
+	srv.SetRole("C", "")
+	c := bootstrappedCluster("A", "B", "C")
+	c.Status.Nodes[2].JoinPhase = ravendbv1.NodeJoinLeft
+	c.Spec.Topology = &ravendbv1.TopologySpec{AutoRejoin: true}
+	rec := record.NewFakeRecorder(10)
+	j := &joiner{rec: rec, buildClient: fakeClient(srv)}
+
+	out, pending, err := j.Run(context.Background(), c, nil, c.Status.Nodes)
+	require.NoError(t, err)
+	require.True(t, pending)
+	require.Equal(t, ravendbv1.NodeJoinJoining, out[2].JoinPhase)
+	require.Equal(t, ravendbv1.NodeRoleMember, srv.Role("C"))
+	require.Equal(t, "Normal NodeRejoinRequested Adding node C back to the cluster as Member (spec.topology.autoRejoin)", <-rec.Events)
+
+	c.Status.Nodes = out
+	out, pending, err = j.Run(context.Background(), c, nil, c.Status.Nodes)
+	require.NoError(t, err)
+	require.False(t, pending)
+	require.Equal(t, ravendbv1.NodeJoinJoined, out[2].JoinPhase)
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package membership
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/naming"
+	"ravendb-operator/pkg/ravendb"
+)
+
+// Remover takes nodes that were removed from spec.nodes (scale in) out of the cluster.
+type Remover interface {
+	// Run returns the node being removed and reports whether a removal is still in progress, so the caller can requeue.
+	Run(ctx context.Context, cluster *ravendbv1.RavenDBCluster, kc client.Client) (*ravendbv1.NodeRemovalStatus, bool, error)
+}
+
+type remover struct {
+	rec         record.EventRecorder
+	buildClient func(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster, opts ...ravendb.Option) (*ravendb.Client, error)
+}
+
+func NewRemover(rec record.EventRecorder) Remover {
+	return &remover{
+		rec:         rec,
+		buildClient: ravendb.NewForCluster,
+	}
+}
+
+// Run performs one removal "tick" for the first orphaned node (a StatefulSet we own whose tag is no longer in spec):
+//  1. while the node is still part of the RavenDB cluster:
+//     a) make sure every database group it hosts has another healthy replica (otherwise we wait).
+//     b) remove it from the cluster through the leader (DELETE /admin/cluster/node).
+//  2. once RavenDB no longer knows the node, delete its StatefulSet and Service,
+//     and its PVCs when spec.storage.pvcRetentionPolicy is Delete.
+//
+// why a removal is blocked is kept in status.nodeRemoval, the NodeRemovalBlocked event is only recorded
+// when that reason changes. on an error the previous status is returned unchanged.
+func (r *remover) Run(ctx context.Context, cluster *ravendbv1.RavenDBCluster, kc client.Client) (*ravendbv1.NodeRemovalStatus, bool, error) {
+	prev := cluster.Status.NodeRemoval
+
+	orphans, err := orphanedStatefulSets(ctx, kc, cluster)
+	if err != nil {
+		return prev, prev != nil, err
+	}
+	if len(orphans) == 0 {
+		return nil, false, nil
+	}
+
+	sts := orphans[0]
+	tag := sts.Labels[common.LabelNodeTag]
+	st := &ravendbv1.NodeRemovalStatus{Tag: tag}
+
+	if cluster.IsBootstrapped() {
+		done, blocked, err := r.leaveCluster(ctx, cluster, kc, tag)
+		if err != nil {
+			return prev, true, err
+		}
+		st.BlockedReason = blocked
+		if blocked != "" && (prev == nil || prev.Tag != tag || prev.BlockedReason != blocked) {
+			r.event(cluster, corev1.EventTypeWarning, "NodeRemovalBlocked", "Node %s can't be removed yet: %s", tag, blocked)
+		}
+		if !done {
+			return st, true, nil
+		}
+	}
+
+	if err := deleteNodeResources(ctx, kc, cluster, &sts); err != nil {
+		return prev, true, err
+	}
+	r.event(cluster, corev1.EventTypeNormal, "NodeRemoved", "Node %s removed (PVCs: %s)", tag, pvcRetention(cluster))
+
+	return nil, len(orphans) > 1, nil
+}
+
+// leaveCluster returns true once the node is no longer part of the RavenDB cluster,
+// or why it can't leave yet
+func (r *remover) leaveCluster(ctx context.Context, cluster *ravendbv1.RavenDBCluster, kc client.Client, tag string) (bool, string, error) {
+	rc, err := r.buildClient(ctx, kc, cluster)
+	if err != nil {
+		return false, "", err
+	}
+
+	topo, err := rc.Topology(ctx)
+	if err != nil {
+		return false, "", err
+	}
+	if topo.RoleOf(tag) == "" {
+		return true, "", nil
+	}
+
+	dbs, err := rc.Databases(ctx)
+	if err != nil {
+		return false, "", err
+	}
+	if ok, info := ravendb.NodeRemovable(dbs, tag); !ok {
+		return false, info, nil
+	}
+
+	if err := rc.RemoveNode(ctx, tag); err != nil {
+		r.event(cluster, corev1.EventTypeWarning, "NodeRemovalFailed", "Failed to remove node %s from the cluster: %v", tag, err)
+		return false, "", err
+	}
+	r.event(cluster, corev1.EventTypeNormal, "NodeLeftCluster", "Node %s removed from the RavenDB cluster", tag)
+
+	// K8s objects are deleted on the next tick, after the topology confirms the removal
+	return false, "", nil
+}
+
+func orphanedStatefulSets(ctx context.Context, kc client.Client, cluster *ravendbv1.RavenDBCluster) ([]appsv1.StatefulSet, error) {
+	var list appsv1.StatefulSetList
+	if err := kc.List(ctx, &list,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{common.LabelInstance: cluster.Name},
+	); err != nil {
+		return nil, err
+	}
+
+	var out []appsv1.StatefulSet
+	for _, sts := range list.Items {
+		if !isOwnedBy(&sts, cluster) {
+			continue
+		}
+		tag := sts.Labels[common.LabelNodeTag]
+		if tag == "" || nodeURL(cluster, tag) != "" {
+			continue
+		}
+		out = append(out, sts)
+	}
+	return out, nil
+}
+
+func deleteNodeResources(ctx context.Context, kc client.Client, cluster *ravendbv1.RavenDBCluster, sts *appsv1.StatefulSet) error {
+	tag := sts.Labels[common.LabelNodeTag]
+
+	// PVC names are derived from the templates, so collect them before the StatefulSet is gone
+	var pvcNames []string
+	if pvcRetention(cluster) == ravendbv1.PVCRetentionDelete {
+		replicas := int32(common.NumOfReplicas)
+		if sts.Spec.Replicas != nil {
+			replicas = *sts.Spec.Replicas
+		}
+		for _, tpl := range sts.Spec.VolumeClaimTemplates {
+			for ordinal := int32(0); ordinal < replicas; ordinal++ {
+				pvcNames = append(pvcNames, fmt.Sprintf("%s-%s-%d", tpl.Name, sts.Name, ordinal))
+			}
+		}
+	}
+
+	if err := kc.Delete(ctx, sts); err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("delete statefulset %s: %w", sts.Name, err)
+	}
+
+	svc := &corev1.Service{}
+	svc.Namespace = cluster.Namespace
+	svc.Name = naming.For(cluster).Node(tag)
+	if err := kc.Delete(ctx, svc); err != nil && !kerrors.IsNotFound(err) {
+		return fmt.Errorf("delete service %s: %w", svc.Name, err)
+	}
+
+	for _, name := range pvcNames {
+		pvc := &corev1.PersistentVolumeClaim{}
+		pvc.Namespace = cluster.Namespace
+		pvc.Name = name
+		if err := kc.Delete(ctx, pvc); err != nil && !kerrors.IsNotFound(err) {
+			return fmt.Errorf("delete pvc %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func pvcRetention(cluster *ravendbv1.RavenDBCluster) ravendbv1.PVCRetentionPolicy {
+	if cluster.Spec.StorageSpec.PVCRetentionPolicy == "" {
+		return ravendbv1.PVCRetentionRetain
+	}
+	return cluster.Spec.StorageSpec.PVCRetentionPolicy
+}
+
+func isOwnedBy(obj client.Object, cluster *ravendbv1.RavenDBCluster) bool {
+	for _, o := range obj.GetOwnerReferences() {
+		if o.UID == cluster.UID {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *remover) event(cluster *ravendbv1.RavenDBCluster, eventType, reason, format string, args ...any) {
+	if r.rec == nil {
+		return
+	}
+	r.rec.Eventf(cluster, eventType, reason, format, args...)
+}
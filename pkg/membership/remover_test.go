@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package membership
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/ravendb"
+	"ravendb-operator/pkg/ravendb/fake"
+)
+
+// a cluster scaled in from A, B, C to A, B with the StatefulSet of C still around
+func scaledInCluster(t *testing.T) (*ravendbv1.RavenDBCluster, client.Client, *appsv1.StatefulSet) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+
+	c := bootstrappedCluster("A", "B")
+	c.Name = "db"
+	c.Namespace = "ravendb"
+	c.UID = "uid-db"
+
+	sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{
+		Name:            "db-c",
+		Namespace:       "ravendb",
+		Labels:          map[string]string{common.LabelInstance: "db", common.LabelNodeTag: "C"},
+		OwnerReferences: []metav1.OwnerReference{{APIVersion: "ravendb.ravendb.io/v1", Kind: "RavenDBCluster", Name: "db", UID: c.UID}},
+	}}
+	kc := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(sts).Build()
+	return c, kc, sts
+}
+
+func onlyOn(tag, status string) ravendb.Database {
+	return ravendb.Database{
+		Name:              "orders",
+		ReplicationFactor: 2,
+		NodesTopology: ravendb.DatabaseTopology{
+			Members: []ravendb.DatabaseNode{{NodeTag: "B"}, {NodeTag: tag}},
+			Status:  map[string]ravendb.DatabaseNodeStatus{"B": {LastStatus: status}, tag: {LastStatus: "Ok"}},
+		},
+	}
+}
+
+func Test_RM1_BlockedRemovalIsReportedOnce(t *testing.T) {
+	c, kc, _ := scaledInCluster(t)
+	srv := fake.New("A", "B", "C")
+	srv.SetDatabases(onlyOn("C", "Error"))
+	rec := record.NewFakeRecorder(10)
+	r := &remover{rec: rec, buildClient: fakeClient(srv)}
+
+	for i := 0; i < 3; i++ {
+		st, pending, err := r.Run(context.Background(), c, kc)
+		require.NoError(t, err)
+		require.True(t, pending)
+		require.Equal(t, "C", st.Tag)
+		require.Contains(t, st.BlockedReason, "db=orders")
+		c.Status.NodeRemoval = st
+	}
+	require.Len(t, rec.Events, 1)
+	require.Contains(t, <-rec.Events, "NodeRemovalBlocked")
+
+	// another reason is reported again
+	srv.SetDatabases(onlyOn("C", "Ok"), ravendb.Database{Name: "invoices", NodesTopology: ravendb.DatabaseTopology{Members: []ravendb.DatabaseNode{{NodeTag: "C"}}}})
+	st, _, err := r.Run(context.Background(), c, kc)
+	require.NoError(t, err)
+	require.Contains(t, st.BlockedReason, "db=invoices")
+	require.Len(t, rec.Events, 1)
+}
+
+func Test_RM2_NodeLeavesThenItsResourcesAreDeleted(t *testing.T) {
+	c, kc, sts := scaledInCluster(t)
+	srv := fake.New("A", "B", "C")
+	srv.SetDatabases(onlyOn("C", "Ok"))
+	r := &remover{buildClient: fakeClient(srv)}
+
+	st, pending, err := r.Run(context.Background(), c, kc)
+	require.NoError(t, err)
+	require.True(t, pending)
+	require.Empty(t, st.BlockedReason)
+	require.Equal(t, ravendbv1.NodeClusterRole(""), srv.Role("C"))
+	require.NoError(t, kc.Get(context.Background(), client.ObjectKeyFromObject(sts), &appsv1.StatefulSet{}))
+
+	st, pending, err = r.Run(context.Background(), c, kc)
+	require.NoError(t, err)
+	require.False(t, pending)
+	require.Nil(t, st)
+	err = kc.Get(context.Background(), client.ObjectKeyFromObject(sts), &appsv1.StatefulSet{})
+	require.True(t, kerrors.IsNotFound(err))
+}
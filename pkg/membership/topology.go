@@ -0,0 +1,190 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package membership
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/ravendb"
+)
+
+// TopologyChecker compares the Raft topology of a bootstrapped cluster with spec.nodes, so nodes that
+// were removed or demoted by hand, or are stuck as a promotable, show up on the ClusterTopologyInSync
+// condition. the checker only reports, re-joining is up to the joiner (spec.topology.autoRejoin).
+type TopologyChecker interface {
+	// Run returns the new status.topology and when the topology has to be checked again
+	// (0 while the cluster isn't bootstrapped).
+	Run(ctx context.Context, cluster *ravendbv1.RavenDBCluster, kc client.Client) (*ravendbv1.TopologyStatus, time.Duration, error)
+}
+
+type topologyChecker struct {
+	rec         record.EventRecorder
+	buildClient func(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster, opts ...ravendb.Option) (*ravendb.Client, error)
+}
+
+func NewTopologyChecker(rec record.EventRecorder) TopologyChecker {
+	return &topologyChecker{
+		rec:         rec,
+		buildClient: ravendb.NewForCluster,
+	}
+}
+
+// Run reads /cluster/topology once per spec.topology.checkInterval (and right away after a spec change),
+// otherwise the previous status is kept.
+func (t *topologyChecker) Run(ctx context.Context, cluster *ravendbv1.RavenDBCluster, kc client.Client) (*ravendbv1.TopologyStatus, time.Duration, error) {
+	prev := cluster.Status.Topology
+	if !cluster.IsBootstrapped() || len(cluster.Spec.Nodes) == 0 {
+		return prev, 0, nil
+	}
+
+	interval := cluster.EffectiveTopologyCheckInterval()
+	now := metav1.Now()
+	if prev != nil && prev.LastCheckTime != nil && cluster.Status.ObservedGeneration == cluster.Generation {
+		if wait := prev.LastCheckTime.Add(interval).Sub(now.Time); wait > 0 {
+			return prev, wait, nil
+		}
+	}
+
+	st := &ravendbv1.TopologyStatus{LastCheckTime: &now}
+
+	rc, err := t.buildClient(ctx, kc, cluster)
+	if err != nil {
+		st.Error = err.Error()
+		return st, interval, err
+	}
+	topo, err := rc.Topology(ctx)
+	if err != nil {
+		st.Error = err.Error()
+		if prev != nil {
+			st.Leader, st.Term, st.Drift = prev.Leader, prev.Term, prev.Drift
+		}
+		return st, interval, err
+	}
+
+	st.Leader = topo.Leader
+	st.Term = topo.CurrentTerm
+	st.Drift = topologyDrift(cluster, topo, prev, now)
+	t.emitChanges(cluster, prev, st)
+	return st, interval, nil
+}
+
+// topologyDrift lists the spec nodes (in spec order) and then the unexpected nodes (by tag) the topology
+// disagrees about. an entry that was already seen keeps its Since.
+func topologyDrift(cluster *ravendbv1.RavenDBCluster, topo *ravendb.Topology, prev *ravendbv1.TopologyStatus, now metav1.Time) []ravendbv1.TopologyDrift {
+	since := map[string]metav1.Time{}
+	if prev != nil {
+		for _, d := range prev.Drift {
+			since[driftKey(d)] = d.Since
+		}
+	}
+
+	var out []ravendbv1.TopologyDrift
+	add := func(d ravendbv1.TopologyDrift) {
+		d.Since = now
+		if s, ok := since[driftKey(d)]; ok {
+			d.Since = s
+		}
+		out = append(out, d)
+	}
+
+	inSpec := map[string]struct{}{}
+	for _, n := range cluster.Spec.Nodes {
+		tag := strings.ToUpper(n.Tag)
+		inSpec[tag] = struct{}{}
+
+		expected := ravendbv1.NodeRoleMember
+		if n.Watcher {
+			expected = ravendbv1.NodeRoleWatcher
+		}
+
+		switch actual := topo.RoleOf(tag); {
+		case actual == "":
+			add(ravendbv1.TopologyDrift{Tag: tag, Kind: ravendbv1.TopologyDriftMissing, ExpectedRole: expected})
+		case actual == ravendbv1.NodeRolePromotable && expected == ravendbv1.NodeRoleMember:
+			add(ravendbv1.TopologyDrift{Tag: tag, Kind: ravendbv1.TopologyDriftPromotable, ExpectedRole: expected, ActualRole: actual})
+		case actual != expected:
+			add(ravendbv1.TopologyDrift{Tag: tag, Kind: ravendbv1.TopologyDriftRoleMismatch, ExpectedRole: expected, ActualRole: actual})
+		}
+	}
+
+	var unexpected []string
+	for _, tag := range topo.Tags() {
+		if _, ok := inSpec[strings.ToUpper(tag)]; !ok {
+			unexpected = append(unexpected, tag)
+		}
+	}
+	sort.Strings(unexpected)
+	for _, tag := range unexpected {
+		add(ravendbv1.TopologyDrift{Tag: tag, Kind: ravendbv1.TopologyDriftUnexpected, ActualRole: topo.RoleOf(tag)})
+	}
+
+	return out
+}
+
+func driftKey(d ravendbv1.TopologyDrift) string {
+	return strings.ToUpper(d.Tag) + "/" + string(d.Kind)
+}
+
+func (t *topologyChecker) emitChanges(cluster *ravendbv1.RavenDBCluster, prev, cur *ravendbv1.TopologyStatus) {
+	seen := map[string]struct{}{}
+	if prev != nil {
+		for _, d := range prev.Drift {
+			seen[driftKey(d)] = struct{}{}
+		}
+	}
+
+	for _, d := range cur.Drift {
+		if _, ok := seen[driftKey(d)]; ok {
+			continue
+		}
+		// a node that joins is missing and then promotable for a while, the joiner reports on those
+		if d.Kind == ravendbv1.TopologyDriftPromotable || (d.Kind == ravendbv1.TopologyDriftMissing && joining(cluster, d.Tag)) {
+			continue
+		}
+		t.event(cluster, corev1.EventTypeWarning, "TopologyDrift", "Node %s %s", d.Tag, d.Describe())
+	}
+
+	if len(cur.Drift) == 0 && len(seen) > 0 {
+		t.event(cluster, corev1.EventTypeNormal, "TopologyInSync", "The RavenDB cluster topology matches spec.nodes again")
+	}
+}
+
+// joining reports whether the joiner didn't see the node in the cluster yet
+func joining(cluster *ravendbv1.RavenDBCluster, tag string) bool {
+	for _, s := range cluster.Status.Nodes {
+		if strings.EqualFold(s.Tag, tag) {
+			return s.JoinPhase != ravendbv1.NodeJoinJoined && s.JoinPhase != ravendbv1.NodeJoinLeft
+		}
+	}
+	return true
+}
+
+func (t *topologyChecker) event(cluster *ravendbv1.RavenDBCluster, eventType, reason, format string, args ...any) {
+	if t.rec == nil {
+		return
+	}
+	t.rec.Eventf(cluster, eventType, reason, format, args...)
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package membership
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/ravendb"
+	"ravendb-operator/pkg/ravendb/fake"
+)
+
+func bootstrappedCluster(tags ...string) *ravendbv1.RavenDBCluster {
+	c := &ravendbv1.RavenDBCluster{}
+	for _, tag := range tags {
+		c.Spec.Nodes = append(c.Spec.Nodes, ravendbv1.RavenDBNode{Tag: tag, PublicServerUrl: fake.URL(tag)})
+		c.Status.Nodes = append(c.Status.Nodes, ravendbv1.RavenDBNodeStatus{Tag: tag, JoinPhase: ravendbv1.NodeJoinJoined})
+	}
+	c.Status.Bootstrap = &ravendbv1.BootstrapStatus{Phase: ravendbv1.BootstrapPhaseCompleted}
+	return c
+}
+
+func fakeClient(srv *fake.Server) func(context.Context, client.Client, *ravendbv1.RavenDBCluster, ...ravendb.Option) (*ravendb.Client, error) {
+	return func(context.Context, client.Client, *ravendbv1.RavenDBCluster, ...ravendb.Option) (*ravendb.Client, error) {
+		return srv.Client(), nil
+	}
+}
+
+func Test_T1_TopologyInSync(t *testing.T) {
+	srv := fake.New("A", "B", "C")
+	c := bootstrappedCluster("A", "B", "C")
+	checker := &topologyChecker{buildClient: fakeClient(srv)}
+
+	st, next, err := checker.Run(context.Background(), c, nil)
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, next)
+	require.Equal(t, "A", st.Leader)
+	require.Empty(t, st.Drift)
+}
+
+func Test_T2_TopologyDrift(t *testing.T) {
+	srv := fake.New("A", "B", "C", "X")
+	srv.SetRole("B", "")
+	srv.SetRole("C", ravendbv1.NodeRolePromotable)
+	c := bootstrappedCluster("A", "B", "C")
+	checker := &topologyChecker{buildClient: fakeClient(srv)}
+
+	st, _, err := checker.Run(context.Background(), c, nil)
+	require.NoError(t, err)
+	require.Equal(t, []ravendbv1.TopologyDriftKind{
+		ravendbv1.TopologyDriftMissing, ravendbv1.TopologyDriftPromotable, ravendbv1.TopologyDriftUnexpected,
+	}, driftKinds(st.Drift))
+	require.Equal(t, "X", st.Drift[2].Tag)
+
+	// a spec watcher that was promoted by hand
+	srv.SetRole("B", ravendbv1.NodeRoleMember)
+	srv.SetRole("C", ravendbv1.NodeRoleMember)
+	srv.SetRole("X", "")
+	c.Spec.Nodes[1].Watcher = true
+	c.Generation++
+
+	c.Status.Topology = st
+	st, _, err = checker.Run(context.Background(), c, nil)
+	require.NoError(t, err)
+	require.Len(t, st.Drift, 1)
+	require.Equal(t, ravendbv1.TopologyDriftRoleMismatch, st.Drift[0].Kind)
+	require.Equal(t, "is a Member, spec wants a Watcher", st.Drift[0].Describe())
+}
+
+func Test_T3_TopologyIsCheckedOncePerInterval(t *testing.T) {
+	srv := fake.New("A")
+	c := bootstrappedCluster("A")
+	checker := &topologyChecker{buildClient: fakeClient(srv)}
+
+	st, _, err := checker.Run(context.Background(), c, nil)
+	require.NoError(t, err)
+	c.Status.Topology = st
+
+	srv.SetRole("A", "")
+	again, next, err := checker.Run(context.Background(), c, nil)
+	require.NoError(t, err)
+	require.Same(t, st, again)
+	require.Greater(t, next, time.Duration(0))
+	require.LessOrEqual(t, next, time.Minute)
+
+	// a check that is due reads the topology again
+	past := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	st.LastCheckTime = &past
+	again, _, err = checker.Run(context.Background(), c, nil)
+	require.Error(t, err)
+	require.NotEmpty(t, again.Error)
+}
+
+func Test_T4_NodeThatDroppedOutIsOnlyRejoinedWhenAsked(t *testing.T) {
+	srv := fake.New("A", "B")
+	srv.SetRole("B", "")
+	c := bootstrappedCluster("A", "B")
+	j := &joiner{buildClient: fakeClient(srv)}
+
+	out, pending, err := j.Run(context.Background(), c, nil, c.Status.Nodes)
+	require.NoError(t, err)
+	require.False(t, pending)
+	require.Equal(t, ravendbv1.NodeJoinLeft, out[1].JoinPhase)
+	require.Equal(t, ravendbv1.NodeClusterRole(""), srv.Role("B"))
+
+	c.Status.Nodes = out
+	c.Spec.Topology = &ravendbv1.TopologySpec{AutoRejoin: true}
+	out, pending, err = j.Run(context.Background(), c, nil, c.Status.Nodes)
+	require.NoError(t, err)
+	require.True(t, pending)
+	require.Equal(t, ravendbv1.NodeJoinJoining, out[1].JoinPhase)
+	require.Equal(t, ravendbv1.NodeRoleMember, srv.Role("B"))
+}
+
+func driftKinds(drift []ravendbv1.TopologyDrift) []ravendbv1.TopologyDriftKind {
+	out := make([]ravendbv1.TopologyDriftKind, 0, len(drift))
+	for _, d := range drift {
+		out = append(out, d.Kind)
+	}
+	return out
+}
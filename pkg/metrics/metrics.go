@@ -0,0 +1,181 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package metrics
+
+import (
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/health"
+)
+
+// the operator's own metrics, served by the manager's metrics endpoint next to the controller-runtime ones.
+// every per cluster series carries namespace and cluster labels and is dropped by Forget once the cluster is gone.
+
+const namespace = "ravendb_operator"
+
+// outcome label of the gate metrics
+const (
+	GatePassed   = "pass"
+	GateBlocked  = "block"
+	GateTimedOut = "timeout"
+	GateFailed   = "error"
+)
+
+var (
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "reconcile_duration_seconds",
+		Help:      "Duration of a RavenDBCluster reconcile.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"namespace", "cluster"})
+
+	reconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reconcile_errors_total",
+		Help:      "Errors during RavenDBCluster reconciles by the step that failed.",
+	}, []string{"namespace", "cluster", "step"})
+
+	gateChecks = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upgrade_gate_checks_total",
+		Help:      "Upgrade gate checks by outcome (pass, block, timeout, error).",
+	}, []string{"namespace", "cluster", "phase", "kind", "outcome"})
+
+	gateDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "upgrade_gate_duration_seconds",
+		Help:      "Time from the first check of an upgrade gate until it passed, timed out or failed.",
+		Buckets:   []float64{1, 5, 10, 30, 60, 120, 300, 600, 900, 1800},
+	}, []string{"phase", "kind", "outcome"})
+
+	conditionStatus = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cluster_condition",
+		Help:      "Status of the RavenDBCluster conditions, 1 for the current status of each condition and 0 for the others.",
+	}, []string{"namespace", "cluster", "type", "status"})
+
+	nodeImage = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "node_image_info",
+		Help:      "Image of the RavenDB container of each node pod and the server version the node reports (empty when unknown).",
+	}, []string{"namespace", "cluster", "tag", "image", "version"})
+
+	bootstrapPhase = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cluster_bootstrap_phase",
+		Help:      "Bootstrap phase of the RavenDBCluster, 1 for the current phase and 0 for the others.",
+	}, []string{"namespace", "cluster", "phase"})
+)
+
+var bootstrapPhases = []ravendbv1.BootstrapPhase{
+	ravendbv1.BootstrapPhaseRunning,
+	ravendbv1.BootstrapPhaseFailed,
+	ravendbv1.BootstrapPhaseCompleted,
+}
+
+var conditionStatuses = []metav1.ConditionStatus{metav1.ConditionTrue, metav1.ConditionFalse, metav1.ConditionUnknown}
+
+func init() {
+	crmetrics.Registry.MustRegister(
+		reconcileDuration,
+		reconcileErrors,
+		gateChecks,
+		gateDuration,
+		conditionStatus,
+		nodeImage,
+		bootstrapPhase,
+	)
+}
+
+func ObserveReconcile(ns, cluster string, d time.Duration) {
+	reconcileDuration.WithLabelValues(ns, cluster).Observe(d.Seconds())
+}
+
+func ReconcileError(ns, cluster, step string) {
+	reconcileErrors.WithLabelValues(ns, cluster, step).Inc()
+}
+
+// GateChecked counts one check of an upgrade gate. elapsed is the time since the gate's first check,
+// it's observed once the gate is over (every outcome but GateBlocked).
+func GateChecked(ns, cluster, phase, kind, outcome string, elapsed time.Duration) {
+	gateChecks.WithLabelValues(ns, cluster, phase, kind, outcome).Inc()
+	if outcome != GateBlocked {
+		gateDuration.WithLabelValues(phase, kind, outcome).Observe(elapsed.Seconds())
+	}
+}
+
+// ObserveCluster sets the gauges from the reconciled status and the collected facts.
+// facts may be nil when collecting failed, the node images are then left as they were.
+func ObserveCluster(cluster *ravendbv1.RavenDBCluster, facts *health.ResourceFacts) {
+	ns, name := cluster.Namespace, cluster.Name
+
+	for _, cond := range cluster.Status.Conditions {
+		for _, s := range conditionStatuses {
+			v := 0.0
+			if cond.Status == s {
+				v = 1
+			}
+			conditionStatus.WithLabelValues(ns, name, cond.Type, strings.ToLower(string(s))).Set(v)
+		}
+	}
+
+	var phase ravendbv1.BootstrapPhase
+	if cluster.Status.Bootstrap != nil {
+		phase = cluster.Status.Bootstrap.Phase
+	}
+	for _, p := range bootstrapPhases {
+		v := 0.0
+		if p == phase {
+			v = 1
+		}
+		bootstrapPhase.WithLabelValues(ns, name, string(p)).Set(v)
+	}
+
+	if facts == nil {
+		return
+	}
+	versions := map[string]string{}
+	if facts.RavenDB != nil {
+		for _, n := range facts.RavenDB.Nodes {
+			versions[n.Tag] = n.Version
+		}
+	}
+	// an upgraded node changes its image label, the old series must not stay at 1
+	nodeImage.DeletePartialMatch(prometheus.Labels{"namespace": ns, "cluster": name})
+	for _, p := range facts.Pods {
+		if p.Tag == "" || p.Image == "" {
+			continue
+		}
+		nodeImage.WithLabelValues(ns, name, p.Tag, p.Image, versions[p.Tag]).Set(1)
+	}
+}
+
+// Forget drops every series of a deleted cluster
+func Forget(ns, cluster string) {
+	labels := prometheus.Labels{"namespace": ns, "cluster": cluster}
+	reconcileDuration.DeletePartialMatch(labels)
+	reconcileErrors.DeletePartialMatch(labels)
+	gateChecks.DeletePartialMatch(labels)
+	conditionStatus.DeletePartialMatch(labels)
+	nodeImage.DeletePartialMatch(labels)
+	bootstrapPhase.DeletePartialMatch(labels)
+}
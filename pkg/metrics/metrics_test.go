@@ -0,0 +1,111 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/health"
+)
+
+func metricsCluster(name string) *ravendbv1.RavenDBCluster {
+	c := &ravendbv1.RavenDBCluster{}
+	c.Name = name
+	c.Namespace = "ravendb"
+	return c
+}
+
+func Test_M1_ConditionsAndBootstrapPhase(t *testing.T) {
+	c := metricsCluster("m1")
+	c.Status.Conditions = []metav1.Condition{
+		{Type: string(ravendbv1.ConditionReady), Status: metav1.ConditionFalse},
+		{Type: string(ravendbv1.ConditionUpgrading), Status: metav1.ConditionTrue},
+	}
+	c.Status.Bootstrap = &ravendbv1.BootstrapStatus{Phase: ravendbv1.BootstrapPhaseRunning}
+	ObserveCluster(c, nil)
+
+	require.Equal(t, 1.0, testutil.ToFloat64(conditionStatus.WithLabelValues("ravendb", "m1", "Ready", "false")))
+	require.Equal(t, 0.0, testutil.ToFloat64(conditionStatus.WithLabelValues("ravendb", "m1", "Ready", "true")))
+	require.Equal(t, 1.0, testutil.ToFloat64(conditionStatus.WithLabelValues("ravendb", "m1", "Upgrading", "true")))
+	require.Equal(t, 1.0, testutil.ToFloat64(bootstrapPhase.WithLabelValues("ravendb", "m1", "Running")))
+
+	c.Status.Conditions[0].Status = metav1.ConditionTrue
+	c.Status.Bootstrap.Phase = ravendbv1.BootstrapPhaseCompleted
+	ObserveCluster(c, nil)
+
+	require.Equal(t, 0.0, testutil.ToFloat64(conditionStatus.WithLabelValues("ravendb", "m1", "Ready", "false")))
+	require.Equal(t, 1.0, testutil.ToFloat64(conditionStatus.WithLabelValues("ravendb", "m1", "Ready", "true")))
+	require.Equal(t, 0.0, testutil.ToFloat64(bootstrapPhase.WithLabelValues("ravendb", "m1", "Running")))
+	require.Equal(t, 1.0, testutil.ToFloat64(bootstrapPhase.WithLabelValues("ravendb", "m1", "Completed")))
+}
+
+func Test_M2_NodeImageFollowsTheUpgrade(t *testing.T) {
+	c := metricsCluster("m2")
+	facts := &health.ResourceFacts{
+		Pods:    []health.PodFact{{Tag: "A", Image: "ravendb/ravendb:6.0"}, {Tag: "B", Image: "ravendb/ravendb:6.0"}},
+		RavenDB: &health.RavenDBFacts{Nodes: []health.RavenDBNodeFact{{Tag: "A", Version: "6.0.105"}}},
+	}
+	ObserveCluster(c, facts)
+	require.Equal(t, 1.0, testutil.ToFloat64(nodeImage.WithLabelValues("ravendb", "m2", "A", "ravendb/ravendb:6.0", "6.0.105")))
+	require.Equal(t, 1.0, testutil.ToFloat64(nodeImage.WithLabelValues("ravendb", "m2", "B", "ravendb/ravendb:6.0", "")))
+
+	facts.Pods[0].Image = "ravendb/ravendb:6.2"
+	facts.RavenDB = nil
+	ObserveCluster(c, facts)
+
+	// the series of the old image is gone, not left at 1
+	require.Equal(t, 2, testutil.CollectAndCount(nodeImage))
+	require.Equal(t, 1.0, testutil.ToFloat64(nodeImage.WithLabelValues("ravendb", "m2", "A", "ravendb/ravendb:6.2", "")))
+	nodeImage.Reset()
+}
+
+func Test_M3_GateChecksAndDurations(t *testing.T) {
+	GateChecked("ravendb", "m3", "pre-step", "pod_ready", GateBlocked, 2*time.Second)
+	GateChecked("ravendb", "m3", "pre-step", "pod_ready", GateBlocked, 4*time.Second)
+	GateChecked("ravendb", "m3", "pre-step", "pod_ready", GatePassed, 8*time.Second)
+	GateChecked("ravendb", "m3", "post-step", "node_alive", GateTimedOut, 15*time.Minute)
+
+	require.Equal(t, 2.0, testutil.ToFloat64(gateChecks.WithLabelValues("ravendb", "m3", "pre-step", "pod_ready", GateBlocked)))
+	require.Equal(t, 1.0, testutil.ToFloat64(gateChecks.WithLabelValues("ravendb", "m3", "post-step", "node_alive", GateTimedOut)))
+
+	// blocked checks don't end the gate, only the pass and the timeout are observed
+	require.Equal(t, 2, testutil.CollectAndCount(gateDuration))
+}
+
+func Test_M4_ForgetDropsTheClusterSeries(t *testing.T) {
+	bootstrapPhase.Reset()
+	reconcileErrors.Reset()
+	reconcileDuration.Reset()
+
+	c := metricsCluster("m4")
+	c.Status.Conditions = []metav1.Condition{{Type: string(ravendbv1.ConditionReady), Status: metav1.ConditionTrue}}
+	ObserveCluster(c, nil)
+	ObserveReconcile("ravendb", "m4", time.Second)
+	ReconcileError("ravendb", "m4", "upgrade")
+	ObserveCluster(metricsCluster("other"), nil)
+
+	Forget("ravendb", "m4")
+
+	require.Zero(t, testutil.CollectAndCount(reconcileErrors))
+	require.Zero(t, testutil.CollectAndCount(reconcileDuration))
+	require.Equal(t, 3, testutil.CollectAndCount(bootstrapPhase))
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package naming derives the names of the objects the operator creates for a RavenDBCluster.
+// it has no dependency on the API types, so the webhook validators can use it as well.
+package naming
+
+import "strings"
+
+// LegacyNamesAnnotation marks a cluster created before child names were derived from the cluster name.
+// such a cluster keeps its fixed names ("ravendb-<tag>", "ravendb", ...), because renaming
+// a StatefulSet means new (empty) PVCs. the controller sets it, it isn't meant to be set by hand.
+const LegacyNamesAnnotation = "ravendb.ravendb.io/legacy-names"
+
+// MaxStatefulSetNameLength keeps "<sts>-<ordinal>" plus the controller-revision-hash suffix within a label value
+const MaxStatefulSetNameLength = 52
+
+const (
+	legacyPrefix            = "ravendb-"
+	legacyGoverningService  = "ravendb"
+	legacyIngress           = "ravendb"
+	legacyCertHookConfigMap = "ravendb-cert-hook"
+	legacyDisruptionBudget  = "ravendb"
+	legacyNetworkPolicy     = "ravendb"
+	legacyNodeAccount       = "ravendb-ops-sa"
+	legacyNodeRole          = "ravendb-ops"
+	legacyNodeRoleBinding   = "ravendb-ops-role-binding"
+)
+
+// Cluster is the part of a RavenDBCluster (or its webhook adapter) names are derived from
+type Cluster interface {
+	GetName() string
+	GetAnnotations() map[string]string
+}
+
+type Names struct {
+	cluster string
+	legacy  bool
+}
+
+func For(c Cluster) Names {
+	return Names{
+		cluster: c.GetName(),
+		legacy:  c.GetAnnotations()[LegacyNamesAnnotation] == "true",
+	}
+}
+
+func (n Names) Legacy() bool {
+	return n.legacy
+}
+
+// Node is the name of the StatefulSet and the Service of one node: "<cluster>-<tag>"
+func (n Names) Node(tag string) string {
+	if n.legacy {
+		return legacyPrefix + strings.ToLower(tag)
+	}
+	return n.cluster + "-" + strings.ToLower(tag)
+}
+
+// Pod is the name of the (single) pod of a node's StatefulSet
+func (n Names) Pod(tag string) string {
+	return n.Node(tag) + "-0"
+}
+
+// GoverningService is the StatefulSets' spec.serviceName. it can't change once a StatefulSet exists.
+func (n Names) GoverningService() string {
+	if n.legacy {
+		return legacyGoverningService
+	}
+	return n.cluster + "-headless"
+}
+
+func (n Names) Ingress() string {
+	if n.legacy {
+		return legacyIngress
+	}
+	return n.cluster
+}
+
+func (n Names) CertHookConfigMap() string {
+	if n.legacy {
+		return legacyCertHookConfigMap
+	}
+	return n.cluster + "-cert-hook"
+}
+
+// PodDisruptionBudget covers the pods of all nodes
+func (n Names) PodDisruptionBudget() string {
+	if n.legacy {
+		return legacyDisruptionBudget
+	}
+	return n.cluster
+}
+
+func (n Names) NetworkPolicy() string {
+	if n.legacy {
+		return legacyNetworkPolicy
+	}
+	return n.cluster
+}
+
+// NodeServiceAccount is the ServiceAccount of the RavenDB pods, legacy clusters keep the one that was created by hand
+func (n Names) NodeServiceAccount() string {
+	if n.legacy {
+		return legacyNodeAccount
+	}
+	return n.cluster + "-node"
+}
+
+// NodeRole grants the RavenDB pods what their cert hooks need
+func (n Names) NodeRole() string {
+	if n.legacy {
+		return legacyNodeRole
+	}
+	return n.cluster + "-node"
+}
+
+func (n Names) NodeRoleBinding() string {
+	if n.legacy {
+		return legacyNodeRoleBinding
+	}
+	return n.cluster + "-node"
+}
+
+// IsLegacyNode reports whether name is the pre-naming StatefulSet name of tag
+func IsLegacyNode(name, tag string) bool {
+	return name == legacyPrefix+strings.ToLower(tag)
+}
@@ -0,0 +1,396 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package notifications turns the alerts of the RavenDB notification center (low disk space, license
+// issues, out of memory...) into Kubernetes Events, so they can be seen without opening the studio.
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/naming"
+	"ravendb-operator/pkg/ravendb"
+)
+
+// how long a node whose subscription dropped (or couldn't be opened) is left alone
+const resubscribeAfter = 30 * time.Second
+
+// Events are cut to this length, RavenDB alerts can carry whole exceptions
+const maxEventMessage = 1024
+
+// the reported alerts kept per cluster to not report them again on a new subscription. past the limit the
+// oldest are dropped, such an alert would be reported once more at worst.
+const (
+	maxSeenAlerts = 1000
+	seenAlertTTL  = 24 * time.Hour
+)
+
+const reasonAlert = "RavenDBAlert"
+
+// Watcher keeps one notification center subscription per node of every bootstrapped cluster with
+// spec.notifications.enabled. it runs with the manager, the reconciler tells it which clusters to watch.
+type Watcher interface {
+	manager.Runnable
+	// Sync starts the subscriptions of the cluster's nodes and stops the ones of nodes that left spec.nodes.
+	// a node is resubscribed when how we connect to it changes (URL, TLS mode or certificates), a cluster
+	// that disables notifications is forgotten.
+	Sync(cluster *ravendbv1.RavenDBCluster)
+	// Forget stops the subscriptions of a deleted cluster
+	Forget(key types.NamespacedName)
+}
+
+type subscribeFunc func(ctx context.Context, cluster *ravendbv1.RavenDBCluster, tag string, fn func(ravendb.Notification)) error
+
+type watcher struct {
+	kc        client.Client
+	rec       record.EventRecorder
+	subscribe subscribeFunc
+	retry     time.Duration
+
+	mu sync.Mutex
+	// nil until the manager started the watcher, subscriptions synced before wait for it
+	base     context.Context
+	clusters map[types.NamespacedName]*clusterWatch
+}
+
+type clusterWatch struct {
+	// last synced copy, subscriptions build their client from it
+	cluster *ravendbv1.RavenDBCluster
+	// tag -> stops the node's subscription, nil while it waits for Start
+	nodes map[string]context.CancelFunc
+	// tag -> the connection settings the node was subscribed with, see endpointOf
+	endpoints map[string]string
+
+	limiter   *rate.Limiter
+	perMinute int
+	// rate limited alerts since the last Event
+	dropped int
+	// "<tag>/<notification id>" -> the alert that was reported, a node sends its active alerts again on
+	// every new subscription
+	seen map[string]seenAlert
+}
+
+type seenAlert struct {
+	createdAt  string
+	reportedAt time.Time
+}
+
+func NewWatcher(kc client.Client, rec record.EventRecorder) Watcher {
+	w := &watcher{
+		kc:       kc,
+		rec:      rec,
+		retry:    resubscribeAfter,
+		clusters: map[types.NamespacedName]*clusterWatch{},
+	}
+	w.subscribe = w.subscribeDefault
+	return w
+}
+
+func (w *watcher) subscribeDefault(ctx context.Context, cluster *ravendbv1.RavenDBCluster, tag string, fn func(ravendb.Notification)) error {
+	rc, err := ravendb.NewForCluster(ctx, w.kc, cluster)
+	if err != nil {
+		return err
+	}
+	return rc.WatchNotifications(ctx, tag, fn)
+}
+
+// Start runs the subscriptions until the manager stops. it needs the leader election like the
+// controllers, only one operator replica reports the alerts.
+func (w *watcher) Start(ctx context.Context) error {
+	w.mu.Lock()
+	w.base = ctx
+	for key, cw := range w.clusters {
+		for tag, stop := range cw.nodes {
+			if stop == nil {
+				w.startLocked(key, cw, tag)
+			}
+		}
+	}
+	w.mu.Unlock()
+
+	<-ctx.Done()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for key := range w.clusters {
+		w.forgetLocked(key)
+	}
+	return nil
+}
+
+func (w *watcher) Sync(cluster *ravendbv1.RavenDBCluster) {
+	key := client.ObjectKeyFromObject(cluster)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !cluster.NotificationsEnabled() || !cluster.IsBootstrapped() {
+		w.forgetLocked(key)
+		return
+	}
+
+	cw := w.clusters[key]
+	if cw == nil {
+		cw = &clusterWatch{nodes: map[string]context.CancelFunc{}, endpoints: map[string]string{}, seen: map[string]seenAlert{}}
+		w.clusters[key] = cw
+	}
+	// minSeverity and eventsPerMinute are read from this copy for every alert, they need no resubscription
+	cw.cluster = cluster.DeepCopy()
+
+	if perMinute := cluster.EffectiveNotificationEventsPerMinute(); perMinute != cw.perMinute {
+		cw.perMinute = perMinute
+		cw.limiter = rate.NewLimiter(rate.Every(time.Minute/time.Duration(perMinute)), perMinute)
+	}
+
+	want := map[string]string{}
+	for _, n := range cluster.Spec.Nodes {
+		want[strings.ToUpper(n.Tag)] = endpointOf(cluster, n)
+	}
+	for tag, stop := range cw.nodes {
+		endpoint, ok := want[tag]
+		if ok && endpoint == cw.endpoints[tag] {
+			continue
+		}
+		if stop != nil {
+			stop()
+		}
+		delete(cw.nodes, tag)
+		delete(cw.endpoints, tag)
+		if !ok {
+			forgetSeenLocked(cw, tag)
+		}
+	}
+	for tag, endpoint := range want {
+		if _, ok := cw.nodes[tag]; !ok {
+			cw.endpoints[tag] = endpoint
+			w.startLocked(key, cw, tag)
+		}
+	}
+}
+
+// endpointOf describes how the subscription of node n connects, a change needs a new subscription
+func endpointOf(c *ravendbv1.RavenDBCluster, n ravendbv1.RavenDBNode) string {
+	ref := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	return strings.Join([]string{n.PublicServerUrl, string(c.Spec.Mode), c.Spec.ClientCertSecretRef, ref(c.Spec.CACertSecretRef)}, "|")
+}
+
+// forgetSeenLocked drops the reported alerts of a node that left the cluster
+func forgetSeenLocked(cw *clusterWatch, tag string) {
+	prefix := tag + "/"
+	for id := range cw.seen {
+		if strings.HasPrefix(id, prefix) {
+			delete(cw.seen, id)
+		}
+	}
+}
+
+// pruneSeenLocked bounds the reported alerts: the expired ones go first, then the oldest until a quarter
+// of the room is free again, so pruning doesn't run for every alert
+func pruneSeenLocked(cw *clusterWatch, now time.Time) {
+	if len(cw.seen) <= maxSeenAlerts {
+		return
+	}
+	for id, a := range cw.seen {
+		if now.Sub(a.reportedAt) > seenAlertTTL {
+			delete(cw.seen, id)
+		}
+	}
+	if excess := len(cw.seen) - maxSeenAlerts*3/4; excess > 0 {
+		ids := make([]string, 0, len(cw.seen))
+		for id := range cw.seen {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return cw.seen[ids[i]].reportedAt.Before(cw.seen[ids[j]].reportedAt) })
+		for _, id := range ids[:excess] {
+			delete(cw.seen, id)
+		}
+	}
+}
+
+func (w *watcher) Forget(key types.NamespacedName) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.forgetLocked(key)
+}
+
+func (w *watcher) forgetLocked(key types.NamespacedName) {
+	cw := w.clusters[key]
+	if cw == nil {
+		return
+	}
+	for _, stop := range cw.nodes {
+		if stop != nil {
+			stop()
+		}
+	}
+	delete(w.clusters, key)
+}
+
+func (w *watcher) startLocked(key types.NamespacedName, cw *clusterWatch, tag string) {
+	if w.base == nil {
+		cw.nodes[tag] = nil
+		return
+	}
+	ctx, cancel := context.WithCancel(w.base)
+	cw.nodes[tag] = cancel
+	go w.run(ctx, key, tag)
+}
+
+// run keeps the node subscribed until its context is cancelled
+func (w *watcher) run(ctx context.Context, key types.NamespacedName, tag string) {
+	logger := log.FromContext(ctx).WithValues("cluster", key, "node", tag)
+
+	for {
+		cluster := w.snapshot(key)
+		if cluster == nil {
+			return
+		}
+
+		err := w.subscribe(ctx, cluster, tag, func(n ravendb.Notification) { w.handle(ctx, key, tag, n) })
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			logger.V(1).Info("notification center subscription dropped", "error", err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.retry):
+		}
+	}
+}
+
+func (w *watcher) snapshot(key types.NamespacedName) *ravendbv1.RavenDBCluster {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if cw := w.clusters[key]; cw != nil {
+		return cw.cluster
+	}
+	return nil
+}
+
+// handle reports an alert unless it is below spec.notifications.minSeverity, was reported already or
+// the cluster ran out of Events for this minute
+func (w *watcher) handle(ctx context.Context, key types.NamespacedName, tag string, n ravendb.Notification) {
+	if n.Type != ravendb.NotificationAlertRaised {
+		return
+	}
+
+	w.mu.Lock()
+	cw := w.clusters[key]
+	if cw == nil || severityRank(n.Severity) < severityRank(string(cw.cluster.EffectiveNotificationMinSeverity())) {
+		w.mu.Unlock()
+		return
+	}
+	id := tag + "/" + n.Id
+	if a, ok := cw.seen[id]; ok && a.createdAt == n.CreatedAt {
+		w.mu.Unlock()
+		return
+	}
+	now := time.Now()
+	cw.seen[id] = seenAlert{createdAt: n.CreatedAt, reportedAt: now}
+	pruneSeenLocked(cw, now)
+
+	if !cw.limiter.Allow() {
+		cw.dropped++
+		w.mu.Unlock()
+		return
+	}
+	dropped := cw.dropped
+	cw.dropped = 0
+	cluster := cw.cluster
+	w.mu.Unlock()
+
+	w.emit(ctx, cluster, tag, n, dropped)
+}
+
+// emit puts the alert on the cluster and on the node's StatefulSet
+func (w *watcher) emit(ctx context.Context, cluster *ravendbv1.RavenDBCluster, tag string, n ravendb.Notification, dropped int) {
+	if w.rec == nil {
+		return
+	}
+
+	eventType := corev1.EventTypeNormal
+	if severityRank(n.Severity) >= severityRank(string(ravendbv1.NotificationSeverityWarning)) {
+		eventType = corev1.EventTypeWarning
+	}
+
+	where := "node " + tag
+	if n.Database != "" {
+		where = "database " + n.Database + " on node " + tag
+	}
+	msg := fmt.Sprintf("[%s] %s: %s", alertType(n), where, n.Title)
+	if n.Message != "" && n.Message != n.Title {
+		msg += ": " + n.Message
+	}
+	if dropped > 0 {
+		msg = fmt.Sprintf("%s (%d more alerts dropped by spec.notifications.eventsPerMinute)", msg, dropped)
+	}
+	if len(msg) > maxEventMessage {
+		msg = msg[:maxEventMessage-3] + "..."
+	}
+
+	w.rec.Event(cluster, eventType, reasonAlert, msg)
+
+	var sts appsv1.StatefulSet
+	name := naming.For(cluster).Node(tag)
+	if err := w.kc.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: name}, &sts); err != nil {
+		return
+	}
+	w.rec.Event(&sts, eventType, reasonAlert, msg)
+}
+
+func alertType(n ravendb.Notification) string {
+	if n.AlertType != "" {
+		return n.AlertType
+	}
+	return n.Type
+}
+
+// severityRank orders RavenDB's notification severities (None, Info, Success, Warning, Error)
+func severityRank(s string) int {
+	switch strings.ToLower(s) {
+	case "error":
+		return 3
+	case "warning":
+		return 2
+	case "info", "success":
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/ravendb"
+)
+
+func watchedCluster(perMinute int32, tags ...string) *ravendbv1.RavenDBCluster {
+	c := &ravendbv1.RavenDBCluster{ObjectMeta: metav1.ObjectMeta{Name: "ravendb", Namespace: "db"}}
+	for _, tag := range tags {
+		c.Spec.Nodes = append(c.Spec.Nodes, ravendbv1.RavenDBNode{Tag: tag})
+	}
+	c.Spec.Notifications = &ravendbv1.NotificationsSpec{Enabled: true, EventsPerMinute: &perMinute}
+	c.Status.Bootstrap = &ravendbv1.BootstrapStatus{Phase: ravendbv1.BootstrapPhaseCompleted}
+	return c
+}
+
+// feed is what the nodes send on every new subscription
+type feed struct {
+	mu     sync.Mutex
+	alerts map[string][]ravendb.Notification
+	// tag -> number of subscriptions made
+	subs map[string]int
+}
+
+func (f *feed) set(tag string, alerts ...ravendb.Notification) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.alerts[tag] = alerts
+}
+
+func (f *feed) of(tag string) []ravendb.Notification {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.subs[tag]++
+	return f.alerts[tag]
+}
+
+func (f *feed) subscriptions(tag string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.subs[tag]
+}
+
+// newTestWatcher replays the feed to every subscription and records the Events
+func newTestWatcher(t *testing.T, objs ...client.Object) (*watcher, *feed, *record.FakeRecorder) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, ravendbv1.AddToScheme(scheme))
+
+	f := &feed{alerts: map[string][]ravendb.Notification{}, subs: map[string]int{}}
+	rec := record.NewFakeRecorder(100)
+	w := NewWatcher(fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(), rec).(*watcher)
+	w.retry = time.Millisecond
+	w.subscribe = func(ctx context.Context, _ *ravendbv1.RavenDBCluster, tag string, fn func(ravendb.Notification)) error {
+		for _, n := range f.of(tag) {
+			fn(n)
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return w, f, rec
+}
+
+func start(t *testing.T, w *watcher) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() { _ = w.Start(ctx) }()
+	require.Eventually(t, func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return w.base != nil
+	}, time.Second, time.Millisecond)
+}
+
+func events(rec *record.FakeRecorder, n int, wait time.Duration) []string {
+	var out []string
+	deadline := time.After(wait)
+	for len(out) < n {
+		select {
+		case e := <-rec.Events:
+			out = append(out, e)
+		case <-deadline:
+			return out
+		}
+	}
+	return out
+}
+
+func lowDisk(created string) ravendb.Notification {
+	return ravendb.Notification{
+		Id: "AlertRaised/LowDiskSpace/A", Type: ravendb.NotificationAlertRaised, CreatedAt: created,
+		Title: "Low free disk space", Message: "only 2 GB left", Severity: "Warning", AlertType: "LowDiskSpace",
+	}
+}
+
+func Test_N1_AlertsBecomeEventsOnClusterAndStatefulSet(t *testing.T) {
+	sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "ravendb-a", Namespace: "db"}}
+	w, f, rec := newTestWatcher(t, sts)
+	f.set("A",
+		lowDisk("2025-01-01T10:00:00Z"),
+		lowDisk("2025-01-01T10:00:00Z"),
+		ravendb.Notification{Id: "AlertRaised/Info", Type: ravendb.NotificationAlertRaised, Severity: "Info", Title: "fyi"},
+		ravendb.Notification{Id: "DatabaseChanged/orders", Type: "DatabaseChanged"},
+	)
+
+	// synced before the manager started the watcher
+	w.Sync(watchedCluster(10, "A"))
+	start(t, w)
+
+	got := events(rec, 3, 200*time.Millisecond)
+	require.Equal(t, []string{
+		"Warning RavenDBAlert [LowDiskSpace] node A: Low free disk space: only 2 GB left",
+		"Warning RavenDBAlert [LowDiskSpace] node A: Low free disk space: only 2 GB left",
+	}, got)
+}
+
+func Test_N2_ResubscribingDoesNotRepeatAlerts(t *testing.T) {
+	w, f, rec := newTestWatcher(t)
+	f.set("A", lowDisk("2025-01-01T10:00:00Z"))
+	start(t, w)
+
+	c := watchedCluster(10, "A")
+	w.Sync(c)
+	require.Len(t, events(rec, 1, time.Second), 1)
+
+	// a new URL restarts the subscription, the node sends its active alerts again
+	c.Spec.Nodes[0].PublicServerUrl = "https://a.db.example.com"
+	w.Sync(c)
+	require.Eventually(t, func() bool { return f.subscriptions("A") == 2 }, time.Second, time.Millisecond)
+	require.Empty(t, events(rec, 1, 50*time.Millisecond))
+
+	// the same alert raised again is new
+	f.set("A", lowDisk("2025-01-02T10:00:00Z"))
+	c.Spec.ClientCertSecretRef = "client-cert-2"
+	w.Sync(c)
+	require.Len(t, events(rec, 1, time.Second), 1)
+}
+
+func Test_N3_EventsAreRateLimited(t *testing.T) {
+	var burst []ravendb.Notification
+	for i := 0; i < 5; i++ {
+		n := lowDisk("2025-01-01T10:00:00Z")
+		n.Id += string(rune('0' + i))
+		burst = append(burst, n)
+	}
+	w, f, rec := newTestWatcher(t)
+	f.set("A", burst...)
+	start(t, w)
+
+	w.Sync(watchedCluster(2, "A"))
+	require.Len(t, events(rec, 5, 100*time.Millisecond), 2)
+
+	w.mu.Lock()
+	require.Equal(t, 3, w.clusters[client.ObjectKey{Namespace: "db", Name: "ravendb"}].dropped)
+	w.mu.Unlock()
+}
+
+func Test_N4_DisablingStopsTheSubscriptions(t *testing.T) {
+	w, _, _ := newTestWatcher(t)
+	start(t, w)
+
+	c := watchedCluster(10, "A", "B")
+	w.Sync(c)
+	key := client.ObjectKeyFromObject(c)
+	require.Len(t, w.clusters[key].nodes, 2)
+
+	c.Spec.Nodes = c.Spec.Nodes[:1]
+	w.Sync(c)
+	require.Len(t, w.clusters[key].nodes, 1)
+
+	c.Spec.Notifications.Enabled = false
+	w.Sync(c)
+	require.NotContains(t, w.clusters, key)
+}
+
+func Test_N5_OnlyConnectionChangesResubscribe(t *testing.T) {
+	w, f, _ := newTestWatcher(t)
+	start(t, w)
+
+	c := watchedCluster(10, "A", "B")
+	w.Sync(c)
+	require.Eventually(t, func() bool { return f.subscriptions("A") == 1 && f.subscriptions("B") == 1 }, time.Second, time.Millisecond)
+
+	// severity and rate apply to the running subscriptions
+	c.Generation++
+	one := int32(1)
+	c.Spec.Notifications.EventsPerMinute = &one
+	c.Spec.Notifications.MinSeverity = "Error"
+	w.Sync(c)
+
+	// only the node which URL changed subscribes again
+	c.Generation++
+	c.Spec.Nodes[1].PublicServerUrl = "https://b.db.example.com"
+	w.Sync(c)
+	require.Eventually(t, func() bool { return f.subscriptions("B") == 2 }, 200*time.Millisecond, time.Millisecond)
+	require.Equal(t, 1, f.subscriptions("A"))
+}
+
+func Test_N6_SeenAlertsAreBounded(t *testing.T) {
+	w, f, rec := newTestWatcher(t)
+	f.set("A", lowDisk("2025-01-01T10:00:00Z"))
+	f.set("B", lowDisk("2025-01-01T10:00:00Z"))
+	start(t, w)
+
+	c := watchedCluster(10, "A", "B")
+	w.Sync(c)
+	require.Len(t, events(rec, 2, time.Second), 2)
+	key := client.ObjectKeyFromObject(c)
+
+	// a node that left forgets its alerts
+	c.Spec.Nodes = c.Spec.Nodes[:1]
+	w.Sync(c)
+	w.mu.Lock()
+	require.Len(t, w.clusters[key].seen, 1)
+	require.Contains(t, w.clusters[key].seen, "A/AlertRaised/LowDiskSpace/A")
+
+	// past the limit the expired alerts and then the oldest are dropped
+	cw := w.clusters[key]
+	now := time.Now()
+	for i := 0; i < maxSeenAlerts; i++ {
+		cw.seen[fmt.Sprintf("A/old-%d", i)] = seenAlert{reportedAt: now.Add(-seenAlertTTL - time.Duration(i+1)*time.Minute)}
+	}
+	pruneSeenLocked(cw, now)
+	require.Len(t, cw.seen, 1)
+	require.Contains(t, cw.seen, "A/AlertRaised/LowDiskSpace/A")
+
+	for i := 0; i <= maxSeenAlerts; i++ {
+		cw.seen[fmt.Sprintf("A/recent-%d", i)] = seenAlert{reportedAt: now.Add(-time.Duration(i+1) * time.Second)}
+	}
+	pruneSeenLocked(cw, now)
+	require.Len(t, cw.seen, maxSeenAlerts*3/4)
+	require.Contains(t, cw.seen, "A/recent-0")
+	require.NotContains(t, cw.seen, fmt.Sprintf("A/recent-%d", maxSeenAlerts))
+	w.mu.Unlock()
+}
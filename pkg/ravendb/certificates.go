@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ravendb
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Thumbprint is how RavenDB identifies a certificate: the SHA-1 of its DER bytes, upper case hex
+func Thumbprint(cert *x509.Certificate) string {
+	sum := sha1.Sum(cert.Raw)
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// CertificateRegistered reports whether node tag knows the certificate with thumbprint (GET /certificates)
+func (c *Client) CertificateRegistered(ctx context.Context, tag, thumbprint string) (bool, error) {
+	q := url.Values{}
+	q.Set("thumbprint", thumbprint)
+
+	var res struct {
+		Results []json.RawMessage
+	}
+	err := c.onNode(ctx, tag, request{method: http.MethodGet, path: "/certificates", query: q}, &res)
+	if IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return len(res.Results) > 0, nil
+}
+
+// RegisterCertificate trusts an existing certificate (its DER bytes) as a cluster admin on node tag (PUT /admin/certificates)
+func (c *Client) RegisterCertificate(ctx context.Context, tag, name string, der []byte) error {
+	body := map[string]any{
+		"Name":              name,
+		"Certificate":       base64.StdEncoding.EncodeToString(der),
+		"SecurityClearance": "ClusterAdmin",
+		"Permissions":       map[string]string{},
+	}
+	return c.onNode(ctx, tag, request{method: http.MethodPut, path: "/admin/certificates", body: body}, nil)
+}
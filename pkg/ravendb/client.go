@@ -0,0 +1,339 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ravendb is a typed client for the (admin) REST API of a RavenDB cluster.
+// a Client knows the nodes of the cluster by tag: node local calls (alive, stats, node info) go to that
+// node, cluster wide reads go to the first node that answers and Raft changes go to the leader.
+package ravendb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ravendbv1 "ravendb-operator/api/v1"
+)
+
+// Node is a RavenDB node the client can address by its tag
+type Node struct {
+	Tag string
+	URL string
+}
+
+// RetryPolicy decides how often a request is sent before its error is returned.
+// reads are retried on transport errors and 502/503/504, writes only on 503 (RavenDB didn't take them).
+type RetryPolicy struct {
+	Attempts   int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+var DefaultRetry = RetryPolicy{Attempts: 3, Backoff: 250 * time.Millisecond, MaxBackoff: 2 * time.Second}
+
+// a redirect to the leader is followed at most this many times per request
+const maxLeaderRedirects = 3
+
+const (
+	// bounds a single HTTP request (one attempt)
+	DefaultTimeout = 30 * time.Second
+	// bounds a call with all its retries and redirects, unless the caller's context has a deadline
+	DefaultCallTimeout = time.Minute
+)
+
+type Client struct {
+	http        *http.Client
+	nodes       []Node
+	retry       RetryPolicy
+	callTimeout time.Duration
+}
+
+type Option func(*Client)
+
+func WithRetry(p RetryPolicy) Option {
+	return func(c *Client) { c.retry = p }
+}
+
+func WithCallTimeout(d time.Duration) Option {
+	return func(c *Client) { c.callTimeout = d }
+}
+
+// New builds a client on top of httpc (nil means a plain client). a request without a timeout of
+// httpc gets DefaultTimeout. redirects are not followed by httpc, the client handles the ones to the
+// leader itself.
+func New(httpc *http.Client, nodes []Node, opts ...Option) *Client {
+	hc := &http.Client{}
+	if httpc != nil {
+		copied := *httpc
+		hc = &copied
+	}
+	if hc.Timeout == 0 {
+		hc.Timeout = DefaultTimeout
+	}
+	hc.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	c := &Client{http: hc, retry: DefaultRetry, callTimeout: DefaultCallTimeout}
+	for _, n := range nodes {
+		c.nodes = append(c.nodes, Node{Tag: strings.ToUpper(strings.TrimSpace(n.Tag)), URL: strings.TrimRight(n.URL, "/")})
+	}
+	for _, o := range opts {
+		o(c)
+	}
+	return c
+}
+
+// NodesOf returns the spec nodes of the cluster, in spec order
+func NodesOf(c *ravendbv1.RavenDBCluster) []Node {
+	out := make([]Node, 0, len(c.Spec.Nodes))
+	for _, n := range c.Spec.Nodes {
+		out = append(out, Node{Tag: n.Tag, URL: n.PublicServerUrl})
+	}
+	return out
+}
+
+// NewForCluster authenticates with the client certificate of the cluster (spec.clientCertSecretRef)
+func NewForCluster(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster, opts ...Option) (*Client, error) {
+	httpc, err := BuildHTTPSClientFromCluster(ctx, kc, c)
+	if err != nil {
+		return nil, err
+	}
+	return New(httpc, NodesOf(c), opts...), nil
+}
+
+// NewForClusterWithServerCert authenticates with the server certificate of node tag, see BuildServerCertHTTPSClient
+func NewForClusterWithServerCert(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster, tag string, opts ...Option) (*Client, error) {
+	httpc, err := BuildServerCertHTTPSClient(ctx, kc, c, tag)
+	if err != nil {
+		return nil, err
+	}
+	return New(httpc, NodesOf(c), opts...), nil
+}
+
+func (c *Client) Nodes() []Node {
+	return append([]Node(nil), c.nodes...)
+}
+
+func (c *Client) HasNode(tag string) bool {
+	_, err := c.nodeURL(tag)
+	return err == nil
+}
+
+func (c *Client) nodeURL(tag string) (string, error) {
+	for _, n := range c.nodes {
+		if strings.EqualFold(n.Tag, tag) && n.URL != "" {
+			return n.URL, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownNode, tag)
+}
+
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   any
+}
+
+type response struct {
+	code   int
+	header http.Header
+	body   []byte
+}
+
+// bounded gives a call without a deadline (e.g. the reconcile context) one of callTimeout
+func (c *Client) bounded(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || c.callTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.callTimeout)
+}
+
+// onNode sends req to node tag
+func (c *Client) onNode(ctx context.Context, tag string, req request, out any) error {
+	base, err := c.nodeURL(tag)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := c.bounded(ctx)
+	defer cancel()
+	return c.doAt(ctx, base, req, out)
+}
+
+// onAnyNode sends req to the nodes in order until one of them answers
+func (c *Client) onAnyNode(ctx context.Context, req request, out any) error {
+	if len(c.nodes) == 0 {
+		return ErrNoNodes
+	}
+	ctx, cancel := c.bounded(ctx)
+	defer cancel()
+	var errs []error
+	for _, n := range c.nodes {
+		err := c.doAt(ctx, n.URL, req, out)
+		if err == nil {
+			return nil
+		}
+		var se *StatusError
+		if errors.As(err, &se) && se.Code < 500 {
+			// the node answered, another node won't answer differently
+			return err
+		}
+		errs = append(errs, fmt.Errorf("node %s: %w", n.Tag, err))
+	}
+	return errors.Join(errs...)
+}
+
+// onLeader sends req to the Raft leader. a node that isn't the leader (anymore) answers with a
+// redirect to the leader, which is followed.
+func (c *Client) onLeader(ctx context.Context, req request, out any) error {
+	ctx, cancel := c.bounded(ctx)
+	defer cancel()
+
+	t, err := c.Topology(ctx)
+	if err != nil {
+		return err
+	}
+	base := t.LeaderURL()
+	if u, err := c.nodeURL(t.Leader); err == nil {
+		base = u
+	}
+	if base == "" {
+		return fmt.Errorf("no URL for leader %q", t.Leader)
+	}
+
+	for hop := 0; ; hop++ {
+		resp, err := c.send(ctx, base, req)
+		if err != nil {
+			return err
+		}
+		loc := resp.header.Get("Location")
+		if (resp.code == http.StatusTemporaryRedirect || resp.code == http.StatusPermanentRedirect) && loc != "" && hop < maxLeaderRedirects {
+			next, err := url.Parse(loc)
+			if err != nil || next.Host == "" {
+				return fmt.Errorf("%s %s: bad redirect to %q", req.method, req.path, loc)
+			}
+			base = next.Scheme + "://" + next.Host
+			continue
+		}
+		return decode(req, resp, out)
+	}
+}
+
+func (c *Client) doAt(ctx context.Context, base string, req request, out any) error {
+	resp, err := c.send(ctx, base, req)
+	if err != nil {
+		return err
+	}
+	return decode(req, resp, out)
+}
+
+// send sends req to base, retried according to the retry policy
+func (c *Client) send(ctx context.Context, base string, req request) (*response, error) {
+	wait := c.retry.Backoff
+	for attempt := 1; ; attempt++ {
+		resp, err := c.sendOnce(ctx, base, req)
+		if attempt >= c.retry.Attempts || !retryable(req.method, resp, err) || ctx.Err() != nil {
+			return resp, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return resp, err
+		case <-time.After(wait):
+		}
+		wait *= 2
+		if c.retry.MaxBackoff > 0 && wait > c.retry.MaxBackoff {
+			wait = c.retry.MaxBackoff
+		}
+	}
+}
+
+func (c *Client) sendOnce(ctx context.Context, base string, req request) (*response, error) {
+	endpoint := strings.TrimRight(base, "/") + req.path
+	if len(req.query) > 0 {
+		endpoint += "?" + req.query.Encode()
+	}
+
+	var reader io.Reader
+	if req.body != nil {
+		raw, err := json.Marshal(req.body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	hr, err := http.NewRequestWithContext(ctx, req.method, endpoint, reader)
+	if err != nil {
+		return nil, err
+	}
+	if req.body != nil {
+		hr.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(hr)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &response{code: resp.StatusCode, header: resp.Header, body: body}, nil
+}
+
+func retryable(method string, resp *response, err error) bool {
+	if err != nil {
+		return method == http.MethodGet
+	}
+	switch resp.code {
+	case http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return method == http.MethodGet
+	}
+	return false
+}
+
+func decode(req request, resp *response, out any) error {
+	if resp.code < 200 || resp.code >= 300 {
+		return &StatusError{Method: req.method, Path: req.path, Code: resp.code, Body: shorten(resp.body)}
+	}
+	if out == nil || len(bytes.TrimSpace(resp.body)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(resp.body, out); err != nil {
+		return fmt.Errorf("decode %s %s: %w", req.method, req.path, err)
+	}
+	return nil
+}
+
+func shorten(body []byte) string {
+	s := strings.Join(strings.Fields(string(body)), " ")
+	if len(s) > 200 {
+		return s[:200]
+	}
+	return s
+}
@@ -0,0 +1,329 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ravendb_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/ravendb"
+	"ravendb-operator/pkg/ravendb/fake"
+)
+
+var fastRetry = ravendb.WithRetry(ravendb.RetryPolicy{Attempts: 3, Backoff: time.Millisecond})
+
+func Test_RC1_TopologySkipsNodesWithoutLeader(t *testing.T) {
+	srv := fake.New("A", "B", "C")
+	srv.SetDown("A", true)
+	srv.SetRole("B", "")
+
+	topo, err := srv.Client().Topology(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "C", topo.NodeTag)
+	require.Equal(t, "A", topo.Leader)
+	require.Equal(t, ravendbv1.NodeRoleMember, topo.RoleOf("c"))
+	require.Equal(t, ravendbv1.NodeClusterRole(""), topo.RoleOf("B"))
+	require.Equal(t, fake.URL("A"), topo.LeaderURL())
+}
+
+func Test_RC2_TopologyWithoutLeaderFails(t *testing.T) {
+	srv := fake.New("A", "B")
+	srv.SetRole("A", "")
+	srv.SetRole("B", "")
+
+	_, err := srv.Client().Topology(context.Background())
+	require.ErrorIs(t, err, ravendb.ErrNoLeader)
+}
+
+func Test_RC3_AddAndRemoveNodeGoThroughTheLeader(t *testing.T) {
+	srv := fake.New("A", "B", "C")
+	srv.SetRole("C", "")
+	srv.SetLeader("B")
+	rc := srv.Client()
+
+	require.NoError(t, rc.AddNode(context.Background(), fake.URL("C"), "c", true))
+	require.Equal(t, ravendbv1.NodeRoleWatcher, srv.Role("C"))
+
+	require.NoError(t, rc.RemoveNode(context.Background(), "A"))
+	require.Equal(t, ravendbv1.NodeClusterRole(""), srv.Role("A"))
+
+	require.Contains(t, srv.Requests(), "B PUT /admin/cluster/node")
+	require.NotContains(t, srv.Requests(), "A PUT /admin/cluster/node")
+}
+
+func Test_RC4_StaleLeaderIsRedirected(t *testing.T) {
+	srv := fake.New("A", "B", "C")
+	srv.SetLeader("B")
+	srv.SetStaleLeader("A", "A")
+	srv.SetRole("C", "")
+
+	require.NoError(t, srv.Client().AddNode(context.Background(), fake.URL("C"), "C", false))
+	require.Equal(t, ravendbv1.NodeRoleMember, srv.Role("C"))
+	require.Contains(t, srv.Requests(), "A PUT /admin/cluster/node")
+	require.Contains(t, srv.Requests(), "B PUT /admin/cluster/node")
+}
+
+func Test_RC5_ReadsAreRetried(t *testing.T) {
+	srv := fake.New("A")
+	srv.Fail("A", http.MethodGet, "/setup/alive", http.StatusBadGateway, 2)
+
+	require.NoError(t, srv.Client(fastRetry).Alive(context.Background(), "A"))
+}
+
+func Test_RC6_WritesAreOnlyRetriedOnServiceUnavailable(t *testing.T) {
+	srv := fake.New("A")
+	srv.Fail("A", http.MethodPost, "/admin/cluster/reelect", http.StatusBadGateway, 1)
+
+	err := srv.Client(fastRetry).Reelect(context.Background(), "A")
+	var se *ravendb.StatusError
+	require.True(t, errors.As(err, &se))
+	require.Equal(t, http.StatusBadGateway, se.Code)
+
+	srv.Fail("A", http.MethodPost, "/admin/cluster/reelect", http.StatusServiceUnavailable, 2)
+	require.NoError(t, srv.Client(fastRetry).Reelect(context.Background(), "A"))
+}
+
+func Test_RC7_RetriesStopWithTheContext(t *testing.T) {
+	srv := fake.New("A")
+	srv.SetDown("A", true)
+	rc := srv.Client(ravendb.WithRetry(ravendb.RetryPolicy{Attempts: 100, Backoff: time.Hour}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	require.Error(t, rc.Alive(ctx, "A"))
+	require.Less(t, time.Since(start), 5*time.Second)
+}
+
+func Test_RC8_UnknownNode(t *testing.T) {
+	rc := fake.New("A").Client()
+
+	require.ErrorIs(t, rc.Alive(context.Background(), "Z"), ravendb.ErrUnknownNode)
+	require.False(t, rc.HasNode("Z"))
+	require.True(t, rc.HasNode("a"))
+}
+
+func Test_RC9_DatabasesAndStats(t *testing.T) {
+	srv := fake.New("A", "B")
+	srv.SetDatabases(ravendb.Database{
+		Name:              "orders",
+		ReplicationFactor: 2,
+		NodesTopology: ravendb.DatabaseTopology{
+			Members: []ravendb.DatabaseNode{{NodeTag: "A"}, {NodeTag: "B"}},
+			Rehabs:  []ravendb.DatabaseNode{{NodeTag: "B"}},
+			Status:  map[string]ravendb.DatabaseNodeStatus{"A": {LastStatus: "Ok"}},
+		},
+	})
+	srv.SetStats("B", "orders", ravendb.DatabaseStats{
+		DatabaseChangeVector: "A:10-x",
+		Indexes:              []ravendb.IndexStats{{Name: "Orders/ByDate", IsStale: true}},
+	})
+	rc := srv.Client()
+
+	dbs, err := rc.Databases(context.Background())
+	require.NoError(t, err)
+	require.Len(t, dbs, 1)
+	require.Equal(t, []string{"A", "B"}, dbs[0].NodesTopology.Tags())
+	require.True(t, dbs[0].NodesTopology.Hosts("b"))
+	require.Equal(t, "Ok", dbs[0].NodesTopology.Status["A"].LastStatus)
+
+	st, err := rc.DatabaseStats(context.Background(), "B", "orders")
+	require.NoError(t, err)
+	require.True(t, st.Indexes[0].IsStale)
+
+	_, err = rc.DatabaseStats(context.Background(), "A", "orders")
+	require.True(t, ravendb.IsNotFound(err))
+}
+
+func Test_RC10_CertificateRegistration(t *testing.T) {
+	srv := fake.New("A")
+	rc := srv.Client()
+	cert := selfSigned(t)
+	tp := ravendb.Thumbprint(cert)
+
+	ok, err := rc.CertificateRegistered(context.Background(), "A", tp)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, rc.RegisterCertificate(context.Background(), "A", "ravendb-operator", cert.Raw))
+	require.Equal(t, map[string]string{tp: "ravendb-operator"}, srv.Certificates())
+
+	ok, err = rc.CertificateRegistered(context.Background(), "A", tp)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func Test_RC11_NodeInfoVersionLicenseAndPing(t *testing.T) {
+	srv := fake.New("A", "B")
+	srv.SetVersion("B", "6.0.110")
+	srv.SetDown("B", true)
+	rc := srv.Client()
+
+	info, err := rc.NodeInfo(context.Background(), "A")
+	require.NoError(t, err)
+	require.Equal(t, "A", info.NodeTag)
+	require.Equal(t, "Leader", info.CurrentState)
+
+	srv.SetDown("B", false)
+	v, err := rc.BuildVersion(context.Background(), "B")
+	require.NoError(t, err)
+	require.Equal(t, "6.0", v.ProductVersion)
+	require.Equal(t, "6.0.110", v.FullVersion)
+
+	l, err := rc.License(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "Developer", l.Type)
+
+	srv.SetDown("B", true)
+	res, err := rc.Ping(context.Background())
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	require.NotEmpty(t, res[1].TcpInfo.Error)
+}
+
+func Test_RC12_WatchNotifications(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/server/notification-center/watch", r.URL.Path)
+		conn, err := upgrader.Upgrade(w, r, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"Id":"AlertRaised/LowDiskSpace","Type":"AlertRaised","AlertType":"LowDiskSpace","Severity":"Warning","Title":"Low disk"}`))
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{}`))
+		_, _, _ = conn.ReadMessage()
+	}))
+	defer srv.Close()
+
+	rc := ravendb.New(srv.Client(), []ravendb.Node{{Tag: "A", URL: srv.URL}})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var got []ravendb.Notification
+	err := rc.WatchNotifications(ctx, "A", func(n ravendb.Notification) {
+		got = append(got, n)
+		cancel()
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Len(t, got, 1)
+	require.Equal(t, "LowDiskSpace", got[0].AlertType)
+}
+
+func selfSigned(t *testing.T) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "ravendb-operator"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+func Test_RC13_DatabaseRecordLifecycle(t *testing.T) {
+	srv := fake.New("A", "B", "C")
+	srv.SetLeader("B")
+	rc := srv.Client()
+	ctx := context.Background()
+
+	rec, err := rc.DatabaseRecord(ctx, "orders")
+	require.NoError(t, err)
+	require.Nil(t, rec)
+
+	require.NoError(t, rc.CreateDatabase(ctx, ravendb.DatabaseRecord{
+		DatabaseName: "orders",
+		Topology:     &ravendb.RecordTopology{Members: []string{"C"}, ReplicationFactor: 1},
+		Settings:     map[string]string{"Indexing.MapBatchSize": "128"},
+	}, 1))
+	require.NoError(t, rc.AddDatabaseNode(ctx, "orders", "a"))
+	require.NoError(t, rc.PutDatabaseSettings(ctx, "orders", map[string]string{"Indexing.MapTimeoutInSec": "30"}))
+
+	rec, err = rc.DatabaseRecord(ctx, "orders")
+	require.NoError(t, err)
+	require.Equal(t, []string{"C"}, rec.Topology.Members)
+	require.Equal(t, []string{"A"}, rec.Topology.Promotables)
+	require.Equal(t, "30", rec.Settings["Indexing.MapTimeoutInSec"])
+
+	require.NoError(t, rc.DeleteDatabase(ctx, "orders", "c"))
+	require.Empty(t, srv.Record("orders").Topology.Members)
+	require.NoError(t, rc.DeleteDatabase(ctx, "orders"))
+	require.Nil(t, srv.Record("orders"))
+	require.NoError(t, rc.DeleteDatabase(ctx, "orders"))
+
+	// the writes went to the leader
+	for _, r := range srv.Requests() {
+		require.False(t, strings.HasPrefix(r, "A ") && !strings.HasPrefix(r, "A GET"), r)
+	}
+}
+
+func Test_RC14_NodeRemovableNeedsAnotherHealthyReplica(t *testing.T) {
+	orders := ravendb.Database{
+		Name: "orders",
+		NodesTopology: ravendb.DatabaseTopology{
+			Members: []ravendb.DatabaseNode{{NodeTag: "A"}, {NodeTag: "B"}},
+			Status:  map[string]ravendb.DatabaseNodeStatus{"A": {LastStatus: "Ok"}, "B": {LastError: "connection refused"}},
+		},
+	}
+
+	ok, _ := ravendb.NodeRemovable([]ravendb.Database{orders}, "B")
+	require.True(t, ok)
+	ok, info := ravendb.NodeRemovable([]ravendb.Database{orders}, "a")
+	require.False(t, ok)
+	require.Contains(t, info, "db=orders")
+	ok, _ = ravendb.NodeRemovable([]ravendb.Database{orders}, "C")
+	require.True(t, ok)
+}
+
+func Test_RC15_CallsWithoutDeadlineAreBounded(t *testing.T) {
+	release := make(chan struct{})
+	hang := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer hang.Close()
+	defer close(release)
+
+	rc := ravendb.New(&http.Client{}, []ravendb.Node{{Tag: "A", URL: hang.URL}}, ravendb.WithCallTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	err := rc.Alive(context.Background(), "A")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), 5*time.Second)
+}
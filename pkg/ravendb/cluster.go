@@ -0,0 +1,193 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ravendb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	ravendbv1 "ravendb-operator/api/v1"
+)
+
+// Topology is the subset of GET /cluster/topology we care about
+type Topology struct {
+	Leader       string
+	CurrentState string
+	CurrentTerm  int64
+	// tag of the node that answered
+	NodeTag  string
+	Topology struct {
+		Members     map[string]string
+		Promotables map[string]string
+		Watchers    map[string]string
+	}
+}
+
+// RoleOf returns the role of tag in the topology, or "" when the node is not part of the cluster
+func (t *Topology) RoleOf(tag string) ravendbv1.NodeClusterRole {
+	tag = strings.ToUpper(tag)
+	if _, ok := t.Topology.Members[tag]; ok {
+		return ravendbv1.NodeRoleMember
+	}
+	if _, ok := t.Topology.Promotables[tag]; ok {
+		return ravendbv1.NodeRolePromotable
+	}
+	if _, ok := t.Topology.Watchers[tag]; ok {
+		return ravendbv1.NodeRoleWatcher
+	}
+	return ""
+}
+
+// URLOf returns the URL the cluster knows tag by, or ""
+func (t *Topology) URLOf(tag string) string {
+	tag = strings.ToUpper(tag)
+	for _, m := range []map[string]string{t.Topology.Members, t.Topology.Promotables, t.Topology.Watchers} {
+		if u, ok := m[tag]; ok {
+			return strings.TrimRight(u, "/")
+		}
+	}
+	return ""
+}
+
+func (t *Topology) LeaderURL() string {
+	if t.Leader == "" {
+		return ""
+	}
+	return t.URLOf(t.Leader)
+}
+
+// Tags returns all nodes of the topology, whatever their role
+func (t *Topology) Tags() []string {
+	var out []string
+	for _, m := range []map[string]string{t.Topology.Members, t.Topology.Promotables, t.Topology.Watchers} {
+		for tag := range m {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
+// Topology reads /cluster/topology from the nodes in order and returns the first one that knows a leader
+func (c *Client) Topology(ctx context.Context) (*Topology, error) {
+	if len(c.nodes) == 0 {
+		return nil, ErrNoNodes
+	}
+	var errs []error
+	for _, n := range c.nodes {
+		t, err := c.NodeTopology(ctx, n.Tag)
+		if err == nil && t.Leader != "" {
+			return t, nil
+		}
+		if err == nil {
+			err = ErrNoLeader
+		}
+		errs = append(errs, fmt.Errorf("node %s: %w", n.Tag, err))
+	}
+	return nil, fmt.Errorf("read cluster topology: %w", errors.Join(errs...))
+}
+
+// NodeTopology is the topology as node tag sees it, with or without a leader
+func (c *Client) NodeTopology(ctx context.Context, tag string) (*Topology, error) {
+	var t Topology
+	if err := c.onNode(ctx, tag, request{method: http.MethodGet, path: "/cluster/topology"}, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// AddNode asks the leader to add a node to the cluster (PUT /admin/cluster/node)
+func (c *Client) AddNode(ctx context.Context, nodeURL, tag string, watcher bool) error {
+	q := url.Values{}
+	q.Set("url", nodeURL)
+	q.Set("tag", strings.ToUpper(tag))
+	if watcher {
+		q.Set("watcher", "true")
+	}
+	return c.onLeader(ctx, request{method: http.MethodPut, path: "/admin/cluster/node", query: q}, nil)
+}
+
+// RemoveNode asks the leader to remove a node from the cluster (DELETE /admin/cluster/node)
+func (c *Client) RemoveNode(ctx context.Context, tag string) error {
+	q := url.Values{}
+	q.Set("nodeTag", strings.ToUpper(tag))
+	return c.onLeader(ctx, request{method: http.MethodDelete, path: "/admin/cluster/node", query: q}, nil)
+}
+
+// Reelect asks node tag, the leader, to give up leadership so the cluster elects another node
+func (c *Client) Reelect(ctx context.Context, tag string) error {
+	return c.onNode(ctx, tag, request{method: http.MethodPost, path: "/admin/cluster/reelect"}, nil)
+}
+
+// Alive probes /setup/alive on node tag. any answer but a 2xx is an error.
+func (c *Client) Alive(ctx context.Context, tag string) error {
+	return c.onNode(ctx, tag, request{method: http.MethodGet, path: "/setup/alive"}, nil)
+}
+
+// PingResult is how the node that was asked reaches one of the nodes of the cluster
+type PingResult struct {
+	Url        string
+	SetupAlive struct{ Error string }
+	TcpInfo    struct{ Error string }
+}
+
+// Ping lets the first node that answers reach out to every node of the cluster (GET /admin/debug/node/ping)
+func (c *Client) Ping(ctx context.Context) ([]PingResult, error) {
+	var res struct {
+		Result []PingResult
+	}
+	if err := c.onAnyNode(ctx, request{method: http.MethodGet, path: "/admin/debug/node/ping"}, &res); err != nil {
+		return nil, err
+	}
+	return res.Result, nil
+}
+
+// NodeInfo is the subset of GET /cluster/node-info we care about
+type NodeInfo struct {
+	NodeTag      string
+	TopologyId   string
+	ServerId     string
+	CurrentState string
+	ServerRole   string
+}
+
+func (c *Client) NodeInfo(ctx context.Context, tag string) (*NodeInfo, error) {
+	var info NodeInfo
+	if err := c.onNode(ctx, tag, request{method: http.MethodGet, path: "/cluster/node-info"}, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// BuildVersion is GET /build/version, e.g. ProductVersion "6.2" and FullVersion "6.2.5"
+type BuildVersion struct {
+	BuildVersion   int
+	ProductVersion string
+	CommitHash     string
+	FullVersion    string
+}
+
+func (c *Client) BuildVersion(ctx context.Context, tag string) (*BuildVersion, error) {
+	var v BuildVersion
+	if err := c.onNode(ctx, tag, request{method: http.MethodGet, path: "/build/version"}, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
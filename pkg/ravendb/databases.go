@@ -0,0 +1,211 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ravendb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type DatabaseNode struct {
+	NodeTag string
+	NodeUrl string
+}
+
+type DatabaseNodeStatus struct {
+	LastStatus string
+	LastError  string
+}
+
+type DatabaseTopology struct {
+	Members     []DatabaseNode
+	Promotables []DatabaseNode
+	Rehabs      []DatabaseNode
+	// by node tag
+	Status map[string]DatabaseNodeStatus
+}
+
+// Tags returns the nodes of the database group (members, promotables and rehabs), without duplicates
+func (t *DatabaseTopology) Tags() []string {
+	seen := map[string]struct{}{}
+	var out []string
+	for _, group := range [][]DatabaseNode{t.Members, t.Promotables, t.Rehabs} {
+		for _, n := range group {
+			tag := strings.TrimSpace(n.NodeTag)
+			if tag == "" {
+				continue
+			}
+			if _, ok := seen[tag]; !ok {
+				seen[tag] = struct{}{}
+				out = append(out, tag)
+			}
+		}
+	}
+	return out
+}
+
+// Hosts reports whether tag is part of the database group
+func (t *DatabaseTopology) Hosts(tag string) bool {
+	for _, hosted := range t.Tags() {
+		if strings.EqualFold(hosted, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// NodeRemovable checks that taking tag out of the cluster for good doesn't lose data: every database
+// group the node belongs to must have at least one other node with LastStatus==Ok. it tells why not.
+func NodeRemovable(dbs []Database, tag string) (bool, string) {
+	for _, db := range dbs {
+		if !db.NodesTopology.Hosts(tag) {
+			continue
+		}
+
+		healthy := false
+		for _, t := range db.NodesTopology.Tags() {
+			if !strings.EqualFold(t, tag) && strings.EqualFold(strings.TrimSpace(db.NodesTopology.Status[t].LastStatus), "ok") {
+				healthy = true
+			}
+		}
+		if !healthy {
+			return false, fmt.Sprintf("db=%s has no other healthy replica besides node %s", db.Name, tag)
+		}
+	}
+	return true, ""
+}
+
+// Database is the subset of an entry of GET /databases we care about
+type Database struct {
+	Name              string
+	Disabled          bool
+	ReplicationFactor int
+	NodesTopology     DatabaseTopology
+}
+
+// Databases lists the databases of the cluster as the first node that answers sees them
+func (c *Client) Databases(ctx context.Context) ([]Database, error) {
+	var res struct {
+		Databases []Database
+	}
+	if err := c.onAnyNode(ctx, request{method: http.MethodGet, path: "/databases"}, &res); err != nil {
+		return nil, err
+	}
+	return res.Databases, nil
+}
+
+type IndexStats struct {
+	Name    string
+	IsStale bool
+}
+
+// DatabaseStats is the subset of GET /databases/{db}/stats we care about
+type DatabaseStats struct {
+	DatabaseChangeVector string
+	Indexes              []IndexStats
+}
+
+// DatabaseStats asks node tag itself, stats are local to the node
+func (c *Client) DatabaseStats(ctx context.Context, tag, db string) (*DatabaseStats, error) {
+	var st DatabaseStats
+	if err := c.onNode(ctx, tag, request{method: http.MethodGet, path: "/databases/" + url.PathEscape(db) + "/stats"}, &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// DatabaseRecord is the subset of the database record (GET /admin/databases) we care about
+type DatabaseRecord struct {
+	DatabaseName string
+	Disabled     bool
+	Topology     *RecordTopology   `json:",omitempty"`
+	Settings     map[string]string `json:",omitempty"`
+}
+
+// RecordTopology is the database group as the record stores it, by node tag
+type RecordTopology struct {
+	Members           []string
+	Promotables       []string
+	Rehabs            []string
+	ReplicationFactor int
+}
+
+// DatabaseRecord returns nil (and no error) when the database does not exist
+func (c *Client) DatabaseRecord(ctx context.Context, name string) (*DatabaseRecord, error) {
+	q := url.Values{}
+	q.Set("name", name)
+
+	var rec *DatabaseRecord
+	err := c.onAnyNode(ctx, request{method: http.MethodGet, path: "/admin/databases", query: q}, &rec)
+	if IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// CreateDatabase creates the database. a record with a topology pins the database to its members,
+// otherwise RavenDB picks replicationFactor nodes by itself.
+func (c *Client) CreateDatabase(ctx context.Context, rec DatabaseRecord, replicationFactor int) error {
+	q := url.Values{}
+	q.Set("name", rec.DatabaseName)
+	q.Set("replicationFactor", strconv.Itoa(replicationFactor))
+	return c.onLeader(ctx, request{method: http.MethodPut, path: "/admin/databases", query: q, body: rec}, nil)
+}
+
+// AddDatabaseNode adds node tag to the database group. RavenDB starts it as a promotable and
+// promotes it to member once it caught up.
+func (c *Client) AddDatabaseNode(ctx context.Context, db, tag string) error {
+	q := url.Values{}
+	q.Set("name", db)
+	q.Set("node", strings.ToUpper(tag))
+	return c.onLeader(ctx, request{method: http.MethodPut, path: "/admin/databases/node", query: q}, nil)
+}
+
+// DeleteDatabase removes the database and its data from fromNodes, or from the whole cluster when
+// none are given. a database that doesn't exist is not an error.
+func (c *Client) DeleteDatabase(ctx context.Context, db string, fromNodes ...string) error {
+	body := map[string]any{
+		"DatabaseNames": []string{db},
+		"HardDelete":    true,
+	}
+	if len(fromNodes) > 0 {
+		tags := make([]string, 0, len(fromNodes))
+		for _, t := range fromNodes {
+			tags = append(tags, strings.ToUpper(t))
+		}
+		body["FromNodes"] = tags
+	}
+
+	err := c.onLeader(ctx, request{method: http.MethodDelete, path: "/admin/databases", body: body}, nil)
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// PutDatabaseSettings stores database level configuration. most settings take effect once the database is reloaded.
+func (c *Client) PutDatabaseSettings(ctx context.Context, db string, settings map[string]string) error {
+	body := map[string]any{"Configuration": settings}
+	return c.onLeader(ctx, request{method: http.MethodPost, path: "/databases/" + url.PathEscape(db) + "/admin/configuration/settings", body: body}, nil)
+}
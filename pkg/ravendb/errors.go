@@ -0,0 +1,47 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ravendb
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	ErrUnknownNode = errors.New("unknown node")
+	ErrNoNodes     = errors.New("no nodes")
+	ErrNoLeader    = errors.New("no leader")
+)
+
+// StatusError is a non 2xx answer of a node
+type StatusError struct {
+	Method string
+	Path   string
+	Code   int
+	// first 200 characters of the body, whitespace collapsed
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s %s: HTTP %d (%s)", e.Method, e.Path, e.Code, e.Body)
+}
+
+func IsNotFound(err error) bool {
+	var se *StatusError
+	return errors.As(err, &se) && se.Code == http.StatusNotFound
+}
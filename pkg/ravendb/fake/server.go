@@ -0,0 +1,561 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fake is an in-memory RavenDB cluster for unit tests. the Server is an http.RoundTripper:
+// requests are routed to a node by the host of their URL, so a ravendb.Client built with Server.Client
+// talks to it without any network.
+package fake
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/ravendb"
+)
+
+type node struct {
+	tag     string
+	url     string
+	role    ravendbv1.NodeClusterRole
+	down    bool
+	version string
+	stats   map[string]ravendb.DatabaseStats
+	// leader the node still believes in, see SetStaleLeader
+	staleLeader string
+}
+
+type failure struct {
+	code  int
+	times int
+}
+
+// Server holds the state of the cluster. the zero value is not usable, see New.
+type Server struct {
+	mu        sync.Mutex
+	nodes     []*node
+	leader    string
+	term      int64
+	databases []ravendb.Database
+	records   map[string]*ravendb.DatabaseRecord
+	certs     map[string]string
+	license   ravendb.License
+	failures  map[string]*failure
+	requests  []string
+}
+
+// New returns a cluster of members with the given tags, the first one leads.
+// node X answers on https://x.ravendb.fake.
+func New(tags ...string) *Server {
+	s := &Server{
+		term:     1,
+		records:  map[string]*ravendb.DatabaseRecord{},
+		certs:    map[string]string{},
+		failures: map[string]*failure{},
+		license:  ravendb.License{Id: "fake", LicensedTo: "fake", Type: "Developer", Status: "Valid"},
+	}
+	for _, tag := range tags {
+		tag = strings.ToUpper(tag)
+		s.nodes = append(s.nodes, &node{
+			tag:     tag,
+			url:     URL(tag),
+			role:    ravendbv1.NodeRoleMember,
+			version: "6.2.5",
+			stats:   map[string]ravendb.DatabaseStats{},
+		})
+	}
+	if len(s.nodes) > 0 {
+		s.leader = s.nodes[0].tag
+	}
+	return s
+}
+
+// URL is the URL of node tag
+func URL(tag string) string {
+	return "https://" + strings.ToLower(tag) + ".ravendb.fake"
+}
+
+// Nodes returns the nodes of the server as a client knows them
+func (s *Server) Nodes() []ravendb.Node {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]ravendb.Node, 0, len(s.nodes))
+	for _, n := range s.nodes {
+		out = append(out, ravendb.Node{Tag: n.tag, URL: n.url})
+	}
+	return out
+}
+
+// Client returns a client for all nodes of the server. requests are not retried unless opts say so.
+func (s *Server) Client(opts ...ravendb.Option) *ravendb.Client {
+	opts = append([]ravendb.Option{ravendb.WithRetry(ravendb.RetryPolicy{Attempts: 1})}, opts...)
+	return ravendb.New(&http.Client{Transport: s}, s.Nodes(), opts...)
+}
+
+// SetRole puts node tag into the topology with role, "" takes it out (a standalone node)
+func (s *Server) SetRole(tag string, role ravendbv1.NodeClusterRole) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.node(tag).role = role
+}
+
+func (s *Server) SetLeader(tag string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.leader = strings.ToUpper(tag)
+	s.term++
+}
+
+// SetStaleLeader makes node tag report leader on /cluster/topology, as a follower that didn't see the
+// last election yet does. "" clears it.
+func (s *Server) SetStaleLeader(tag, leader string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.node(tag).staleLeader = strings.ToUpper(leader)
+}
+
+func (s *Server) Leader() (string, int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.leader, s.term
+}
+
+// SetDown makes node tag refuse connections
+func (s *Server) SetDown(tag string, down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.node(tag).down = down
+}
+
+func (s *Server) SetVersion(tag, version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.node(tag).version = version
+}
+
+func (s *Server) SetDatabases(dbs ...ravendb.Database) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.databases = dbs
+}
+
+// Record returns the database record of name, nil when there is no such database
+func (s *Server) Record(name string) *ravendb.DatabaseRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec, ok := s.records[name]
+	if !ok {
+		return nil
+	}
+	out := *rec
+	if rec.Topology != nil {
+		t := *rec.Topology
+		out.Topology = &t
+	}
+	return &out
+}
+
+func (s *Server) SetStats(tag, db string, st ravendb.DatabaseStats) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.node(tag).stats[db] = st
+}
+
+func (s *Server) SetLicense(l ravendb.License) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.license = l
+}
+
+// Fail makes the next times requests "METHOD path" to node tag answer with code
+func (s *Server) Fail(tag, method, path string, code, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[failureKey(tag, method, path)] = &failure{code: code, times: times}
+}
+
+// Role returns the role of node tag in the topology
+func (s *Server) Role(tag string) ravendbv1.NodeClusterRole {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n := s.node(tag); n != nil {
+		return n.role
+	}
+	return ""
+}
+
+// Certificates returns the registered certificates, name by thumbprint
+func (s *Server) Certificates() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]string, len(s.certs))
+	for k, v := range s.certs {
+		out[k] = v
+	}
+	return out
+}
+
+// Requests returns the requests served so far as "TAG METHOD path"
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) RoundTrip(req *http.Request) (*http.Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var n *node
+	for _, cand := range s.nodes {
+		if strings.EqualFold(strings.TrimPrefix(cand.url, "https://"), req.URL.Host) {
+			n = cand
+		}
+	}
+	if n == nil || n.down {
+		return nil, fmt.Errorf("dial tcp %s: connection refused", req.URL.Host)
+	}
+	s.requests = append(s.requests, n.tag+" "+req.Method+" "+req.URL.Path)
+
+	if f := s.failures[failureKey(n.tag, req.Method, req.URL.Path)]; f != nil && f.times > 0 {
+		f.times--
+		return reply(req, f.code, map[string]string{"Message": "injected failure"}), nil
+	}
+
+	return s.serve(n, req), nil
+}
+
+func (s *Server) serve(n *node, req *http.Request) *http.Response {
+	q := req.URL.Query()
+	route := req.Method + " " + req.URL.Path
+
+	switch {
+	case route == "GET /setup/alive":
+		return reply(req, http.StatusOK, nil)
+
+	case route == "GET /cluster/topology":
+		return reply(req, http.StatusOK, s.topology(n))
+
+	case route == "GET /cluster/node-info":
+		return reply(req, http.StatusOK, ravendb.NodeInfo{
+			NodeTag: n.tag, TopologyId: "fake", ServerId: "fake-" + n.tag, CurrentState: s.state(n),
+		})
+
+	case route == "GET /build/version":
+		return reply(req, http.StatusOK, ravendb.BuildVersion{ProductVersion: productVersion(n.version), FullVersion: n.version})
+
+	case route == "GET /license/status":
+		return reply(req, http.StatusOK, s.license)
+
+	case route == "GET /databases":
+		return reply(req, http.StatusOK, map[string]any{"Databases": s.databases})
+
+	case req.Method == http.MethodGet && strings.HasPrefix(req.URL.Path, "/databases/") && strings.HasSuffix(req.URL.Path, "/stats"):
+		db := strings.TrimSuffix(strings.TrimPrefix(req.URL.Path, "/databases/"), "/stats")
+		st, ok := n.stats[db]
+		if !ok {
+			return reply(req, http.StatusNotFound, map[string]string{"Message": "database " + db + " not found"})
+		}
+		return reply(req, http.StatusOK, st)
+
+	case route == "GET /admin/debug/node/ping":
+		var res []ravendb.PingResult
+		for _, other := range s.nodes {
+			if other.role == "" {
+				continue
+			}
+			var r ravendb.PingResult
+			r.Url = other.url
+			if other.down {
+				r.SetupAlive.Error = "connection refused"
+				r.TcpInfo.Error = "connection refused"
+			}
+			res = append(res, r)
+		}
+		return reply(req, http.StatusOK, map[string]any{"Result": res})
+
+	case route == "PUT /admin/cluster/node", route == "DELETE /admin/cluster/node":
+		if n.tag != s.leader {
+			return s.redirectToLeader(req)
+		}
+		if req.Method == http.MethodDelete {
+			other := s.node(q.Get("nodeTag"))
+			if other == nil {
+				return reply(req, http.StatusNotFound, map[string]string{"Message": "no such node"})
+			}
+			other.role = ""
+			return reply(req, http.StatusOK, nil)
+		}
+		other := s.node(q.Get("tag"))
+		if other == nil || other.url != strings.TrimRight(q.Get("url"), "/") {
+			return reply(req, http.StatusBadRequest, map[string]string{"Message": "unknown node " + q.Get("url")})
+		}
+		other.role = ravendbv1.NodeRoleMember
+		if q.Get("watcher") == "true" {
+			other.role = ravendbv1.NodeRoleWatcher
+		}
+		return reply(req, http.StatusOK, nil)
+
+	case route == "POST /admin/cluster/reelect":
+		if n.tag != s.leader {
+			return reply(req, http.StatusBadRequest, map[string]string{"Message": "not the leader"})
+		}
+		for _, other := range s.nodes {
+			if other.tag != n.tag && other.role == ravendbv1.NodeRoleMember && !other.down {
+				s.leader = other.tag
+				s.term++
+				break
+			}
+		}
+		return reply(req, http.StatusOK, nil)
+
+	case route == "GET /admin/databases":
+		rec, ok := s.records[q.Get("name")]
+		if !ok {
+			return reply(req, http.StatusNotFound, map[string]string{"Message": "database " + q.Get("name") + " not found"})
+		}
+		return reply(req, http.StatusOK, rec)
+
+	case route == "PUT /admin/databases", route == "PUT /admin/databases/node", route == "DELETE /admin/databases":
+		if n.tag != s.leader {
+			return s.redirectToLeader(req)
+		}
+		return s.changeDatabase(req)
+
+	case req.Method == http.MethodPost && strings.HasPrefix(req.URL.Path, "/databases/") && strings.HasSuffix(req.URL.Path, "/admin/configuration/settings"):
+		if n.tag != s.leader {
+			return s.redirectToLeader(req)
+		}
+		db := strings.TrimSuffix(strings.TrimPrefix(req.URL.Path, "/databases/"), "/admin/configuration/settings")
+		rec, ok := s.records[db]
+		if !ok {
+			return reply(req, http.StatusNotFound, map[string]string{"Message": "database " + db + " not found"})
+		}
+		var body struct{ Configuration map[string]string }
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			return reply(req, http.StatusBadRequest, map[string]string{"Message": err.Error()})
+		}
+		if rec.Settings == nil {
+			rec.Settings = map[string]string{}
+		}
+		for k, v := range body.Configuration {
+			rec.Settings[k] = v
+		}
+		return reply(req, http.StatusOK, nil)
+
+	case route == "GET /certificates":
+		var results []map[string]string
+		if name, ok := s.certs[q.Get("thumbprint")]; ok {
+			results = append(results, map[string]string{"Thumbprint": q.Get("thumbprint"), "Name": name})
+		}
+		return reply(req, http.StatusOK, map[string]any{"Results": results})
+
+	case route == "PUT /admin/certificates":
+		var body struct {
+			Name        string
+			Certificate string
+		}
+		raw, _ := io.ReadAll(req.Body)
+		der, err := func() ([]byte, error) {
+			if err := json.Unmarshal(raw, &body); err != nil {
+				return nil, err
+			}
+			return base64.StdEncoding.DecodeString(body.Certificate)
+		}()
+		if err != nil {
+			return reply(req, http.StatusBadRequest, map[string]string{"Message": err.Error()})
+		}
+		sum := sha1.Sum(der)
+		s.certs[strings.ToUpper(hex.EncodeToString(sum[:]))] = body.Name
+		return reply(req, http.StatusCreated, nil)
+	}
+
+	return reply(req, http.StatusNotFound, map[string]string{"Message": "no route for " + route})
+}
+
+// changeDatabase serves the database group changes, on the leader
+func (s *Server) changeDatabase(req *http.Request) *http.Response {
+	q := req.URL.Query()
+	notFound := func(name string) *http.Response {
+		return reply(req, http.StatusNotFound, map[string]string{"Message": "database " + name + " not found"})
+	}
+
+	switch req.Method + " " + req.URL.Path {
+	case "PUT /admin/databases":
+		var rec ravendb.DatabaseRecord
+		if err := json.NewDecoder(req.Body).Decode(&rec); err != nil {
+			return reply(req, http.StatusBadRequest, map[string]string{"Message": err.Error()})
+		}
+		if _, exists := s.records[rec.DatabaseName]; exists {
+			return reply(req, http.StatusConflict, map[string]string{"Message": "database " + rec.DatabaseName + " already exists"})
+		}
+		if rec.Topology == nil {
+			rf, _ := strconv.Atoi(q.Get("replicationFactor"))
+			rec.Topology = &ravendb.RecordTopology{ReplicationFactor: rf}
+			for _, n := range s.nodes {
+				if len(rec.Topology.Members) < rf && n.role == ravendbv1.NodeRoleMember {
+					rec.Topology.Members = append(rec.Topology.Members, n.tag)
+				}
+			}
+		}
+		s.records[rec.DatabaseName] = &rec
+		return reply(req, http.StatusCreated, nil)
+
+	case "PUT /admin/databases/node":
+		rec, ok := s.records[q.Get("name")]
+		if !ok {
+			return notFound(q.Get("name"))
+		}
+		rec.Topology.Promotables = append(rec.Topology.Promotables, strings.ToUpper(q.Get("node")))
+		return reply(req, http.StatusOK, nil)
+
+	default: // DELETE /admin/databases
+		var body struct {
+			DatabaseNames []string
+			FromNodes     []string
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil || len(body.DatabaseNames) != 1 {
+			return reply(req, http.StatusBadRequest, map[string]string{"Message": "expected one database name"})
+		}
+		name := body.DatabaseNames[0]
+		rec, ok := s.records[name]
+		if !ok {
+			return notFound(name)
+		}
+		if len(body.FromNodes) == 0 {
+			delete(s.records, name)
+			return reply(req, http.StatusOK, nil)
+		}
+		t := rec.Topology
+		t.Members, t.Promotables, t.Rehabs = without(t.Members, body.FromNodes), without(t.Promotables, body.FromNodes), without(t.Rehabs, body.FromNodes)
+		return reply(req, http.StatusOK, nil)
+	}
+}
+
+func without(tags, drop []string) []string {
+	var out []string
+	for _, t := range tags {
+		keep := true
+		for _, d := range drop {
+			keep = keep && !strings.EqualFold(t, d)
+		}
+		if keep {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// topology is what node n answers on /cluster/topology, a node outside the cluster only knows itself
+func (s *Server) topology(n *node) map[string]any {
+	members, promotables, watchers := map[string]string{}, map[string]string{}, map[string]string{}
+	leader := ""
+	if n.role != "" {
+		leader = s.leader
+		if n.staleLeader != "" {
+			leader = n.staleLeader
+		}
+		for _, other := range s.nodes {
+			switch other.role {
+			case ravendbv1.NodeRoleMember:
+				members[other.tag] = other.url
+			case ravendbv1.NodeRolePromotable:
+				promotables[other.tag] = other.url
+			case ravendbv1.NodeRoleWatcher:
+				watchers[other.tag] = other.url
+			}
+		}
+	}
+	return map[string]any{
+		"Leader":       leader,
+		"CurrentState": s.state(n),
+		"CurrentTerm":  s.term,
+		"NodeTag":      n.tag,
+		"Topology": map[string]any{
+			"Members":     members,
+			"Promotables": promotables,
+			"Watchers":    watchers,
+		},
+	}
+}
+
+func (s *Server) state(n *node) string {
+	switch {
+	case n.role == "":
+		return "Passive"
+	case n.tag == s.leader:
+		return "Leader"
+	}
+	return "Follower"
+}
+
+func (s *Server) redirectToLeader(req *http.Request) *http.Response {
+	leader := s.node(s.leader)
+	if leader == nil {
+		return reply(req, http.StatusServiceUnavailable, map[string]string{"Message": "no leader"})
+	}
+	resp := reply(req, http.StatusTemporaryRedirect, nil)
+	loc := leader.url + req.URL.Path
+	if req.URL.RawQuery != "" {
+		loc += "?" + req.URL.RawQuery
+	}
+	resp.Header.Set("Location", loc)
+	return resp
+}
+
+func (s *Server) node(tag string) *node {
+	for _, n := range s.nodes {
+		if strings.EqualFold(n.tag, tag) {
+			return n
+		}
+	}
+	return nil
+}
+
+func failureKey(tag, method, path string) string {
+	return strings.ToUpper(tag) + " " + method + " " + path
+}
+
+func productVersion(full string) string {
+	parts := strings.Split(full, ".")
+	if len(parts) < 2 {
+		return full
+	}
+	return parts[0] + "." + parts[1]
+}
+
+func reply(req *http.Request, code int, body any) *http.Response {
+	var raw []byte
+	if body != nil {
+		raw, _ = json.Marshal(body)
+	}
+	return &http.Response{
+		StatusCode: code,
+		Status:     http.StatusText(code),
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(raw)),
+		Request:    req,
+	}
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ravendb
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/pkcs12"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ravendbv1 "ravendb-operator/api/v1"
+)
+
+const (
+	clientPFXKey = "client.pfx"
+	serverPFXKey = "server.pfx"
+	clientPwdKey = "password"
+	caCRTKey     = "ca.crt"
+)
+
+const (
+	dialTimeout         = 10 * time.Second
+	tlsHandshakeTimeout = 10 * time.Second
+	idleConnTimeout     = 90 * time.Second
+)
+
+// transports are shared per cluster and certificate: every reconcile builds a client, they all reuse the
+// same connections instead of leaving an idle pool behind each time. a rotated certificate (or CA)
+// replaces the transport of its cluster.
+var transports = &transportCache{entries: map[string]cachedTransport{}}
+
+type transportCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedTransport
+}
+
+type cachedTransport struct {
+	fingerprint [sha256.Size]byte
+	tr          *http.Transport
+}
+
+func BuildHTTPSClientFromCluster(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster) (*http.Client, error) {
+	pair, err := loadPair(ctx, kc, c.Namespace, c.Spec.ClientCertSecretRef, clientPFXKey)
+	if err != nil {
+		return nil, err
+	}
+	return buildHTTPSClient(ctx, kc, c, "client", pair)
+}
+
+// BuildServerCertHTTPSClient authenticates with the server certificate of node tag instead of the client
+// certificate. RavenDB trusts its own server certificate as a cluster admin, which is how the client
+// certificate gets registered while the cluster doesn't know it yet.
+func BuildServerCertHTTPSClient(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster, tag string) (*http.Client, error) {
+	name, err := serverCertSecretName(c, tag)
+	if err != nil {
+		return nil, err
+	}
+	pair, err := loadPair(ctx, kc, c.Namespace, name, serverPFXKey)
+	if err != nil {
+		return nil, err
+	}
+	return buildHTTPSClient(ctx, kc, c, "server/"+strings.ToUpper(tag), pair)
+}
+
+// ClientCertificate returns the client certificate the operator authenticates with
+func ClientCertificate(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster) (*x509.Certificate, error) {
+	pair, err := loadPair(ctx, kc, c.Namespace, c.Spec.ClientCertSecretRef, clientPFXKey)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(pair.Certificate[0])
+}
+
+func buildHTTPSClient(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster, identity string, pair tls.Certificate) (*http.Client, error) {
+
+	needCA, err := needsCA(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var caPEM []byte
+	if needCA {
+		if caPEM, err = loadCAPEM(ctx, kc, c); err != nil {
+			return nil, err
+		}
+	}
+
+	tr, err := transports.get(c.Namespace+"/"+c.Name+"/"+identity, pair, caPEM)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: tr, Timeout: DefaultTimeout}, nil
+}
+
+// get returns the transport of key, built again when the certificate or the CA changed
+func (tc *transportCache) get(key string, pair tls.Certificate, caPEM []byte) (*http.Transport, error) {
+	h := sha256.New()
+	for _, der := range pair.Certificate {
+		h.Write(der)
+	}
+	h.Write(caPEM)
+	var fp [sha256.Size]byte
+	copy(fp[:], h.Sum(nil))
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if e, ok := tc.entries[key]; ok {
+		if e.fingerprint == fp {
+			return e.tr, nil
+		}
+		e.tr.CloseIdleConnections()
+	}
+
+	tlsCfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{pair},
+	}
+	if caPEM != nil {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("failed to parse %q of the CA secret", caCRTKey)
+		}
+		tlsCfg.RootCAs = pool
+	}
+
+	tr := &http.Transport{
+		DialContext:         (&net.Dialer{Timeout: dialTimeout, KeepAlive: 30 * time.Second}).DialContext,
+		TLSClientConfig:     tlsCfg,
+		TLSHandshakeTimeout: tlsHandshakeTimeout,
+		IdleConnTimeout:     idleConnTimeout,
+	}
+	tc.entries[key] = cachedTransport{fingerprint: fp, tr: tr}
+	return tr, nil
+}
+
+// forget closes the idle connections of every transport of the cluster and drops them
+func (tc *transportCache) forget(namespace, name string) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	prefix := namespace + "/" + name + "/"
+	for key, e := range tc.entries {
+		if strings.HasPrefix(key, prefix) {
+			e.tr.CloseIdleConnections()
+			delete(tc.entries, key)
+		}
+	}
+}
+
+// ForgetCluster releases the connections kept for a cluster, once it was deleted
+func ForgetCluster(namespace, name string) {
+	transports.forget(namespace, name)
+}
+
+// serverCertSecretName is the secret with the server.pfx node tag runs with
+func serverCertSecretName(c *ravendbv1.RavenDBCluster, tag string) (string, error) {
+	switch c.Spec.Mode {
+	case ravendbv1.ModeLetsEncrypt:
+		for _, n := range c.Spec.Nodes {
+			if strings.EqualFold(n.Tag, tag) && n.CertSecretRef != nil {
+				return *n.CertSecretRef, nil
+			}
+		}
+		return "", fmt.Errorf("node %s has no certSecretRef", tag)
+	case ravendbv1.ModeNone:
+		if c.Spec.ClusterCertSecretRef == nil {
+			return "", fmt.Errorf("clusterCertSecretRef is not set")
+		}
+		return *c.Spec.ClusterCertSecretRef, nil
+	default:
+		return "", fmt.Errorf("unsupported mode: %q", c.Spec.Mode)
+	}
+}
+
+func needsCA(c *ravendbv1.RavenDBCluster) (bool, error) {
+	switch c.Spec.Mode {
+	case ravendbv1.ModeLetsEncrypt:
+		return false, nil
+	case ravendbv1.ModeNone: // self-signed
+		return true, nil
+	default:
+		return false, fmt.Errorf("unsupported mode: %q", c.Spec.Mode)
+	}
+}
+
+func pfxToTLSCert(pfx []byte, password string) (tls.Certificate, error) {
+
+	blocks, err := pkcs12.ToPEM(pfx, password)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("decode pfx: %w", err)
+	}
+	var certPEM, keyPEM []byte
+	for _, b := range blocks {
+		if strings.Contains(b.Type, "PRIVATE KEY") {
+			keyPEM = append(keyPEM, pem.EncodeToMemory(b)...)
+		} else {
+			certPEM = append(certPEM, pem.EncodeToMemory(b)...)
+		}
+	}
+	return tls.X509KeyPair(certPEM, keyPEM)
+}
+
+func loadPair(ctx context.Context, kc client.Client, namespace, name, key string) (tls.Certificate, error) {
+	var secret corev1.Secret
+	if err := kc.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &secret); err != nil {
+		return tls.Certificate{}, fmt.Errorf("get cert secret %q: %w", name, err)
+	}
+
+	pfx, ok := secret.Data[key]
+	if !ok || len(pfx) == 0 {
+		return tls.Certificate{}, fmt.Errorf("secret %q missing %q", name, key)
+	}
+	pass := string(secret.Data[clientPwdKey]) // allow empty password
+	return pfxToTLSCert(pfx, pass)
+}
+
+func loadCAPEM(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster) ([]byte, error) {
+	if c.Spec.CACertSecretRef == nil {
+		return nil, fmt.Errorf("caCertSecretRef is not set")
+	}
+	caName := strings.TrimSpace(*c.Spec.CACertSecretRef)
+
+	var caSecret corev1.Secret
+	if err := kc.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: caName}, &caSecret); err != nil {
+		return nil, fmt.Errorf("get CA secret %q: %w", caName, err)
+	}
+
+	caPEM, ok := caSecret.Data[caCRTKey]
+	if !ok || len(caPEM) == 0 {
+		return nil, fmt.Errorf("CA secret %q missing %q", caName, caCRTKey)
+	}
+	return caPEM, nil
+}
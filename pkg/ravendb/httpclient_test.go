@@ -0,0 +1,85 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package ravendb
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testPair(t *testing.T) (tls.Certificate, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "ravendb-operator"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func Test_HC1_TransportIsSharedPerClusterAndCertificate(t *testing.T) {
+	tc := &transportCache{entries: map[string]cachedTransport{}}
+	pair, ca := testPair(t)
+
+	tr, err := tc.get("ravendb/db/client", pair, ca)
+	require.NoError(t, err)
+	require.Equal(t, tlsHandshakeTimeout, tr.TLSHandshakeTimeout)
+	require.Equal(t, idleConnTimeout, tr.IdleConnTimeout)
+	require.NotNil(t, tr.TLSClientConfig.RootCAs)
+
+	again, err := tc.get("ravendb/db/client", pair, ca)
+	require.NoError(t, err)
+	require.Same(t, tr, again)
+
+	// a rotated certificate gets a new transport
+	rotated, _ := testPair(t)
+	replaced, err := tc.get("ravendb/db/client", rotated, ca)
+	require.NoError(t, err)
+	require.NotSame(t, tr, replaced)
+
+	other, err := tc.get("ravendb/db/server/A", pair, nil)
+	require.NoError(t, err)
+	require.Nil(t, other.TLSClientConfig.RootCAs)
+	require.Len(t, tc.entries, 2)
+
+	_, err = tc.get("ravendb/db2/client", pair, []byte("not a pem"))
+	require.Error(t, err)
+
+	tc.forget("ravendb", "db")
+	require.Empty(t, tc.entries)
+}
+
+func Test_HC2_CallerClientGetsATimeout(t *testing.T) {
+	require.Equal(t, DefaultTimeout, New(nil, nil).http.Timeout)
+	require.Equal(t, DefaultTimeout, New(&http.Client{}, nil).http.Timeout)
+	require.Equal(t, 5*time.Second, New(&http.Client{Timeout: 5 * time.Second}, nil).http.Timeout)
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ravendb
+
+import (
+	"context"
+	"net/http"
+)
+
+// License is the subset of GET /license/status we care about.
+// Expiration is kept as RavenDB formats it (no time zone), empty for licenses that don't expire.
+type License struct {
+	Id         string
+	LicensedTo string
+	Type       string
+	Status     string
+	Expired    bool
+	Expiration string
+}
+
+func (c *Client) License(ctx context.Context) (*License, error) {
+	var l License
+	if err := c.onAnyNode(ctx, request{method: http.MethodGet, path: "/license/status"}, &l); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
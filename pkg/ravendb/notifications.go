@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ravendb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// NotificationAlertRaised is the Type of the notifications that are operational alerts
+const NotificationAlertRaised = "AlertRaised"
+
+const notificationCenterPath = "/server/notification-center/watch"
+
+// Notification is the subset of a notification center message we care about.
+// alerts carry an AlertType (e.g. LowDiskSpace, OutOfMemoryException, LicenseManager_LicenseLimit)
+// and a Severity (None, Info, Success, Warning, Error). CreatedAt is kept as RavenDB formats it.
+type Notification struct {
+	Id        string
+	Type      string
+	CreatedAt string
+	Title     string
+	Message   string
+	Severity  string
+	AlertType string
+	Database  string
+}
+
+// WatchNotifications subscribes to the server notification center of node tag and calls fn for every
+// notification until ctx is done or the connection drops. right after connecting the node sends the
+// notifications that are still active, so a new subscription sees alerts that were raised before it.
+func (c *Client) WatchNotifications(ctx context.Context, tag string, fn func(Notification)) error {
+	base, err := c.nodeURL(tag)
+	if err != nil {
+		return err
+	}
+	u, err := url.Parse(base + notificationCenterPath)
+	if err != nil {
+		return err
+	}
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	case "http":
+		u.Scheme = "ws"
+	}
+
+	dialer := websocket.Dialer{Proxy: http.ProxyFromEnvironment, HandshakeTimeout: 30 * time.Second}
+	if t, ok := c.http.Transport.(*http.Transport); ok {
+		dialer.TLSClientConfig = t.TLSClientConfig
+	}
+
+	conn, resp, err := dialer.DialContext(ctx, u.String(), nil)
+	if err != nil {
+		if resp != nil {
+			return &StatusError{Method: http.MethodGet, Path: notificationCenterPath, Code: resp.StatusCode}
+		}
+		return fmt.Errorf("watch notifications of node %s: %w", tag, err)
+	}
+	defer conn.Close()
+
+	// ReadMessage doesn't take a context, closing the connection unblocks it
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("watch notifications of node %s: %w", tag, err)
+		}
+
+		var n Notification
+		if err := json.Unmarshal(data, &n); err != nil || n.Type == "" {
+			continue
+		}
+		fn(n)
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package resource
 
 import (
+	ravendbv1 "ravendb-operator/api/v1"
 	"ravendb-operator/pkg/common"
 
 	corev1 "k8s.io/api/core/v1"
@@ -41,42 +42,48 @@ func BuildRavenDBContainer(image string, env []corev1.EnvVar, ports []corev1.Con
 	}
 }
 
-func BuildClusterBootstrapperContainer(image string, vMounts []corev1.VolumeMount, env []corev1.EnvVar) corev1.Container {
-	return corev1.Container{
-		Name:    "ravendb-cluster-initializer",
-		Image:   image,
-		Command: []string{"/bin/sh", "-c"},
-		Args: []string{`
-			echo ">> Starting RavenDB cluster bootstrapper..."
-			/ravendb/scripts/check-nodes-discoverability.sh &&
-			/ravendb/scripts/init-cluster.sh
-		`},
-		VolumeMounts: vMounts,
-		Env:          env,
+// BuildSidecarContainers builds spec.sidecars or spec.initContainers. mounts of the volumes the operator manages
+// are made read only, a sidecar must not be able to change RavenDB's data or certificates.
+func BuildSidecarContainers(sidecars []ravendbv1.Sidecar) []corev1.Container {
+	var containers []corev1.Container
+
+	for _, s := range sidecars {
+		mounts := make([]corev1.VolumeMount, 0, len(s.VolumeMounts))
+		for _, m := range s.VolumeMounts {
+			if isManagedVolume(m.Name) {
+				m.ReadOnly = true
+			}
+			mounts = append(mounts, m)
+		}
+
+		container := corev1.Container{
+			Name:            s.Name,
+			Image:           s.Image,
+			ImagePullPolicy: s.ImagePullPolicy,
+			Command:         s.Command,
+			Args:            s.Args,
+			Env:             s.Env,
+			Ports:           s.Ports,
+			Resources:       getResourcesOrEmpty(s.Resources),
+			VolumeMounts:    mounts,
+		}
+		containers = append(containers, container)
 	}
+	return containers
 }
 
-// TODO: might use sidecars later
-// func BuildSidecarContainers(sidecars []ravendbv1.Sidecar, additionalMounts []corev1.VolumeMount) []corev1.Container {
-// 	var containers []corev1.Container
-
-// 	for _, s := range sidecars {
-// 		container := corev1.Container{
-// 			Name:      s.Name,
-// 			Image:     s.Image,
-// 			Command:   s.Command,
-// 			Env:       s.Env,
-// 			Ports:     s.Ports,
-// 			Resources: getResourcesOrEmpty(s.Resources),
-// 		}
-// 		containers = append(containers, container)
-// 	}
-// 	return containers
-// }
+func isManagedVolume(name string) bool {
+	switch name {
+	case common.DataVolumeName, common.LogsVolumeName, common.AuditVolumeName, common.CertVolumeName,
+		common.LicenseVolumeName, common.CertHookVolumeName:
+		return true
+	}
+	return false
+}
 
-// func getResourcesOrEmpty(res *corev1.ResourceRequirements) corev1.ResourceRequirements {
-// 	if res == nil {
-// 		return corev1.ResourceRequirements{}
-// 	}
-// 	return *res
-// }
+func getResourcesOrEmpty(res *corev1.ResourceRequirements) corev1.ResourceRequirements {
+	if res == nil {
+		return corev1.ResourceRequirements{}
+	}
+	return *res
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resource
+
+import (
+	"context"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/naming"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type GoverningServiceBuilder struct{}
+
+func NewGoverningServiceBuilder() PerClusterBuilder {
+	return &GoverningServiceBuilder{}
+}
+
+func (b *GoverningServiceBuilder) Build(ctx context.Context, cluster *ravendbv1.RavenDBCluster) (client.Object, error) {
+	return BuildGoverningService(cluster)
+}
+
+// BuildGoverningService builds the headless Service named in the StatefulSets' serviceName. it gives every
+// node pod the DNS name "<pod>.<service>.<namespace>.svc.<cluster domain>" (see common.PodFQDN).
+// not ready pods are published as well, a node restarting has to reach the others before it's Ready.
+func BuildGoverningService(cluster *ravendbv1.RavenDBCluster) (*corev1.Service, error) {
+	svc := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      naming.For(cluster).GoverningService(),
+			Namespace: cluster.Namespace,
+			Labels: map[string]string{
+				common.LabelAppName:   common.App,
+				common.LabelManagedBy: common.Manager,
+				common.LabelInstance:  cluster.Name,
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP:                corev1.ClusterIPNone,
+			PublishNotReadyAddresses: true,
+			Selector: map[string]string{
+				common.LabelAppName:  common.App,
+				common.LabelInstance: cluster.Name,
+			},
+			Ports: buildServicePorts(),
+		},
+	}
+
+	return svc, nil
+}
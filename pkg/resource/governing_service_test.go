@@ -0,0 +1,54 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/naming"
+)
+
+func Test_GS1_HeadlessServiceSelectsAllNodePods(t *testing.T) {
+	c := pdbCluster(3, 0)
+	svc, err := BuildGoverningService(c)
+	require.NoError(t, err)
+
+	require.Equal(t, naming.For(c).GoverningService(), svc.Name)
+	require.Equal(t, corev1.ClusterIPNone, svc.Spec.ClusterIP)
+	require.True(t, svc.Spec.PublishNotReadyAddresses)
+	require.Equal(t, map[string]string{
+		common.LabelAppName:  common.App,
+		common.LabelInstance: "db",
+	}, svc.Spec.Selector)
+	require.Len(t, svc.Spec.Ports, 2)
+}
+
+func Test_GS2_NodesUsePerPodDNS(t *testing.T) {
+	c := pdbCluster(3, 0)
+	names := naming.For(c)
+
+	var tcp string
+	for _, e := range common.BuildCommonEnvVars(c, c.Spec.Nodes[1]) {
+		if e.Name == "RAVEN_PublicServerUrl_Tcp_Cluster" {
+			tcp = e.Value
+		}
+	}
+	require.Equal(t, "tcp://"+names.Pod("B")+"."+names.GoverningService()+".ravendb.svc.cluster.local:38888", tcp)
+}
@@ -22,6 +22,7 @@ import (
 
 	ravendbv1 "ravendb-operator/api/v1"
 	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/naming"
 
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -40,7 +41,7 @@ func (b *IngressBuilder) Build(ctx context.Context, cluster *ravendbv1.RavenDBCl
 }
 
 func BuildIngress(cluster *ravendbv1.RavenDBCluster) (*networkingv1.Ingress, error) {
-	ingressName := common.App
+	ingressName := naming.For(cluster).Ingress()
 
 	labels := buildIngressLabels(cluster)
 	annotations := buildIngressAnnotations(cluster)
@@ -102,18 +103,20 @@ func buildIngressAnnotations(cluster *ravendbv1.RavenDBCluster) map[string]strin
 
 func buildIngressRules(cluster *ravendbv1.RavenDBCluster) []networkingv1.IngressRule {
 	var rules []networkingv1.IngressRule
+	names := naming.For(cluster)
 
 	for _, node := range cluster.Spec.Nodes {
+		svcName := names.Node(node.Tag)
 		rules = append(rules,
-			buildHTTPSRule(node.Tag, cluster.Spec.Domain),
-			buildTCPRule(node.Tag, cluster.Spec.Domain),
+			buildHTTPSRule(node.Tag, cluster.Spec.Domain, svcName),
+			buildTCPRule(node.Tag, cluster.Spec.Domain, svcName),
 		)
 	}
 
 	return rules
 }
 
-func buildHTTPSRule(nodeName, domain string) networkingv1.IngressRule {
+func buildHTTPSRule(nodeName, domain, svcName string) networkingv1.IngressRule {
 	return networkingv1.IngressRule{
 		Host: fmt.Sprintf("%s.%s", nodeName, domain),
 		IngressRuleValue: networkingv1.IngressRuleValue{
@@ -124,7 +127,7 @@ func buildHTTPSRule(nodeName, domain string) networkingv1.IngressRule {
 						PathType: pathTypePtr(networkingv1.PathTypePrefix),
 						Backend: networkingv1.IngressBackend{
 							Service: &networkingv1.IngressServiceBackend{
-								Name: fmt.Sprintf("%s%s", common.Prefix, nodeName),
+								Name: svcName,
 								Port: networkingv1.ServiceBackendPort{Number: common.InternalHttpsPort},
 							},
 						},
@@ -135,7 +138,7 @@ func buildHTTPSRule(nodeName, domain string) networkingv1.IngressRule {
 	}
 }
 
-func buildTCPRule(nodeName, domain string) networkingv1.IngressRule {
+func buildTCPRule(nodeName, domain, svcName string) networkingv1.IngressRule {
 	return networkingv1.IngressRule{
 		Host: fmt.Sprintf("%s-tcp.%s", nodeName, domain),
 		IngressRuleValue: networkingv1.IngressRuleValue{
@@ -146,7 +149,7 @@ func buildTCPRule(nodeName, domain string) networkingv1.IngressRule {
 						PathType: pathTypePtr(networkingv1.PathTypePrefix),
 						Backend: networkingv1.IngressBackend{
 							Service: &networkingv1.IngressServiceBackend{
-								Name: fmt.Sprintf("%s%s", common.Prefix, nodeName),
+								Name: svcName,
 								Port: networkingv1.ServiceBackendPort{Number: common.InternalTcpPort},
 							},
 						},
@@ -0,0 +1,175 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resource
+
+import (
+	"context"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/naming"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type NetworkPolicyBuilder struct{}
+
+func NewNetworkPolicyBuilder() PerClusterBuilder {
+	return &NetworkPolicyBuilder{}
+}
+
+func (b *NetworkPolicyBuilder) Build(ctx context.Context, cluster *ravendbv1.RavenDBCluster) (client.Object, error) {
+	return BuildNetworkPolicy(cluster)
+}
+
+func BuildNetworkPolicy(cluster *ravendbv1.RavenDBCluster) (*networkingv1.NetworkPolicy, error) {
+	spec := cluster.Spec.NetworkPolicy
+	if spec == nil {
+		spec = &ravendbv1.NetworkPolicySpec{}
+	}
+
+	nodePods := map[string]string{
+		common.LabelAppName:  common.App,
+		common.LabelInstance: cluster.Name,
+	}
+
+	rules := []networkingv1.NetworkPolicyIngressRule{
+		// the nodes among themselves, raft and replication use both ports
+		{
+			From:  []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{MatchLabels: nodePods}}},
+			Ports: nodePorts(common.InternalHttpsPort, common.InternalTcpPort),
+		},
+		// the operator only talks HTTPS
+		{
+			From: []networkingv1.NetworkPolicyPeer{{
+				NamespaceSelector: namespaceSelector(stringOr(spec.OperatorNamespace, common.OperatorNamespace)),
+				PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{common.LabelControlPlane: common.ControlPlaneController}},
+			}},
+			Ports: nodePorts(common.InternalHttpsPort),
+		},
+	}
+
+	if rule := externalAccessRule(cluster); rule != nil {
+		rules = append(rules, *rule)
+	}
+
+	// sidecars (e.g. metrics exporters) serve the ports they declare to the pods of the cluster
+	if ports := sidecarPorts(cluster); len(ports) > 0 {
+		rules = append(rules, networkingv1.NetworkPolicyIngressRule{
+			From:  []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{}}},
+			Ports: ports,
+		})
+	}
+
+	if len(spec.Clients) > 0 {
+		rules = append(rules, networkingv1.NetworkPolicyIngressRule{
+			From:  spec.Clients,
+			Ports: nodePorts(common.InternalHttpsPort, common.InternalTcpPort),
+		})
+	}
+
+	np := &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "networking.k8s.io/v1",
+			Kind:       "NetworkPolicy",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      naming.For(cluster).NetworkPolicy(),
+			Namespace: cluster.Namespace,
+			Labels: map[string]string{
+				common.LabelAppName:   common.App,
+				common.LabelManagedBy: common.Manager,
+				common.LabelInstance:  cluster.Name,
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: nodePods},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress:     rules,
+		},
+	}
+
+	return np, nil
+}
+
+// an ingress controller forwards from its own pods. a cloud load balancer keeps the client's address,
+// so the nodes have to accept both ports from anywhere. the webhook requires spec.networkPolicy.ingressControllerNamespace
+// for ingress classes without a default namespace.
+func externalAccessRule(cluster *ravendbv1.RavenDBCluster) *networkingv1.NetworkPolicyIngressRule {
+	ea := cluster.Spec.ExternalAccessConfiguration
+	if ea == nil {
+		return nil
+	}
+
+	ports := nodePorts(common.InternalHttpsPort, common.InternalTcpPort)
+
+	switch ea.Type {
+	case ravendbv1.ExternalAccessTypeIngressController:
+		ns := cluster.EffectiveIngressControllerNamespace()
+		if ns == "" {
+			return nil
+		}
+		return &networkingv1.NetworkPolicyIngressRule{
+			From:  []networkingv1.NetworkPolicyPeer{{NamespaceSelector: namespaceSelector(ns)}},
+			Ports: ports,
+		}
+
+	case ravendbv1.ExternalAccessTypeAWS, ravendbv1.ExternalAccessTypeAzure:
+		return &networkingv1.NetworkPolicyIngressRule{Ports: ports}
+	}
+
+	return nil
+}
+
+func nodePorts(ports ...int) []networkingv1.NetworkPolicyPort {
+	out := make([]networkingv1.NetworkPolicyPort, 0, len(ports))
+	for _, p := range ports {
+		protocol := corev1.ProtocolTCP
+		port := intstr.FromInt(p)
+		out = append(out, networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &port})
+	}
+	return out
+}
+
+func sidecarPorts(cluster *ravendbv1.RavenDBCluster) []networkingv1.NetworkPolicyPort {
+	var out []networkingv1.NetworkPolicyPort
+	for _, sc := range cluster.Spec.Sidecars {
+		for _, p := range sc.Ports {
+			protocol := p.Protocol
+			if protocol == "" {
+				protocol = corev1.ProtocolTCP
+			}
+			port := intstr.FromInt32(p.ContainerPort)
+			out = append(out, networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &port})
+		}
+	}
+	return out
+}
+
+func namespaceSelector(ns string) *metav1.LabelSelector {
+	return &metav1.LabelSelector{MatchLabels: map[string]string{common.NamespaceNameLabel: ns}}
+}
+
+func stringOr(s *string, def string) string {
+	if s == nil || *s == "" {
+		return def
+	}
+	return *s
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ravendbv1 "ravendb-operator/api/v1"
+)
+
+func npCluster(ea *ravendbv1.ExternalAccessConfiguration) *ravendbv1.RavenDBCluster {
+	c := pdbCluster(3, 0)
+	c.Spec.ExternalAccessConfiguration = ea
+	c.Spec.NetworkPolicy = &ravendbv1.NetworkPolicySpec{Enabled: true}
+	return c
+}
+
+func ruleNamespaces(rule networkingv1.NetworkPolicyIngressRule) []string {
+	var out []string
+	for _, p := range rule.From {
+		if p.NamespaceSelector != nil {
+			out = append(out, p.NamespaceSelector.MatchLabels["kubernetes.io/metadata.name"])
+		}
+	}
+	return out
+}
+
+func Test_NP1_NodesAndOperator(t *testing.T) {
+	np, err := BuildNetworkPolicy(npCluster(nil))
+	require.NoError(t, err)
+
+	require.Equal(t, "db", np.Name)
+	require.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, np.Spec.PolicyTypes)
+	require.Len(t, np.Spec.Ingress, 2)
+
+	nodes := np.Spec.Ingress[0]
+	require.Equal(t, np.Spec.PodSelector.MatchLabels, nodes.From[0].PodSelector.MatchLabels)
+	require.Equal(t, 443, nodes.Ports[0].Port.IntValue())
+	require.Equal(t, 38888, nodes.Ports[1].Port.IntValue())
+
+	operator := np.Spec.Ingress[1]
+	require.Equal(t, []string{"ravendb-operator-system"}, ruleNamespaces(operator))
+	require.Equal(t, "controller-manager", operator.From[0].PodSelector.MatchLabels["control-plane"])
+	require.Len(t, operator.Ports, 1)
+}
+
+func Test_NP2_ExternalAccessAndClients(t *testing.T) {
+	ingress := npCluster(&ravendbv1.ExternalAccessConfiguration{
+		Type:                            ravendbv1.ExternalAccessTypeIngressController,
+		IngressControllerExternalAccess: &ravendbv1.IngressControllerContext{IngressClassName: "nginx"},
+	})
+	np, err := BuildNetworkPolicy(ingress)
+	require.NoError(t, err)
+	require.Len(t, np.Spec.Ingress, 3)
+	require.Equal(t, []string{"ingress-nginx"}, ruleNamespaces(np.Spec.Ingress[2]))
+
+	custom := "edge"
+	ingress.Spec.NetworkPolicy.IngressControllerNamespace = &custom
+	np, err = BuildNetworkPolicy(ingress)
+	require.NoError(t, err)
+	require.Equal(t, []string{"edge"}, ruleNamespaces(np.Spec.Ingress[2]))
+
+	lb := npCluster(&ravendbv1.ExternalAccessConfiguration{Type: ravendbv1.ExternalAccessTypeAWS})
+	lb.Spec.NetworkPolicy.Clients = []networkingv1.NetworkPolicyPeer{
+		{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "app"}}},
+	}
+	np, err = BuildNetworkPolicy(lb)
+	require.NoError(t, err)
+	require.Len(t, np.Spec.Ingress, 4)
+	require.Empty(t, np.Spec.Ingress[2].From, "a load balancer keeps the client address")
+	require.Equal(t, lb.Spec.NetworkPolicy.Clients, np.Spec.Ingress[3].From)
+}
+
+func Test_NP3_SidecarPorts(t *testing.T) {
+	c := npCluster(nil)
+	c.Spec.Sidecars = []ravendbv1.Sidecar{
+		{Name: "exporter", Image: "x:1", Ports: []corev1.ContainerPort{{Name: "metrics", ContainerPort: 9100}}},
+		{Name: "shipper", Image: "x:1"},
+		{Name: "syslog", Image: "x:1", Ports: []corev1.ContainerPort{{ContainerPort: 514, Protocol: corev1.ProtocolUDP}}},
+	}
+	c.Spec.InitContainers = []ravendbv1.Sidecar{{Name: "init", Image: "x:1", Ports: []corev1.ContainerPort{{ContainerPort: 8080}}}}
+
+	np, err := BuildNetworkPolicy(c)
+	require.NoError(t, err)
+	require.Len(t, np.Spec.Ingress, 3)
+
+	sidecars := np.Spec.Ingress[2]
+	require.Equal(t, []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{}}}, sidecars.From, "any pod of the cluster")
+	require.Len(t, sidecars.Ports, 2)
+	require.Equal(t, 9100, sidecars.Ports[0].Port.IntValue())
+	require.Equal(t, corev1.ProtocolTCP, *sidecars.Ports[0].Protocol)
+	require.Equal(t, 514, sidecars.Ports[1].Port.IntValue())
+	require.Equal(t, corev1.ProtocolUDP, *sidecars.Ports[1].Protocol)
+}
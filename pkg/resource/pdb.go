@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/naming"
+
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type PodDisruptionBudgetBuilder struct{}
+
+func NewPodDisruptionBudgetBuilder() PerClusterBuilder {
+	return &PodDisruptionBudgetBuilder{}
+}
+
+func (b *PodDisruptionBudgetBuilder) Build(ctx context.Context, cluster *ravendbv1.RavenDBCluster) (client.Object, error) {
+	return BuildPodDisruptionBudget(cluster)
+}
+
+func BuildPodDisruptionBudget(cluster *ravendbv1.RavenDBCluster) (*policyv1.PodDisruptionBudget, error) {
+	labels := map[string]string{
+		common.LabelAppName:   common.App,
+		common.LabelManagedBy: common.Manager,
+		common.LabelInstance:  cluster.Name,
+	}
+
+	pdb := &policyv1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "policy/v1",
+			Kind:       "PodDisruptionBudget",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      naming.For(cluster).PodDisruptionBudget(),
+			Namespace: cluster.Namespace,
+			Labels:    labels,
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{
+				common.LabelAppName:  common.App,
+				common.LabelInstance: cluster.Name,
+			}},
+		},
+	}
+
+	spec := cluster.Spec.DisruptionBudget
+	switch {
+	case spec != nil && spec.MinAvailable != nil:
+		pdb.Spec.MinAvailable = spec.MinAvailable
+	case spec != nil && spec.MaxUnavailable != nil:
+		pdb.Spec.MaxUnavailable = spec.MaxUnavailable
+	default:
+		maxUnavailable := intstr.FromInt32(QuorumSafeDisruptions(cluster))
+		pdb.Spec.MaxUnavailable = &maxUnavailable
+	}
+
+	return pdb, nil
+}
+
+// QuorumSafeDisruptions is how many node pods can be down while a majority of the members stays up.
+// watchers don't vote, but a watcher's pod counts against the budget like any other.
+func QuorumSafeDisruptions(cluster *ravendbv1.RavenDBCluster) int32 {
+	members := int32(0)
+	for _, n := range cluster.Spec.Nodes {
+		if !n.Watcher {
+			members++
+		}
+	}
+	if members == 0 {
+		return 0
+	}
+	return (members - 1) / 2
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	ravendbv1 "ravendb-operator/api/v1"
+)
+
+func pdbCluster(members, watchers int) *ravendbv1.RavenDBCluster {
+	c := &ravendbv1.RavenDBCluster{}
+	c.Name = "db"
+	c.Namespace = "ravendb"
+	for i := 0; i < members+watchers; i++ {
+		c.Spec.Nodes = append(c.Spec.Nodes, ravendbv1.RavenDBNode{Tag: string(rune('A' + i)), Watcher: i >= members})
+	}
+	return c
+}
+
+func Test_PDB1_DefaultKeepsTheQuorum(t *testing.T) {
+	cases := []struct {
+		members, watchers int
+		maxUnavailable    int
+	}{
+		{1, 0, 0},
+		{2, 0, 0},
+		{3, 0, 1},
+		{4, 0, 1},
+		{5, 0, 2},
+		{3, 2, 1},
+	}
+	for _, tc := range cases {
+		pdb, err := BuildPodDisruptionBudget(pdbCluster(tc.members, tc.watchers))
+		require.NoError(t, err)
+		require.Nil(t, pdb.Spec.MinAvailable)
+		require.Equal(t, intstr.FromInt(tc.maxUnavailable), *pdb.Spec.MaxUnavailable, "%d members, %d watchers", tc.members, tc.watchers)
+	}
+}
+
+func Test_PDB2_SpecOverridesTheDefault(t *testing.T) {
+	c := pdbCluster(3, 0)
+	minAvailable := intstr.FromString("50%")
+	c.Spec.DisruptionBudget = &ravendbv1.DisruptionBudgetSpec{MinAvailable: &minAvailable}
+
+	pdb, err := BuildPodDisruptionBudget(c)
+	require.NoError(t, err)
+	require.Equal(t, "db", pdb.Name)
+	require.Equal(t, &minAvailable, pdb.Spec.MinAvailable)
+	require.Nil(t, pdb.Spec.MaxUnavailable)
+	require.Equal(t, map[string]string{"app.kubernetes.io/name": "ravendb", "app.kubernetes.io/instance": "db"}, pdb.Spec.Selector.MatchLabels)
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	ravendbv1 "ravendb-operator/api/v1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// EffectivePodTemplate merges node.podTemplate over spec.podTemplate, see ravendbv1.PodTemplateSpec
+func EffectivePodTemplate(cluster *ravendbv1.RavenDBCluster, node ravendbv1.RavenDBNode) ravendbv1.PodTemplateSpec {
+	var out ravendbv1.PodTemplateSpec
+	for _, pt := range []*ravendbv1.PodTemplateSpec{cluster.Spec.PodTemplate, node.PodTemplate} {
+		if pt == nil {
+			continue
+		}
+		out.Resources = mergeResources(out.Resources, pt.Resources)
+		out.NodeSelector = mergeStringMaps(out.NodeSelector, pt.NodeSelector)
+		out.Tolerations = append(out.Tolerations, pt.Tolerations...)
+		if len(pt.TopologySpreadConstraints) > 0 {
+			out.TopologySpreadConstraints = append([]corev1.TopologySpreadConstraint(nil), pt.TopologySpreadConstraints...)
+		}
+		if pt.PriorityClassName != "" {
+			out.PriorityClassName = pt.PriorityClassName
+		}
+		out.Labels = mergeStringMaps(out.Labels, pt.Labels)
+		out.Annotations = mergeStringMaps(out.Annotations, pt.Annotations)
+	}
+	return out
+}
+
+// applyPodTemplate sets the effective pod template of the node on the StatefulSet's pod template.
+// the operator's own labels win over the user's, the selector depends on them.
+func applyPodTemplate(sts *appsv1.StatefulSet, cluster *ravendbv1.RavenDBCluster, node ravendbv1.RavenDBNode) {
+	pt := EffectivePodTemplate(cluster, node)
+	tpl := &sts.Spec.Template
+
+	tpl.Labels = mergeStringMaps(pt.Labels, tpl.Labels)
+	tpl.Annotations = mergeStringMaps(tpl.Annotations, pt.Annotations)
+
+	tpl.Spec.NodeSelector = pt.NodeSelector
+	tpl.Spec.Tolerations = pt.Tolerations
+	tpl.Spec.TopologySpreadConstraints = pt.TopologySpreadConstraints
+	tpl.Spec.PriorityClassName = pt.PriorityClassName
+
+	if pt.Resources != nil && len(tpl.Spec.Containers) > 0 {
+		tpl.Spec.Containers[0].Resources = *pt.Resources
+	}
+}
+
+// requests and limits are merged per resource name, claims are replaced
+func mergeResources(base, override *corev1.ResourceRequirements) *corev1.ResourceRequirements {
+	if override == nil {
+		return base
+	}
+	out := &corev1.ResourceRequirements{}
+	if base != nil {
+		out = base.DeepCopy()
+	}
+	out.Requests = mergeResourceLists(out.Requests, override.Requests)
+	out.Limits = mergeResourceLists(out.Limits, override.Limits)
+	if len(override.Claims) > 0 {
+		out.Claims = append([]corev1.ResourceClaim(nil), override.Claims...)
+	}
+	return out
+}
+
+func mergeResourceLists(base, override corev1.ResourceList) corev1.ResourceList {
+	if len(override) == 0 {
+		return base
+	}
+	out := corev1.ResourceList{}
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range override {
+		out[k] = v
+	}
+	return out
+}
+
+func mergeStringMaps(base, override map[string]string) map[string]string {
+	if len(base) == 0 && len(override) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(base)+len(override))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range override {
+		out[k] = v
+	}
+	return out
+}
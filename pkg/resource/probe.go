@@ -0,0 +1,95 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// /setup/alive answers without a client certificate once the server is up and loaded its certificate.
+// the kubelet doesn't verify the server certificate of HTTPS probes.
+const setupAlivePath = "/setup/alive"
+
+// the startup probe gives a node 10 minutes (60 x 10s) to come up, a node with many or large databases
+// may take that long after a restart. liveness and readiness only start once it passed.
+func defaultStartupProbe() *corev1.Probe {
+	return setupAliveProbe(10, 5, 60)
+}
+
+func defaultReadinessProbe() *corev1.Probe {
+	return setupAliveProbe(10, 5, 3)
+}
+
+// a liveness failure restarts RavenDB, so it takes a full minute of failures
+func defaultLivenessProbe() *corev1.Probe {
+	return setupAliveProbe(20, 5, 3)
+}
+
+func setupAliveProbe(period, timeout, failures int32) *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path:   setupAlivePath,
+				Port:   intstr.FromString(common.HttpsPortName),
+				Scheme: corev1.URISchemeHTTPS,
+			},
+		},
+		PeriodSeconds:    period,
+		TimeoutSeconds:   timeout,
+		FailureThreshold: failures,
+		SuccessThreshold: 1,
+	}
+}
+
+// applyProbes sets the startup, readiness and liveness probes of the RavenDB container from spec.probes
+func applyProbes(c *corev1.Container, cluster *ravendbv1.RavenDBCluster) {
+	var spec ravendbv1.ProbesSpec
+	if cluster.Spec.Probes != nil {
+		spec = *cluster.Spec.Probes
+	}
+
+	c.StartupProbe = buildProbe(defaultStartupProbe(), spec.Startup)
+	c.ReadinessProbe = buildProbe(defaultReadinessProbe(), spec.Readiness)
+	c.LivenessProbe = buildProbe(defaultLivenessProbe(), spec.Liveness)
+}
+
+func buildProbe(probe *corev1.Probe, override *ravendbv1.ProbeSpec) *corev1.Probe {
+	if override == nil {
+		return probe
+	}
+	if override.Disabled {
+		return nil
+	}
+
+	if override.InitialDelaySeconds != nil {
+		probe.InitialDelaySeconds = *override.InitialDelaySeconds
+	}
+	if override.PeriodSeconds != nil {
+		probe.PeriodSeconds = *override.PeriodSeconds
+	}
+	if override.TimeoutSeconds != nil {
+		probe.TimeoutSeconds = *override.TimeoutSeconds
+	}
+	if override.FailureThreshold != nil {
+		probe.FailureThreshold = *override.FailureThreshold
+	}
+	return probe
+}
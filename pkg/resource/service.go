@@ -18,10 +18,10 @@ package resource
 
 import (
 	"context"
-	"fmt"
 
 	ravendbv1 "ravendb-operator/api/v1"
 	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/naming"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -41,11 +41,11 @@ func (b *ServiceBuilder) Build(ctx context.Context, cluster *ravendbv1.RavenDBCl
 
 func BuildService(cluster *ravendbv1.RavenDBCluster, node ravendbv1.RavenDBNode) (*corev1.Service, error) {
 
-	svcName := fmt.Sprintf("%s%s", common.Prefix, node.Tag)
+	svcName := naming.For(cluster).Node(node.Tag)
 
 	labels := buildServiceLabels(cluster, node)
 	ports := buildServicePorts()
-	selector := buildServiceSelector(node)
+	selector := buildServiceSelector(cluster, node)
 
 	svc := &corev1.Service{
 		TypeMeta: metav1.TypeMeta{
@@ -80,9 +80,10 @@ func buildServiceLabels(cluster *ravendbv1.RavenDBCluster, node ravendbv1.RavenD
 	}
 }
 
-func buildServiceSelector(node ravendbv1.RavenDBNode) map[string]string {
+func buildServiceSelector(cluster *ravendbv1.RavenDBCluster, node ravendbv1.RavenDBNode) map[string]string {
 	return map[string]string{
-		common.LabelNodeTag: node.Tag,
+		common.LabelInstance: cluster.Name,
+		common.LabelNodeTag:  node.Tag,
 	}
 }
 
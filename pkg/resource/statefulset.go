@@ -18,11 +18,10 @@ package resource
 
 import (
 	"context"
-	"fmt"
-
 
 	ravendbv1 "ravendb-operator/api/v1"
 	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/naming"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -43,11 +42,12 @@ func (b *StatefulSetBuilder) Build(ctx context.Context, cluster *ravendbv1.Raven
 
 
 func BuildStatefulSet(cluster *ravendbv1.RavenDBCluster, node ravendbv1.RavenDBNode) (*appsv1.StatefulSet, error) {
-	stsName := fmt.Sprintf("%s%s", common.Prefix, node.Tag)
+	names := naming.For(cluster)
+	stsName := names.Node(node.Tag)
 
 	replicas := int32(common.NumOfReplicas)
 	labels := buildStatefulsetLabels(cluster, node)
-	selector := &metav1.LabelSelector{MatchLabels: buildStatefulsetSelector(node)}
+	selector := &metav1.LabelSelector{MatchLabels: buildStatefulsetSelector(cluster, node)}
 	annotations := buildStatefulsetAnnotations()
 	ports := buildPorts()
 
@@ -76,7 +76,7 @@ func BuildStatefulSet(cluster *ravendbv1.RavenDBCluster, node ravendbv1.RavenDBN
 			Annotations: annotations,
 		},
 		Spec: appsv1.StatefulSetSpec{
-			ServiceName: common.App,
+			ServiceName: names.GoverningService(),
 			Replicas:    &replicas,
 			Selector:    selector,
 			Template: corev1.PodTemplateSpec{
@@ -84,10 +84,11 @@ func BuildStatefulSet(cluster *ravendbv1.RavenDBCluster, node ravendbv1.RavenDBN
 					Labels: labels,
 				},
 				Spec: corev1.PodSpec{
+					InitContainers:     BuildSidecarContainers(cluster.Spec.InitContainers),
 					Containers:         containers,
 					Volumes:            volumes,
 					Affinity:           affinity,
-					ServiceAccountName: common.RavenDbNodeServiceAccount,
+					ServiceAccountName: names.NodeServiceAccount(),
 
 					// alows us to bind lower ports like 443
 					// considered safe. see: https://kubernetes.io/docs/tasks/administer-cluster/sysctl-cluster/#safe-and-unsafe-sysctls
@@ -105,27 +106,33 @@ func BuildStatefulSet(cluster *ravendbv1.RavenDBCluster, node ravendbv1.RavenDBN
 		},
 	}
 
+	applyPodTemplate(sts, cluster, node)
+
 	return sts, nil
 }
 
 
 func buildContainers(image string, env []corev1.EnvVar, ports []corev1.ContainerPort, mounts []corev1.VolumeMount, ipp corev1.PullPolicy, cluster *ravendbv1.RavenDBCluster) []corev1.Container {
 	rdbContainer := BuildRavenDBContainer(image, env, ports, mounts, ipp)
+	applyProbes(&rdbContainer, cluster)
 
-	// TODO: might use sidecars later
-	// sideCarContainers := BuildSidecarContainers(cluster.Spec.Sidecars, nil)
-	// containers := append([]corev1.Container{rdbContainer}, sideCarContainers...)
-	// return containers
-
-	return []corev1.Container{rdbContainer}
-
+	// RavenDB stays the first container, the StatefulSet actor and the upgrader look it up by index
+	sideCarContainers := BuildSidecarContainers(cluster.Spec.Sidecars)
+	return append([]corev1.Container{rdbContainer}, sideCarContainers...)
 }
 
 
 
-func buildStatefulsetSelector(node ravendbv1.RavenDBNode) map[string]string {
+// the selector is immutable, clusters with legacy names keep selecting by tag only
+func buildStatefulsetSelector(cluster *ravendbv1.RavenDBCluster, node ravendbv1.RavenDBNode) map[string]string {
+	if naming.For(cluster).Legacy() {
+		return map[string]string{
+			common.LabelNodeTag: node.Tag}
+	}
 	return map[string]string{
-		common.LabelNodeTag: node.Tag}
+		common.LabelInstance: cluster.Name,
+		common.LabelNodeTag:  node.Tag,
+	}
 }
 
 
@@ -195,7 +202,7 @@ func buildVolumes(cluster *ravendbv1.RavenDBCluster, node ravendbv1.RavenDBNode)
 	// certs scripts
 	volumes = append(volumes, buildConfigMapVolume(
 		common.CertHookVolumeName,
-		common.CertHookConfigMap,
+		naming.For(cluster).CertHookConfigMap(),
 		map[string]string{
 			common.UpdateCertHookKey: common.UpdateCertHookKey,
 			common.GetCertHookKey:    common.GetCertHookKey,
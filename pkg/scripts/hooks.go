@@ -21,12 +21,6 @@ import (
 	"strings"
 )
 
-//go:embed check-nodes-discoverability.sh
-var checkNodesDiscoverabilityScriptRaw string
-
-//go:embed init-cluster.sh
-var initClusterScriptRaw string
-
 //go:embed update-cert.sh
 var updateCertScriptRaw string
 
@@ -38,8 +32,6 @@ func normalizeLF(s string) string {
 }
 
 var (
-	CheckNodesDiscoverabilityScript = normalizeLF(checkNodesDiscoverabilityScriptRaw)
-	InitClusterScript               = normalizeLF(initClusterScriptRaw)
-	UpdateCertScript                = normalizeLF(updateCertScriptRaw)
-	GetServerCertScript             = normalizeLF(getServerCertScriptRaw)
+	UpdateCertScript    = normalizeLF(updateCertScriptRaw)
+	GetServerCertScript = normalizeLF(getServerCertScriptRaw)
 )
@@ -13,26 +13,27 @@ function update_secret {
     chmod +x "$HOME/bin/kubectl"
     export PATH="$HOME/bin:$PATH"
 
-    cr_name=$(kubectl -n ravendb get ravendbcluster -o jsonpath='{.items[0].metadata.name}')
+    cr_name="$CLUSTER_NAME"
+    ns=$(cat /var/run/secrets/kubernetes.io/serviceaccount/namespace)
 
     if [ "$RAVEN_Setup_Mode" = "LetsEncrypt" ]; then
-        secret_name=$(kubectl -n ravendb get ravendbcluster "$cr_name" -o "jsonpath={.spec.nodes[?(@.tag=='$NODE_TAG')].certSecretRef}")
+        secret_name=$(kubectl -n "$ns" get ravendbcluster "$cr_name" -o "jsonpath={.spec.nodes[?(@.tag=='$NODE_TAG')].certSecretRef}")
     fi
 
     if [ "$RAVEN_Setup_Mode" = "None" ]; then
-        secret_name=$(kubectl -n ravendb get ravendbcluster "$cr_name" -o "jsonpath={.spec.clusterCertSecretRef}")
+        secret_name=$(kubectl -n "$ns" get ravendbcluster "$cr_name" -o "jsonpath={.spec.clusterCertSecretRef}")
     fi
 
-    previous_content=$(kubectl get secret "$secret_name" -n ravendb -o jsonpath='{.data.server\.pfx}')
+    previous_content=$(kubectl get secret "$secret_name" -n "$ns" -o jsonpath='{.data.server\.pfx}')
     echo "Previous secret (first 80 chars): ${previous_content:0:80}"
 
     # update secret
     echo "Updating server certificate on node server by updating ravendb-certs secret"
-    kubectl get secret "$secret_name" -o json -n ravendb | \
+    kubectl get secret "$secret_name" -o json -n "$ns" | \
         jq ".data[\"server.pfx\"]=\"$new_cert\"" | \
         kubectl apply -f -
 
-    content=$(kubectl get secret "$secret_name" -n ravendb -o jsonpath='{.data.server\.pfx}')
+    content=$(kubectl get secret "$secret_name" -n "$ns" -o jsonpath='{.data.server\.pfx}')
     echo "New secret (first 80 chars): ${content:0:80}"
 
     if [[ $previous_content == "$content" ]]; then
@@ -2,39 +2,32 @@ package upgrade
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"time"
 )
 
-func (hcc *HealthCheckContext) ClusterConnectivity(ctx context.Context) (bool, string, error) {
-	baseURL, err := hcc.clusterURL()
-	if err != nil {
-		return false, "", err
-	}
+// cluster_connectivity: every node of the cluster reaches every other node
+type clusterConnectivityGate struct{}
 
-	endpoint, err := join(baseURL, "/admin/debug/node/ping")
-	if err != nil {
-		return false, "", err
-	}
+func (clusterConnectivityGate) Kind() GateKind                  { return GateClusterConnectivity }
+func (clusterConnectivityGate) Phases() []GatePhase             { return []GatePhase{GatePreStep, GatePostStep} }
+func (clusterConnectivityGate) EnabledByDefault() bool          { return true }
+func (clusterConnectivityGate) Interval(t Timing) time.Duration { return t.PingInterval }
+func (clusterConnectivityGate) Check(ctx context.Context, hcc *HealthCheckContext, _ GateRequest) (bool, string, error) {
+	return hcc.ClusterConnectivity(ctx)
+}
 
-	code, body, err := hcc.httpGET(ctx, endpoint)
+func (hcc *HealthCheckContext) ClusterConnectivity(ctx context.Context) (bool, string, error) {
+	res, err := hcc.rc.Ping(ctx)
 	if err != nil {
-		return false, err.Error(), nil
-	}
-
-	if code < 200 || code >= 300 {
-		return false, fmt.Sprintf("HTTP %d (%s)", code, truncate(body, 200)), nil
-	}
-
-	var pr pingResponse
-	if err := json.Unmarshal([]byte(body), &pr); err != nil {
-		return false, "invalid ping response: bad JSON", nil
+		info, err := softInfo(err)
+		return false, info, err
 	}
-	if len(pr.Result) == 0 {
+	if len(res) == 0 {
 		return false, "invalid ping response: empty result", nil
 	}
 
-	for _, it := range pr.Result {
+	for _, it := range res {
 		setupErr := summarizeError(it.SetupAlive.Error)
 		tcpErr := summarizeError(it.TcpInfo.Error)
 		if setupErr != "" || tcpErr != "" {
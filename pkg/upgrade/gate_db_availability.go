@@ -2,32 +2,27 @@ package upgrade
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
-)
-
-type dbStatus struct {
-	LastStatus string
-	LastError  string
-}
-
-type nodesTopology struct {
-	Members     []map[string]any
-	Promotables []map[string]any
-	Rehabs      []map[string]any
-	Status      map[string]dbStatus
-}
+	"time"
 
-type databaseInfo struct {
-	Name              string
-	Disabled          bool
-	ReplicationFactor int
-	NodesTopology     nodesTopology
-}
+	"ravendb-operator/pkg/ravendb"
+)
 
-type databasesResponse struct {
-	Databases []databaseInfo
+// db_groups_available_excluding_target: every database group stays available without the target node
+// (pre-step), and with all nodes again once it was upgraded (post-step)
+type databasesOnlineGate struct{}
+
+func (databasesOnlineGate) Kind() GateKind                  { return GateDatabasesOnline }
+func (databasesOnlineGate) Phases() []GatePhase             { return []GatePhase{GatePreStep, GatePostStep} }
+func (databasesOnlineGate) EnabledByDefault() bool          { return true }
+func (databasesOnlineGate) Interval(t Timing) time.Duration { return t.DBInterval }
+func (databasesOnlineGate) Check(ctx context.Context, hcc *HealthCheckContext, req GateRequest) (bool, string, error) {
+	excluded := req.Tag
+	if req.Phase == GatePostStep {
+		excluded = ""
+	}
+	return hcc.DatabasesOnline(ctx, excluded)
 }
 
 var ignoredErrSnippets = []string{
@@ -36,43 +31,20 @@ var ignoredErrSnippets = []string{
 }
 
 func (hcc *HealthCheckContext) DatabasesOnline(ctx context.Context, excludedTag string) (bool, string, error) {
-	baseURL, err := hcc.clusterURL()
-	if err != nil {
-		return false, "", err
-	}
-
-	endpoint, err := join(baseURL, "/databases")
-	if err != nil {
-		return false, "", err
-	}
-
-	code, body, err := hcc.httpGET(ctx, endpoint)
-	if err != nil {
-		return false, err.Error(), nil
-	}
-	if code < 200 || code >= 300 {
-		return false, fmt.Sprintf("HTTP %d (%s)", code, truncate(body, 200)), nil
+	dbs, info, err := hcc.fetchDatabases(ctx)
+	if err != nil || dbs == nil {
+		return false, info, err
 	}
-
-	var dr databasesResponse
-	if json.Unmarshal([]byte(body), &dr) != nil {
-		return false, "invalid /databases response", nil
-	}
-	if len(dr.Databases) == 0 {
+	if len(dbs) == 0 {
 		return true, "no databases", nil
 	}
 
-	for _, db := range dr.Databases {
+	for _, db := range dbs {
 		if db.Disabled || db.ReplicationFactor == 1 {
 			continue
 		}
 
-		nodes := append(
-			append(db.NodesTopology.Members, db.NodesTopology.Promotables...),
-			db.NodesTopology.Rehabs...,
-		)
-
-		allTags := pluckTags(nodes)
+		allTags := db.NodesTopology.Tags()
 
 		var okNodes []string
 		var firstNonIgnored string
@@ -111,6 +83,19 @@ func (hcc *HealthCheckContext) DatabasesOnline(ctx context.Context, excludedTag
 	return true, "", nil
 }
 
+// fetchDatabases returns nil with an info message when the cluster didn't answer usefully yet
+func (hcc *HealthCheckContext) fetchDatabases(ctx context.Context) ([]ravendb.Database, string, error) {
+	dbs, err := hcc.rc.Databases(ctx)
+	if err != nil {
+		info, err := softInfo(err)
+		return nil, info, err
+	}
+	if dbs == nil {
+		dbs = []ravendb.Database{}
+	}
+	return dbs, "", nil
+}
+
 func isHardLoadError(s string) bool {
 	return strings.Contains(strings.ToLower(s), "endofstreamexception")
 }
@@ -124,21 +109,3 @@ func isIgnoredTransient(s string) bool {
 	}
 	return false
 }
-
-func pluckTags(arr []map[string]any) []string {
-	seen := map[string]struct{}{}
-	out := make([]string, 0, len(arr))
-	for _, m := range arr {
-		if tag, _ := m["NodeTag"].(string); tag != "" {
-			tag = strings.TrimSpace(tag)
-			if tag == "" {
-				continue
-			}
-			if _, ok := seen[tag]; !ok {
-				seen[tag] = struct{}{}
-				out = append(out, tag)
-			}
-		}
-	}
-	return out
-}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"ravendb-operator/pkg/ravendb"
+)
+
+// index_staleness (post-step, opt-in): the upgraded node finished rebuilding its indexes.
+// params: maxStaleIndexes - stale indexes tolerated on the node across all its databases (default 0)
+type indexStalenessGate struct{}
+
+const paramMaxStaleIndexes = "maxStaleIndexes"
+
+func (indexStalenessGate) Kind() GateKind                  { return GateIndexStaleness }
+func (indexStalenessGate) Phases() []GatePhase             { return []GatePhase{GatePostStep} }
+func (indexStalenessGate) EnabledByDefault() bool          { return false }
+func (indexStalenessGate) Interval(t Timing) time.Duration { return t.DBInterval }
+func (indexStalenessGate) Check(ctx context.Context, hcc *HealthCheckContext, req GateRequest) (bool, string, error) {
+	max, err := intParam(req.Params, paramMaxStaleIndexes, 0)
+	if err != nil {
+		return false, "", err
+	}
+	return hcc.IndexesUpToDate(ctx, req.Tag, max)
+}
+
+func (indexStalenessGate) ValidateParams(params map[string]string) error {
+	if err := knownParams(params, paramMaxStaleIndexes); err != nil {
+		return err
+	}
+	_, err := intParam(params, paramMaxStaleIndexes, 0)
+	return err
+}
+
+// IndexesUpToDate checks that the node has at most maxStale stale indexes in the databases it hosts
+func (hcc *HealthCheckContext) IndexesUpToDate(ctx context.Context, tag string, maxStale int64) (bool, string, error) {
+	dbs, info, err := hcc.hostedDatabases(ctx, tag)
+	if err != nil || dbs == nil {
+		return false, info, err
+	}
+
+	var stale []string
+	for _, db := range dbs {
+		st, info, err := hcc.fetchDatabaseStats(ctx, tag, db)
+		if err != nil || st == nil {
+			return false, info, err
+		}
+		for _, idx := range st.Indexes {
+			if idx.IsStale {
+				stale = append(stale, db+"/"+idx.Name)
+			}
+		}
+	}
+
+	if int64(len(stale)) > maxStale {
+		return false, fmt.Sprintf("%d stale indexes (max %d): %s", len(stale), maxStale, truncate(strings.Join(stale, ", "), 200)), nil
+	}
+	return true, "", nil
+}
+
+// hostedDatabases lists the enabled databases which topology contains the node.
+// nil with an info message means the cluster didn't answer usefully yet.
+func (hcc *HealthCheckContext) hostedDatabases(ctx context.Context, tag string) ([]string, string, error) {
+	dbs, info, err := hcc.fetchDatabases(ctx)
+	if err != nil || dbs == nil {
+		return nil, info, err
+	}
+
+	out := []string{}
+	for _, db := range dbs {
+		if !db.Disabled && db.NodesTopology.Hosts(tag) {
+			out = append(out, db.Name)
+		}
+	}
+	return out, "", nil
+}
+
+// fetchDatabaseStats asks the node itself, stats are local to the node
+func (hcc *HealthCheckContext) fetchDatabaseStats(ctx context.Context, tag, db string) (*ravendb.DatabaseStats, string, error) {
+	st, err := hcc.rc.DatabaseStats(ctx, tag, db)
+	if err != nil {
+		info, err := softInfo(err)
+		if err != nil {
+			return nil, info, err
+		}
+		return nil, fmt.Sprintf("node=%s db=%s %s", tag, db, info), nil
+	}
+	return st, "", nil
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"ravendb-operator/pkg/ravendb"
+)
+
+// ClusterTopology reads /cluster/topology from the first node that answers with a leader (the first spec
+// node first). when the nodes answer but none knows a leader, the topology is returned without one.
+func (hcc *HealthCheckContext) ClusterTopology(ctx context.Context) (*ravendb.Topology, string, error) {
+	t, err := hcc.rc.Topology(ctx)
+	if errors.Is(err, ravendb.ErrNoLeader) {
+		return &ravendb.Topology{}, "", nil
+	}
+	if err != nil {
+		info, err := softInfo(err)
+		return nil, info, err
+	}
+	return t, "", nil
+}
+
+// StepDownLeader asks the leader to give up leadership so the cluster elects another node
+func (hcc *HealthCheckContext) StepDownLeader(ctx context.Context, tag string) error {
+	return hcc.rc.Reelect(ctx, tag)
+}
+
+// leader_step_down (pre-step, opt-in): when the node about to be restarted is the Raft leader,
+// ask it to step down and wait until another node leads. single member clusters pass.
+// the leader is asked once per term, an election takes a few ticks and asking again would only restart it.
+type leaderStepDownGate struct {
+	mu    sync.Mutex
+	asked map[string]stepDownRequest
+}
+
+type stepDownRequest struct {
+	leader  string
+	term    int64
+	checked time.Time
+}
+
+func newLeaderStepDownGate() *leaderStepDownGate {
+	return &leaderStepDownGate{asked: map[string]stepDownRequest{}}
+}
+
+func (*leaderStepDownGate) Kind() GateKind                  { return GateLeaderStepDown }
+func (*leaderStepDownGate) Phases() []GatePhase             { return []GatePhase{GatePreStep} }
+func (*leaderStepDownGate) EnabledByDefault() bool          { return false }
+func (*leaderStepDownGate) Interval(t Timing) time.Duration { return t.PingInterval }
+func (g *leaderStepDownGate) Check(ctx context.Context, hcc *HealthCheckContext, req GateRequest) (bool, string, error) {
+	t, info, err := hcc.ClusterTopology(ctx)
+	if err != nil || t == nil {
+		return false, info, err
+	}
+	if t.Leader == "" {
+		return false, "no leader elected", nil
+	}
+	if !strings.EqualFold(t.Leader, req.Tag) || len(t.Topology.Members) < 2 {
+		g.forget(req.ClusterUID)
+		return true, "", nil
+	}
+
+	if g.askedBefore(req.ClusterUID, t.Leader, t.CurrentTerm) {
+		return false, fmt.Sprintf("waiting for leader %s to step down (asked in term %d)", req.Tag, t.CurrentTerm), nil
+	}
+	if err := hcc.StepDownLeader(ctx, req.Tag); err != nil {
+		return false, fmt.Sprintf("step down of leader %s failed: %v", req.Tag, err), nil
+	}
+	g.ask(req.ClusterUID, t.Leader, t.CurrentTerm)
+	return false, fmt.Sprintf("asked leader %s to step down (term %d)", req.Tag, t.CurrentTerm), nil
+}
+
+// askedBefore reports whether leader was already asked to step down in term
+func (g *leaderStepDownGate) askedBefore(cluster, leader string, term int64) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	pruneStale(g.asked, now, func(r stepDownRequest) time.Time { return r.checked })
+	prev, ok := g.asked[cluster]
+	if !ok || prev.leader != leader || prev.term != term {
+		return false
+	}
+	prev.checked = now
+	g.asked[cluster] = prev
+	return true
+}
+
+func (g *leaderStepDownGate) ask(cluster, leader string, term int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.asked[cluster] = stepDownRequest{leader: leader, term: term, checked: time.Now()}
+}
+
+func (g *leaderStepDownGate) forget(cluster string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.asked, cluster)
+}
+
+// leader_stable (pre-step and post-step): the cluster has a leader and neither the leader nor the term
+// changed for a period, so the next restart does not pile up on an election.
+// the observation is kept in memory, after an operator restart the period starts again.
+// params: stablePeriod - how long leader and term must stay the same (default 30s)
+type leaderStableGate struct {
+	mu   sync.Mutex
+	seen map[string]leaderObservation
+}
+
+type leaderObservation struct {
+	leader  string
+	term    int64
+	since   time.Time
+	checked time.Time
+}
+
+const paramStablePeriod = "stablePeriod"
+
+func newLeaderStableGate() *leaderStableGate {
+	return &leaderStableGate{seen: map[string]leaderObservation{}}
+}
+
+func (*leaderStableGate) Kind() GateKind                  { return GateLeaderStable }
+func (*leaderStableGate) Phases() []GatePhase             { return []GatePhase{GatePreStep, GatePostStep} }
+func (*leaderStableGate) EnabledByDefault() bool          { return true }
+func (*leaderStableGate) Interval(t Timing) time.Duration { return t.PingInterval }
+func (g *leaderStableGate) Check(ctx context.Context, hcc *HealthCheckContext, req GateRequest) (bool, string, error) {
+	period, err := durationParam(req.Params, paramStablePeriod, 30*time.Second)
+	if err != nil {
+		return false, "", err
+	}
+
+	t, info, err := hcc.ClusterTopology(ctx)
+	if err != nil || t == nil {
+		return false, info, err
+	}
+	if t.Leader == "" {
+		g.forget(req.ClusterUID)
+		return false, "no leader elected", nil
+	}
+
+	stableFor := g.observe(req.ClusterUID, t.Leader, t.CurrentTerm)
+	if stableFor < period {
+		return false, fmt.Sprintf("leader %s (term %d) stable for %s of %s", t.Leader, t.CurrentTerm, stableFor.Round(time.Second), period), nil
+	}
+	return true, "", nil
+}
+
+func (*leaderStableGate) ValidateParams(params map[string]string) error {
+	if err := knownParams(params, paramStablePeriod); err != nil {
+		return err
+	}
+	_, err := durationParam(params, paramStablePeriod, 30*time.Second)
+	return err
+}
+
+// observe records the leader/term of the cluster and returns how long they did not change
+func (g *leaderStableGate) observe(cluster, leader string, term int64) time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	pruneStale(g.seen, now, func(o leaderObservation) time.Time { return o.checked })
+	prev, ok := g.seen[cluster]
+	if !ok || prev.leader != leader || prev.term != term {
+		g.seen[cluster] = leaderObservation{leader: leader, term: term, since: now, checked: now}
+		return 0
+	}
+	prev.checked = now
+	g.seen[cluster] = prev
+	return now.Sub(prev.since)
+}
+
+func (g *leaderStableGate) forget(cluster string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.seen, cluster)
+}
+
+// leaderStateTTL is how long the leader gates keep the state of a cluster they are no longer asked about,
+// the rollout finished or the cluster is gone. a running gate is checked every PingInterval.
+const leaderStateTTL = time.Hour
+
+// pruneStale drops the entries of state that weren't checked for leaderStateTTL
+func pruneStale[V any](state map[string]V, now time.Time, checked func(V) time.Time) {
+	for k, v := range state {
+		if now.Sub(checked(v)) > leaderStateTTL {
+			delete(state, k)
+		}
+	}
+}
@@ -18,29 +18,24 @@ package upgrade
 
 import (
 	"context"
-	"fmt"
-	"strings"
+	"time"
 )
 
-func (hcc *HealthCheckContext) NodeAlive(ctx context.Context, tag string) (bool, string, error) {
-	nodeURL := strings.TrimSpace(hcc.urlForTag(tag))
-	if nodeURL == "" {
-		return false, "empty node url", fmt.Errorf("no URL for tag %q", tag)
-	}
-
-	endpoint, err := join(nodeURL, "/setup/alive")
-	if err != nil {
-		return false, err.Error(), nil
-	}
+// node_alive: the target node answers /setup/alive
+type nodeAliveGate struct{}
 
-	code, body, err := hcc.httpGET(ctx, endpoint)
-	if err != nil {
-		return false, err.Error(), nil
-	}
+func (nodeAliveGate) Kind() GateKind                  { return GateNodeAlive }
+func (nodeAliveGate) Phases() []GatePhase             { return []GatePhase{GatePreStep, GatePostStep} }
+func (nodeAliveGate) EnabledByDefault() bool          { return true }
+func (nodeAliveGate) Interval(t Timing) time.Duration { return t.PingInterval }
+func (nodeAliveGate) Check(ctx context.Context, hcc *HealthCheckContext, req GateRequest) (bool, string, error) {
+	return hcc.NodeAlive(ctx, req.Tag)
+}
 
-	if code >= 200 && code < 300 {
-		return true, fmt.Sprintf("status:%d", code), nil
+func (hcc *HealthCheckContext) NodeAlive(ctx context.Context, tag string) (bool, string, error) {
+	if err := hcc.rc.Alive(ctx, tag); err != nil {
+		info, err := softInfo(err)
+		return false, info, err
 	}
-
-	return false, fmt.Sprintf("HTTP %d (%s)", code, truncate(body, 200)), nil
+	return true, "", nil
 }
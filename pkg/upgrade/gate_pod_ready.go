@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"ravendb-operator/pkg/naming"
+)
+
+// pod_ready: the pod of the target node is Ready. after the image change it also has to be the pod
+// of the StatefulSet's new revision, the old pod stays Ready until it is terminated.
+// readiness is /setup/alive (see spec.probes), so this is the kubelet's view of node_alive.
+type podReadyGate struct{}
+
+func (podReadyGate) Kind() GateKind                  { return GatePodReady }
+func (podReadyGate) Phases() []GatePhase             { return []GatePhase{GatePreStep, GatePostStep} }
+func (podReadyGate) EnabledByDefault() bool          { return true }
+func (podReadyGate) Interval(t Timing) time.Duration { return t.PingInterval }
+func (podReadyGate) Check(ctx context.Context, hcc *HealthCheckContext, req GateRequest) (bool, string, error) {
+	return hcc.PodReady(ctx, req.Tag, req.Phase == GatePostStep)
+}
+
+func (hcc *HealthCheckContext) PodReady(ctx context.Context, tag string, updated bool) (bool, string, error) {
+	if hcc.kc == nil || hcc.cluster == nil {
+		return false, "pod checks need WithPods", nil
+	}
+	names := naming.For(hcc.cluster)
+	ns := hcc.cluster.Namespace
+
+	var pod corev1.Pod
+	if err := hcc.kc.Get(ctx, client.ObjectKey{Namespace: ns, Name: names.Pod(tag)}, &pod); err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, fmt.Sprintf("pod %s not created yet", names.Pod(tag)), nil
+		}
+		return false, err.Error(), nil
+	}
+
+	if updated {
+		var sts appsv1.StatefulSet
+		if err := hcc.kc.Get(ctx, client.ObjectKey{Namespace: ns, Name: names.Node(tag)}, &sts); err != nil {
+			return false, err.Error(), nil
+		}
+		if sts.Status.ObservedGeneration < sts.Generation {
+			return false, fmt.Sprintf("statefulset %s not rolled out yet", sts.Name), nil
+		}
+		if rev := pod.Labels[appsv1.ControllerRevisionHashLabelKey]; rev != sts.Status.UpdateRevision {
+			return false, fmt.Sprintf("pod %s still on revision %s", pod.Name, rev), nil
+		}
+	}
+
+	if pod.DeletionTimestamp != nil {
+		return false, fmt.Sprintf("pod %s is terminating", pod.Name), nil
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady && c.Status == corev1.ConditionTrue {
+			return true, "", nil
+		}
+	}
+	return false, fmt.Sprintf("pod %s not ready", pod.Name), nil
+}
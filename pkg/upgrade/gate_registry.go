@@ -0,0 +1,233 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	ravendbv1 "ravendb-operator/api/v1"
+)
+
+// Gate is a single upgrade check. the upgrader runs the enabled gates of a phase in registry order,
+// one check per tick, and reports every outcome through the GateEmitter.
+type Gate interface {
+	Kind() GateKind
+	// phases the gate takes part in
+	Phases() []GatePhase
+	// whether the gate runs when spec.upgrade.gates does not mention it
+	EnabledByDefault() bool
+	// time between two checks while the gate blocks (before backoff)
+	Interval(t Timing) time.Duration
+	// Check returns ok when the gate passes, info describing the state otherwise.
+	// an error fails the gate immediately.
+	Check(ctx context.Context, hcc *HealthCheckContext, req GateRequest) (ok bool, info string, err error)
+}
+
+// GateParamsValidator is implemented by the gates that take spec.upgrade.gates[].params
+type GateParamsValidator interface {
+	// ValidateParams rejects unknown params and values Check would fail on
+	ValidateParams(params map[string]string) error
+}
+
+// GateRequest is what a gate knows about the check it is asked for
+type GateRequest struct {
+	// namespace/name of the cluster
+	Cluster string
+	// metadata.uid of the cluster, gates keep their per cluster state under it so a recreated cluster starts over
+	ClusterUID string
+	Phase      GatePhase
+	// tag of the node being upgraded
+	Tag string
+	// spec.upgrade.gates[].params of the gate
+	Params map[string]string
+}
+
+// GateRegistry keeps the known gates in the order they run
+type GateRegistry struct {
+	mu    sync.RWMutex
+	gates []Gate
+}
+
+func NewGateRegistry(gates ...Gate) *GateRegistry {
+	r := &GateRegistry{}
+	for _, g := range gates {
+		r.Register(g)
+	}
+	return r
+}
+
+// DefaultGateRegistry returns the built-in gates
+func DefaultGateRegistry() *GateRegistry {
+	return NewGateRegistry(
+		podReadyGate{},
+		nodeAliveGate{},
+		clusterConnectivityGate{},
+		databasesOnlineGate{},
+		indexStalenessGate{},
+		replicationLagGate{},
+		newLeaderStepDownGate(),
+		newLeaderStableGate(),
+	)
+}
+
+// Register appends a gate, or replaces the gate of the same kind in place
+func (r *GateRegistry) Register(g Gate) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.gates {
+		if r.gates[i].Kind() == g.Kind() {
+			r.gates[i] = g
+			return
+		}
+	}
+	r.gates = append(r.gates, g)
+}
+
+func (r *GateRegistry) Get(kind GateKind) (Gate, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, g := range r.gates {
+		if g.Kind() == kind {
+			return g, true
+		}
+	}
+	return nil, false
+}
+
+func (r *GateRegistry) All() []Gate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.gates)
+}
+
+// GateNames returns the kinds of the registered gates, in the order they run
+func (r *GateRegistry) GateNames() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.gates))
+	for _, g := range r.gates {
+		names = append(names, string(g.Kind()))
+	}
+	return names
+}
+
+// ValidateGateParams checks the spec.upgrade.gates[].params of gate name. the webhook calls it, so a bad
+// value is rejected up front instead of failing a node in the middle of a rollout.
+func (r *GateRegistry) ValidateGateParams(name string, params map[string]string) error {
+	g, ok := r.Get(GateKind(name))
+	if !ok {
+		return fmt.Errorf("unknown gate %q", name)
+	}
+	if v, ok := g.(GateParamsValidator); ok {
+		return v.ValidateParams(params)
+	}
+	return knownParams(params)
+}
+
+// activeGate is a gate enabled for a cluster, with its spec overrides applied
+type activeGate struct {
+	Gate
+	params  map[string]string
+	timeout time.Duration
+}
+
+// gatesFor returns the ordered, enabled gates of a phase for the cluster.
+// spec.upgrade.gates can enable/disable a gate, give it its own timeout and pass params to it.
+func (u *upgrader) gatesFor(c *ravendbv1.RavenDBCluster, phase GatePhase) []activeGate {
+	overrides := map[GateKind]ravendbv1.UpgradeGateSpec{}
+	if c.Spec.Upgrade != nil {
+		for _, o := range c.Spec.Upgrade.Gates {
+			overrides[GateKind(o.Name)] = o
+		}
+	}
+
+	out := []activeGate{}
+	for _, g := range u.gates.All() {
+		if !slices.Contains(g.Phases(), phase) {
+			continue
+		}
+
+		ag := activeGate{Gate: g, timeout: u.maxWaitFor(phase)}
+		enabled := g.EnabledByDefault()
+		if o, ok := overrides[g.Kind()]; ok {
+			if o.Enabled != nil {
+				enabled = *o.Enabled
+			}
+			if o.Timeout != nil && o.Timeout.Duration > 0 {
+				ag.timeout = o.Timeout.Duration
+			}
+			ag.params = o.Params
+		}
+		if enabled {
+			out = append(out, ag)
+		}
+	}
+	return out
+}
+
+// knownParams rejects the params that aren't in known, a typo would otherwise be ignored
+func knownParams(params map[string]string, known ...string) error {
+	var unknown []string
+	for k := range params {
+		if !slices.Contains(known, k) {
+			unknown = append(unknown, k)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	slices.Sort(unknown)
+	if len(known) == 0 {
+		return fmt.Errorf("the gate takes no params, got %s", strings.Join(unknown, ", "))
+	}
+	return fmt.Errorf("unknown params %s, the gate takes %s", strings.Join(unknown, ", "), strings.Join(known, ", "))
+}
+
+// intParam reads a non negative integer gate param, def when it is not set
+func intParam(params map[string]string, key string, def int64) (int64, error) {
+	v, ok := params[key]
+	if !ok || strings.TrimSpace(v) == "" {
+		return def, nil
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("param %s=%q must be a non negative integer", key, v)
+	}
+	return n, nil
+}
+
+// durationParam reads a positive duration gate param (e.g. "30s"), def when it is not set
+func durationParam(params map[string]string, key string, def time.Duration) (time.Duration, error) {
+	v, ok := params[key]
+	if !ok || strings.TrimSpace(v) == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(v))
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("param %s=%q must be a positive duration", key, v)
+	}
+	return d, nil
+}
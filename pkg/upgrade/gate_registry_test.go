@@ -0,0 +1,144 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package upgrade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	ravendbv1 "ravendb-operator/api/v1"
+)
+
+func kinds(gates []activeGate) []GateKind {
+	out := []GateKind{}
+	for _, g := range gates {
+		out = append(out, g.Kind())
+	}
+	return out
+}
+
+func Test_GR1_RegisterReplacesInPlace(t *testing.T) {
+	r := NewGateRegistry(passing(GatePodReady, GatePreStep), passing(GateNodeAlive, GatePreStep))
+	blocked := blocking(GatePodReady, GatePreStep, "replaced")
+	r.Register(blocked)
+	r.Register(passing(GateReplicationLag, GatePostStep))
+
+	all := r.All()
+	require.Len(t, all, 3)
+	require.Equal(t, GatePodReady, all[0].Kind())
+	require.Equal(t, GateReplicationLag, all[2].Kind())
+
+	g, ok := r.Get(GatePodReady)
+	require.True(t, ok)
+	_, info, _ := g.Check(context.Background(), nil, GateRequest{})
+	require.Equal(t, "replaced", info)
+
+	_, ok = r.Get(GateLeaderStepDown)
+	require.False(t, ok)
+}
+
+func Test_GR2_SpecOverrides(t *testing.T) {
+	u := NewUpgrader(Timing{PreMaxWait: time.Minute, PostMaxWait: 2 * time.Minute}).(*upgrader)
+	c := &ravendbv1.RavenDBCluster{}
+
+	require.Equal(t, []GateKind{GatePodReady, GateNodeAlive, GateClusterConnectivity, GateDatabasesOnline, GateLeaderStable}, kinds(u.gatesFor(c, GatePreStep)))
+	post := u.gatesFor(c, GatePostStep)
+	require.Equal(t, []GateKind{GatePodReady, GateNodeAlive, GateClusterConnectivity, GateDatabasesOnline, GateLeaderStable}, kinds(post))
+	require.Equal(t, 2*time.Minute, post[0].timeout)
+
+	c.Spec.Upgrade = &ravendbv1.UpgradeSpec{Gates: []ravendbv1.UpgradeGateSpec{
+		{Name: string(GateClusterConnectivity), Enabled: ptr.To(false)},
+		{Name: string(GateLeaderStepDown), Enabled: ptr.To(true)},
+		{Name: string(GateReplicationLag), Enabled: ptr.To(true), Timeout: &metav1.Duration{Duration: 10 * time.Minute}, Params: map[string]string{"maxLag": "100"}},
+		{Name: string(GateNodeAlive), Timeout: &metav1.Duration{Duration: 30 * time.Second}},
+		{Name: "no_such_gate", Enabled: ptr.To(true)},
+	}}
+
+	// the registry order is kept, whatever the order of the overrides
+	require.Equal(t, []GateKind{GatePodReady, GateNodeAlive, GateDatabasesOnline, GateLeaderStepDown, GateLeaderStable}, kinds(u.gatesFor(c, GatePreStep)))
+	post = u.gatesFor(c, GatePostStep)
+	require.Equal(t, []GateKind{GatePodReady, GateNodeAlive, GateDatabasesOnline, GateReplicationLag, GateLeaderStable}, kinds(post))
+	require.Equal(t, 30*time.Second, post[1].timeout)
+	require.Equal(t, 10*time.Minute, post[3].timeout)
+	require.Equal(t, map[string]string{"maxLag": "100"}, post[3].params)
+}
+
+func Test_GR3_OverridesReachTheGate(t *testing.T) {
+	var got GateRequest
+	g := stubGate{kind: GatePodReady, phases: []GatePhase{GatePreStep}, check: func(req GateRequest) (bool, string, error) {
+		got = req
+		return false, "waiting", nil
+	}}
+	e := newRolloutEnv(t, g, passing(GateNodeAlive, GatePostStep))
+	var events []GateState
+	e.u.SetEmitter(func(_ *ravendbv1.RavenDBCluster, state GateState, phase GatePhase, kind GateKind, tag, _ string) {
+		require.Equal(t, GatePreStep, phase)
+		require.Equal(t, GatePodReady, kind)
+		require.Equal(t, "B", tag)
+		events = append(events, state)
+	})
+	e.c.Spec.Upgrade = &ravendbv1.UpgradeSpec{Gates: []ravendbv1.UpgradeGateSpec{
+		{Name: string(GatePodReady), Timeout: &metav1.Duration{Duration: 5 * time.Minute}, Params: map[string]string{"k": "v"}},
+	}}
+
+	_, err := e.tick()
+	require.NoError(t, err)
+	require.Equal(t, GateRequest{Cluster: "ravendb/db", Phase: GatePreStep, Tag: "B", Params: map[string]string{"k": "v"}}, got)
+	require.Equal(t, []GateState{GateStart, GateBlock}, events)
+
+	st := e.c.Status.Upgrade
+	require.Equal(t, 5*time.Minute, st.Deadline.Sub(st.GateStartTime.Time))
+}
+
+func Test_GR4_Params(t *testing.T) {
+	n, err := intParam(nil, "maxLag", 7)
+	require.NoError(t, err)
+	require.Equal(t, int64(7), n)
+
+	n, err = intParam(map[string]string{"maxLag": " 42 "}, "maxLag", 7)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), n)
+
+	_, err = intParam(map[string]string{"maxLag": "-1"}, "maxLag", 7)
+	require.EqualError(t, err, `param maxLag="-1" must be a non negative integer`)
+
+	d, err := durationParam(map[string]string{"period": "30s"}, "period", time.Second)
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Second, d)
+
+	_, err = durationParam(map[string]string{"period": "0s"}, "period", time.Second)
+	require.Error(t, err)
+}
+
+func Test_GR5_ValidateGateParams(t *testing.T) {
+	r := DefaultGateRegistry()
+	require.Equal(t, []string{"pod_ready", "node_alive", "cluster_connectivity", "db_groups_available_excluding_target", "index_staleness", "replication_lag", "leader_step_down", "leader_stable"}, r.GateNames())
+
+	require.NoError(t, r.ValidateGateParams("replication_lag", nil))
+	require.NoError(t, r.ValidateGateParams("replication_lag", map[string]string{"maxLag": "500"}))
+	require.NoError(t, r.ValidateGateParams("leader_stable", map[string]string{"stablePeriod": "1m"}))
+
+	require.EqualError(t, r.ValidateGateParams("nope", nil), `unknown gate "nope"`)
+	require.EqualError(t, r.ValidateGateParams("replication_lag", map[string]string{"maxLag": "abc"}), `param maxLag="abc" must be a non negative integer`)
+	require.Error(t, r.ValidateGateParams("leader_stable", map[string]string{"stablePeriod": "0"}))
+	require.EqualError(t, r.ValidateGateParams("index_staleness", map[string]string{"maxStale": "1"}), "unknown params maxStale, the gate takes maxStaleIndexes")
+	require.EqualError(t, r.ValidateGateParams("pod_ready", map[string]string{"timeout": "1m"}), "the gate takes no params, got timeout")
+}
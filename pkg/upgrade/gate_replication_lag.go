@@ -0,0 +1,116 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// replication_lag (post-step, opt-in): the upgraded node caught up with the other nodes of its database groups.
+// the lag is measured in etags: for every database origin (change vector entry) seen on another node,
+// how far the upgraded node is behind.
+// params: maxLag - etags the node may be behind per database (default 100)
+type replicationLagGate struct{}
+
+const paramMaxLag = "maxLag"
+
+func (replicationLagGate) Kind() GateKind                  { return GateReplicationLag }
+func (replicationLagGate) Phases() []GatePhase             { return []GatePhase{GatePostStep} }
+func (replicationLagGate) EnabledByDefault() bool          { return false }
+func (replicationLagGate) Interval(t Timing) time.Duration { return t.DBInterval }
+func (replicationLagGate) Check(ctx context.Context, hcc *HealthCheckContext, req GateRequest) (bool, string, error) {
+	max, err := intParam(req.Params, paramMaxLag, 100)
+	if err != nil {
+		return false, "", err
+	}
+	return hcc.ReplicationCaughtUp(ctx, req.Tag, max)
+}
+
+func (replicationLagGate) ValidateParams(params map[string]string) error {
+	if err := knownParams(params, paramMaxLag); err != nil {
+		return err
+	}
+	_, err := intParam(params, paramMaxLag, 100)
+	return err
+}
+
+// ReplicationCaughtUp checks that in every database the node hosts it is at most maxLag etags behind the other nodes
+func (hcc *HealthCheckContext) ReplicationCaughtUp(ctx context.Context, tag string, maxLag int64) (bool, string, error) {
+	dbs, info, err := hcc.fetchDatabases(ctx)
+	if err != nil || dbs == nil {
+		return false, info, err
+	}
+
+	for _, db := range dbs {
+		if db.Disabled {
+			continue
+		}
+		tags := db.NodesTopology.Tags()
+		if !db.NodesTopology.Hosts(tag) || len(tags) < 2 {
+			continue
+		}
+
+		target, info, err := hcc.fetchDatabaseStats(ctx, tag, db.Name)
+		if err != nil || target == nil {
+			return false, info, err
+		}
+		own := parseChangeVector(target.DatabaseChangeVector)
+
+		for _, peer := range tags {
+			if strings.EqualFold(peer, tag) || !hcc.rc.HasNode(peer) {
+				continue
+			}
+			st, info, err := hcc.fetchDatabaseStats(ctx, peer, db.Name)
+			if err != nil {
+				return false, info, err
+			}
+			if st == nil {
+				// an unreachable peer can't be ahead of us, the availability gates cover it
+				continue
+			}
+			for dbID, etag := range parseChangeVector(st.DatabaseChangeVector) {
+				if lag := etag - own[dbID]; lag > maxLag {
+					return false, fmt.Sprintf("db=%s node=%s is %d etags behind %s (max %d)", db.Name, tag, lag, peer, maxLag), nil
+				}
+			}
+		}
+	}
+	return true, "", nil
+}
+
+// parseChangeVector maps database id -> etag, e.g. "A:1042-7Hh5...==, B:77-o2Q..." (malformed entries are skipped)
+func parseChangeVector(cv string) map[string]int64 {
+	out := map[string]int64{}
+	for _, entry := range strings.Split(cv, ",") {
+		entry = strings.TrimSpace(entry)
+		colon := strings.Index(entry, ":")
+		dash := strings.Index(entry, "-")
+		if colon < 0 || dash < colon {
+			continue
+		}
+		etag, err := strconv.ParseInt(entry[colon+1:dash], 10, 64)
+		if err != nil {
+			continue
+		}
+		out[entry[dash+1:]] = etag
+	}
+	return out
+}
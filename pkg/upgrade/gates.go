@@ -17,10 +17,10 @@ limitations under the License.
 package upgrade
 
 import (
-	"net/http"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
 	ravendbv1 "ravendb-operator/api/v1"
-	"strings"
-	"time"
+	"ravendb-operator/pkg/ravendb"
 )
 
 type GatePhase string
@@ -33,39 +33,31 @@ const (
 type GateKind string
 
 const (
+	GatePodReady            GateKind = "pod_ready"
 	GateNodeAlive           GateKind = "node_alive"
 	GateClusterConnectivity GateKind = "cluster_connectivity"
 	GateDatabasesOnline     GateKind = "db_groups_available_excluding_target"
+	GateIndexStaleness      GateKind = "index_staleness"
+	GateReplicationLag      GateKind = "replication_lag"
+	GateLeaderStepDown      GateKind = "leader_step_down"
+	GateLeaderStable        GateKind = "leader_stable"
 )
 
 type HealthCheckContext struct {
-	http    *http.Client
-	baseURL string
-	byTag   map[string]string
-}
-
-func NewChecks(httpc *http.Client, c *ravendbv1.RavenDBCluster) *HealthCheckContext {
-	leader := ""
-	if len(c.Spec.Nodes) > 0 {
-		leader = c.Spec.Nodes[0].PublicServerUrl
-	}
+	rc *ravendb.Client
 
-	urlByTag := map[string]string{}
-	for _, n := range c.Spec.Nodes {
-		urlByTag[strings.ToUpper(n.Tag)] = n.PublicServerUrl
-	}
-
-	if httpc == nil {
-		httpc = &http.Client{Timeout: 30 * time.Second}
-	}
+	// set by WithPods, the checks on the node pods fail without them
+	kc      client.Client
+	cluster *ravendbv1.RavenDBCluster
+}
 
-	return &HealthCheckContext{
-		http:    httpc,
-		baseURL: strings.TrimRight(leader, "/"),
-		byTag:   urlByTag,
-	}
+func NewChecks(rc *ravendb.Client) *HealthCheckContext {
+	return &HealthCheckContext{rc: rc}
 }
 
-func (g *HealthCheckContext) urlForTag(tag string) string {
-	return g.byTag[strings.ToUpper(tag)]
+// WithPods lets the checks look at the pods of the cluster's nodes
+func (hcc *HealthCheckContext) WithPods(kc client.Client, c *ravendbv1.RavenDBCluster) *HealthCheckContext {
+	hcc.kc = kc
+	hcc.cluster = c
+	return hcc
 }
@@ -0,0 +1,233 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/ravendb"
+	"ravendb-operator/pkg/ravendb/fake"
+)
+
+func ordersOn(status map[string]ravendb.DatabaseNodeStatus) ravendb.Database {
+	return ravendb.Database{
+		Name:              "orders",
+		ReplicationFactor: 2,
+		NodesTopology: ravendb.DatabaseTopology{
+			Members: []ravendb.DatabaseNode{{NodeTag: "A"}, {NodeTag: "B"}},
+			Status:  status,
+		},
+	}
+}
+
+func Test_G1_DatabasesOnlineExcludingTarget(t *testing.T) {
+	srv := fake.New("A", "B", "C")
+	hcc := NewChecks(srv.Client())
+
+	srv.SetDatabases(ordersOn(map[string]ravendb.DatabaseNodeStatus{"A": {LastStatus: "Ok"}, "B": {LastStatus: "Ok"}}))
+	ok, _, err := hcc.DatabasesOnline(context.Background(), "A")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	srv.SetDatabases(ordersOn(map[string]ravendb.DatabaseNodeStatus{"A": {LastStatus: "Ok"}, "B": {LastError: "connection refused"}}))
+	ok, info, err := hcc.DatabasesOnline(context.Background(), "A")
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Contains(t, info, "db=orders")
+}
+
+func Test_G2_UnreachableClusterIsSoft(t *testing.T) {
+	srv := fake.New("A")
+	srv.SetDown("A", true)
+	hcc := NewChecks(srv.Client())
+
+	ok, info, err := hcc.NodeAlive(context.Background(), "A")
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Contains(t, info, "connection refused")
+
+	_, _, err = hcc.NodeAlive(context.Background(), "Z")
+	require.ErrorIs(t, err, ravendb.ErrUnknownNode)
+}
+
+func Test_G3_LeaderStepDown(t *testing.T) {
+	srv := fake.New("A", "B")
+	hcc := NewChecks(srv.Client())
+	gate := newLeaderStepDownGate()
+
+	ok, _, err := gate.Check(context.Background(), hcc, GateRequest{Tag: "B"})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, info, err := gate.Check(context.Background(), hcc, GateRequest{Tag: "A"})
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Contains(t, info, "asked leader A to step down")
+
+	leader, _ := srv.Leader()
+	require.Equal(t, "B", leader)
+}
+
+func Test_G4_NoLeaderElected(t *testing.T) {
+	srv := fake.New("A", "B")
+	srv.SetRole("A", "")
+	srv.SetRole("B", "")
+	hcc := NewChecks(srv.Client())
+
+	ok, info, err := newLeaderStableGate().Check(context.Background(), hcc, GateRequest{Cluster: "c", Tag: "A"})
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, "no leader elected", info)
+}
+
+func Test_G5_PodReadyWaitsForTheNewRevision(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+
+	c := &ravendbv1.RavenDBCluster{ObjectMeta: metav1.ObjectMeta{Name: "ravendb", Namespace: "db"}}
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "ravendb-a", Namespace: "db", Generation: 2},
+		Status:     appsv1.StatefulSetStatus{ObservedGeneration: 2, UpdateRevision: "new"},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "ravendb-a-0", Namespace: "db", Labels: map[string]string{appsv1.ControllerRevisionHashLabelKey: "old"}},
+		Status:     corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}},
+	}
+	kc := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(sts, pod).Build()
+	hcc := NewChecks(fake.New("A").Client()).WithPods(kc, c)
+
+	ok, _, err := podReadyGate{}.Check(context.Background(), hcc, GateRequest{Phase: GatePreStep, Tag: "A"})
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, info, err := podReadyGate{}.Check(context.Background(), hcc, GateRequest{Phase: GatePostStep, Tag: "A"})
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, "pod ravendb-a-0 still on revision old", info)
+
+	pod.Labels[appsv1.ControllerRevisionHashLabelKey] = "new"
+	require.NoError(t, kc.Update(context.Background(), pod))
+	ok, _, err = podReadyGate{}.Check(context.Background(), hcc, GateRequest{Phase: GatePostStep, Tag: "A"})
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func Test_G6_PodReadyFailsWithoutPods(t *testing.T) {
+	ok, info, err := podReadyGate{}.Check(context.Background(), NewChecks(fake.New("A").Client()), GateRequest{Phase: GatePreStep, Tag: "A"})
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, "pod checks need WithPods", info)
+}
+
+func Test_G7_LeaderStableForAPeriod(t *testing.T) {
+	srv := fake.New("A", "B", "C")
+	hcc := NewChecks(srv.Client())
+	gate := newLeaderStableGate()
+	req := GateRequest{Cluster: "ravendb/db", Tag: "B", Params: map[string]string{paramStablePeriod: "50ms"}}
+
+	ok, info, err := gate.Check(context.Background(), hcc, req)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, "leader A (term 1) stable for 0s of 50ms", info)
+
+	time.Sleep(60 * time.Millisecond)
+	ok, _, err = gate.Check(context.Background(), hcc, req)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// a new election starts the period again
+	srv.SetLeader("C")
+	ok, info, err = gate.Check(context.Background(), hcc, req)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Contains(t, info, "leader C (term 2)")
+
+	_, _, err = gate.Check(context.Background(), hcc, GateRequest{Params: map[string]string{paramStablePeriod: "soon"}})
+	require.EqualError(t, err, `param stablePeriod="soon" must be a positive duration`)
+}
+
+func Test_G8_LeaderStepDownAsksOncePerTerm(t *testing.T) {
+	srv := fake.New("A", "B")
+	srv.SetDown("B", true) // nobody to hand the leadership to yet
+	hcc := NewChecks(srv.Client())
+	gate := newLeaderStepDownGate()
+	req := GateRequest{ClusterUID: "uid-1", Tag: "A"}
+
+	reelects := func() int {
+		n := 0
+		for _, r := range srv.Requests() {
+			if r == "A POST /admin/cluster/reelect" {
+				n++
+			}
+		}
+		return n
+	}
+
+	_, info, err := gate.Check(context.Background(), hcc, req)
+	require.NoError(t, err)
+	require.Equal(t, "asked leader A to step down (term 1)", info)
+
+	ok, info, err := gate.Check(context.Background(), hcc, req)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Equal(t, "waiting for leader A to step down (asked in term 1)", info)
+	require.Equal(t, 1, reelects())
+
+	// A won the next election again
+	srv.SetLeader("A")
+	_, info, err = gate.Check(context.Background(), hcc, req)
+	require.NoError(t, err)
+	require.Equal(t, "asked leader A to step down (term 2)", info)
+	require.Equal(t, 2, reelects())
+
+	srv.SetLeader("B")
+	ok, _, err = gate.Check(context.Background(), hcc, req)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Empty(t, gate.asked)
+}
+
+func Test_G9_LeaderStateExpires(t *testing.T) {
+	srv := fake.New("A", "B")
+	hcc := NewChecks(srv.Client())
+	gate := newLeaderStableGate()
+
+	_, _, err := gate.Check(context.Background(), hcc, GateRequest{ClusterUID: "uid-1", Tag: "B"})
+	require.NoError(t, err)
+	require.Contains(t, gate.seen, "uid-1")
+
+	// the rollout of uid-1 finished an hour ago, another cluster's rollout drops it
+	o := gate.seen["uid-1"]
+	o.checked = o.checked.Add(-leaderStateTTL - time.Minute)
+	gate.seen["uid-1"] = o
+
+	_, _, err = gate.Check(context.Background(), hcc, GateRequest{ClusterUID: "uid-2", Tag: "B"})
+	require.NoError(t, err)
+	require.NotContains(t, gate.seen, "uid-1")
+	require.Contains(t, gate.seen, "uid-2")
+}
@@ -17,47 +17,20 @@ limitations under the License.
 package upgrade
 
 import (
-	"context"
 	"errors"
-	"fmt"
-	"io"
-	"net/http"
 	"strings"
-)
-
-type pingItem struct {
-	Url        string
-	SetupAlive struct{ Error string }
-	TcpInfo    struct{ Error string }
-}
-
-type pingResponse struct {
-	Result []pingItem
-}
-
-func (hcc *HealthCheckContext) httpGET(ctx context.Context, rawURL string) (int, string, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
-	if err != nil {
-		return 0, "", err
-	}
-	resp, err := hcc.http.Do(req)
-	if err != nil {
-		return 0, "", err
-	}
-	defer resp.Body.Close()
 
-	body, rerr := io.ReadAll(resp.Body)
-	if rerr != nil {
-		return resp.StatusCode, "", rerr
-	}
-	return resp.StatusCode, string(body), nil
-}
+	"ravendb-operator/pkg/ravendb"
+)
 
-func (hcc *HealthCheckContext) clusterURL() (string, error) {
-	if hcc.baseURL == "" {
-		return "", errors.New("baseURL is empty")
+// softInfo turns the error of a request into the info message of a failed check: a node that is down
+// or answers with an error is what the gates wait out. only a cluster the client can't address at all
+// is a hard error.
+func softInfo(err error) (string, error) {
+	if errors.Is(err, ravendb.ErrUnknownNode) || errors.Is(err, ravendb.ErrNoNodes) {
+		return "", err
 	}
-	return hcc.baseURL, nil
+	return err.Error(), nil
 }
 
 func collapseWhitespace(s string) string { return strings.Join(strings.Fields(s), " ") }
@@ -77,13 +50,6 @@ func summarizeError(s string) string {
 	return s
 }
 
-func join(base, path string) (string, error) {
-	if base == "" {
-		return "", fmt.Errorf("empty base url")
-	}
-	return strings.TrimRight(base, "/") + "/" + strings.TrimLeft(path, "/"), nil
-}
-
 func truncate(s string, n int) string {
 	if len(s) <= n {
 		return s
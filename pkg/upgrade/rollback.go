@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/version"
+)
+
+// with spec.upgrade.rollbackPolicy=Automatic a node which post gates failed goes back to the image it ran
+// before the rollout and runs its post gates again:
+//
+//	PostGates(failed) -> Applying(previous image) -> PostGates(...) -> rollout RolledBack
+//
+// the rollout then stays RolledBack until the cluster spec changes.
+
+func rollingBack(st *ravendbv1.UpgradeStatus) bool {
+	if st == nil || st.CurrentNode == "" {
+		return false
+	}
+	i := planIndex(st, st.CurrentNode)
+	return i >= 0 && st.Plan[i].Phase == ravendbv1.UpgradeNodeRollingBack
+}
+
+// rolledBack tells whether the rollout to desiredImg was rolled back for the current spec
+func rolledBack(st *ravendbv1.UpgradeStatus, desiredImg string, generation int64) bool {
+	return st != nil && st.Phase == ravendbv1.UpgradePhaseRolledBack &&
+		st.TargetImage == desiredImg && st.ObservedGeneration == generation
+}
+
+// startRollback hands the previous image to the node's StatefulSet. it returns false (and why, as error)
+// when the policy does not allow a rollback for this node.
+func (u *upgrader) startRollback(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster, res *RunResult, tag, desiredImg string, cause error) (bool, error) {
+	if c.EffectiveRollbackPolicy() != ravendbv1.RollbackPolicyAutomatic {
+		return false, nil
+	}
+
+	st := res.Upgrade
+	i := planIndex(st, tag)
+	if i < 0 || st.Plan[i].FromImage == "" {
+		return false, fmt.Errorf("automatic rollback skipped: previous image of node %s is unknown", tag)
+	}
+	fromImg := st.Plan[i].FromImage
+
+	if ok, why := version.DataFormatCompatible(fromImg, desiredImg); !ok {
+		return false, fmt.Errorf("automatic rollback skipped: %s", why)
+	}
+
+	if err := u.setRollbackAnnotation(ctx, kc, c, tag, fromImg); err != nil {
+		_ = u.setRollbackAnnotation(ctx, kc, c, tag, "")
+		return false, fmt.Errorf("automatic rollback skipped: set rollback annotation: %w", err)
+	}
+	_ = u.setUpgradeAnnotation(ctx, kc, c, tag, "")
+
+	setNodeStatus(res.Nodes, failedStatus(tag, fmt.Sprintf("rolling back to %s: %v", fromImg, cause), desiredImg))
+	markPlanned(st, tag, ravendbv1.UpgradeNodeRollingBack)
+	st.Step = ravendbv1.UpgradeStepApplying
+	st.GateMessage = summarizeError(cause.Error())
+	return true, nil
+}
+
+// finishRollback ends the rollout once the rolled back node passed (or failed) its gates again.
+// the marker is removed in both cases: the StatefulSet already runs the previous image and the freeze keeps it there.
+func (u *upgrader) finishRollback(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster, res RunResult, tag, desiredImg string, err error) (RunResult, error) {
+	_ = u.setRollbackAnnotation(ctx, kc, c, tag, "")
+
+	st := res.Upgrade
+	if err != nil {
+		setNodeStatus(res.Nodes, failedStatus(tag, err.Error(), desiredImg))
+		endNode(st, ravendbv1.UpgradeNodeFailed)
+	} else {
+		if i := planIndex(st, tag); i >= 0 {
+			setNodeStatus(res.Nodes, failedStatus(tag, fmt.Sprintf("upgrade to %s rolled back to %s", desiredImg, st.Plan[i].FromImage), desiredImg))
+		}
+		endNode(st, ravendbv1.UpgradeNodeRolledBack)
+	}
+
+	now := timestampNow()
+	st.Phase = ravendbv1.UpgradePhaseRolledBack
+	st.CompletionTime = &now
+	st.ObservedGeneration = c.Generation
+	res.RequeueAfter = 0
+	return res, err
+}
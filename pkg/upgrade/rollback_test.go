@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package upgrade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+)
+
+// newRollbackEnv fails the post gates of a node on newImage right away, healthy tells whether the node
+// passes them on its current image
+func newRollbackEnv(t *testing.T, policy ravendbv1.RollbackPolicy, healthy func(img string) bool) *rolloutEnv {
+	e := newRolloutEnv(t)
+	e.c.Spec.Upgrade = &ravendbv1.UpgradeSpec{RollbackPolicy: policy}
+	e.u.SetGates(NewGateRegistry(
+		passing(GatePodReady, GatePreStep),
+		stubGate{kind: GateNodeAlive, phases: []GatePhase{GatePostStep}, check: func(req GateRequest) (bool, string, error) {
+			return healthy(e.image(req.Tag)), "node not alive", nil
+		}},
+	))
+	e.u.timing.PostMaxWait = time.Nanosecond
+	return e
+}
+
+func Test_RB1_FailedNodeGoesBackToItsPreviousImage(t *testing.T) {
+	e := newRollbackEnv(t, ravendbv1.RollbackPolicyAutomatic, func(img string) bool { return img == oldImage })
+
+	res, err := e.tick()
+	require.NoError(t, err)
+	require.Zero(t, res.RequeueAfter)
+	require.Equal(t, []string{"B", "B"}, e.applied)
+	require.Equal(t, oldImage, e.image("B"))
+
+	st := e.c.Status.Upgrade
+	require.Equal(t, ravendbv1.UpgradePhaseRolledBack, st.Phase)
+	require.NotNil(t, st.CompletionTime)
+	require.Empty(t, st.CurrentNode)
+	require.Equal(t, ravendbv1.UpgradeNodeRolledBack, e.planPhase("B"))
+	require.Equal(t, ravendbv1.UpgradeNodePending, e.planPhase("C"))
+	require.Equal(t, ravendbv1.NodeStatusFailed, e.c.Status.Nodes[1].Status)
+	require.Contains(t, e.c.Status.Nodes[1].LastError, "rolled back to "+oldImage)
+
+	sts := e.sts("B")
+	require.NotContains(t, sts.Annotations, common.UpgradeImageAnnotation)
+	require.NotContains(t, sts.Annotations, common.RollbackImageAnnotation)
+
+	// nothing else is touched until the spec changes
+	res, err = e.tick()
+	require.NoError(t, err)
+	require.Zero(t, res.RequeueAfter)
+	require.Len(t, e.applied, 2)
+
+	e.c.Generation++
+	e.u.timing.PostMaxWait = time.Minute
+	e.u.SetGates(NewGateRegistry(passing(GatePodReady, GatePreStep), passing(GateNodeAlive, GatePostStep)))
+	_, err = e.tick()
+	require.NoError(t, err)
+	require.Equal(t, ravendbv1.UpgradePhaseRunning, e.c.Status.Upgrade.Phase)
+	require.Equal(t, newImage, e.image("B"))
+}
+
+func Test_RB2_RollbackWhichGatesFailToo(t *testing.T) {
+	e := newRollbackEnv(t, ravendbv1.RollbackPolicyAutomatic, func(string) bool { return false })
+
+	res, err := e.tick()
+	require.ErrorContains(t, err, "post-rollback gates failed for B")
+	require.Zero(t, res.RequeueAfter)
+	require.Equal(t, oldImage, e.image("B"))
+	require.Equal(t, ravendbv1.UpgradePhaseRolledBack, e.c.Status.Upgrade.Phase)
+	require.Equal(t, ravendbv1.UpgradeNodeFailed, e.planPhase("B"))
+	require.NotContains(t, e.sts("B").Annotations, common.RollbackImageAnnotation)
+}
+
+func Test_RB3_NoRollback(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  ravendbv1.RollbackPolicy
+		from    string
+		wantErr string
+	}{
+		{"policy None", ravendbv1.RollbackPolicyNone, oldImage, "post-node gates failed for B"},
+		{"data format changed", ravendbv1.RollbackPolicyAutomatic, "ravendb/ravendb:6.0.108", "automatic rollback skipped: v6.0 -> v6.2 changes the data format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newRollbackEnv(t, tt.policy, func(string) bool { return false })
+			sts := e.sts("B")
+			sts.Spec.Template.Spec.Containers[0].Image = tt.from
+			require.NoError(t, e.kc.Update(context.Background(), sts))
+
+			res, err := e.tick()
+			require.ErrorContains(t, err, tt.wantErr)
+			require.Equal(t, failedRetryInterval, res.RequeueAfter)
+			require.Equal(t, []string{"B"}, e.applied)
+			require.Equal(t, newImage, e.image("B"))
+			require.Equal(t, ravendbv1.UpgradePhaseRunning, e.c.Status.Upgrade.Phase)
+			require.Equal(t, ravendbv1.UpgradeNodeFailed, e.planPhase("B"))
+			require.NotContains(t, e.sts("B").Annotations, common.RollbackImageAnnotation)
+		})
+	}
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"context"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ravendbv1 "ravendb-operator/api/v1"
+)
+
+// a rollout is the whole rolling upgrade from one image to another.
+// status.upgrade keeps describing it after it finished, so kubectl/dashboards can show the last one.
+
+// a rollout is active while it is running or paused, and only for the image it was planned for
+func rolloutActive(st *ravendbv1.UpgradeStatus, desiredImg string) bool {
+	if st == nil || st.TargetImage != desiredImg {
+		return false
+	}
+	return st.Phase == ravendbv1.UpgradePhaseRunning || st.Phase == ravendbv1.UpgradePhasePaused
+}
+
+// newRollout plans an upgrade of every node which StatefulSet is not on the desired image yet,
+// in spec order with the Raft leader (when known) moved last
+func (u *upgrader) newRollout(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster, desiredImg, leader string) (*ravendbv1.UpgradeStatus, error) {
+	now := timestampNow()
+	st := &ravendbv1.UpgradeStatus{
+		Phase:              ravendbv1.UpgradePhaseRunning,
+		TargetImage:        desiredImg,
+		StartTime:          &now,
+		ObservedGeneration: c.Generation,
+	}
+
+	for _, n := range c.Spec.Nodes {
+		sts, exists, err := u.loadSTSByNodeTag(ctx, kc, c, n.Tag)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+		cur := currentStsImage(sts)
+		if cur == "" || cur == desiredImg {
+			continue
+		}
+		if st.SourceImage == "" {
+			st.SourceImage = cur
+		}
+		st.Plan = append(st.Plan, ravendbv1.UpgradeNodeProgress{Tag: normalizeTag(n.Tag), FromImage: cur, Phase: ravendbv1.UpgradeNodePending})
+	}
+
+	if i := planIndex(st, leader); leader != "" && i >= 0 {
+		p := st.Plan[i]
+		st.Plan = append(append(st.Plan[:i:i], st.Plan[i+1:]...), p)
+	}
+	return st, nil
+}
+
+// leaderTag returns the current Raft leader, "" when it can't be determined right now
+func (u *upgrader) leaderTag(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster) string {
+	hcc, err := u.buildGates(ctx, kc, c)
+	if err != nil {
+		return ""
+	}
+	cctx, cancel := context.WithTimeout(ctx, gateCheckTimeout)
+	defer cancel()
+
+	t, _, err := hcc.ClusterTopology(cctx)
+	if err != nil || t == nil {
+		return ""
+	}
+	return normalizeTag(t.Leader)
+}
+
+// nextPlanned returns the first node of the plan which still has to be upgraded.
+// nodes that were removed from the spec are dropped from the plan, nodes that already run the
+// desired image (e.g. the StatefulSet was edited by hand) are marked Completed.
+// the current leader is skipped while any follower is left, leadership may move during a rollout.
+func (u *upgrader) nextPlanned(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster, st *ravendbv1.UpgradeStatus, desiredImg, leader string) (string, error) {
+	plan := st.Plan[:0]
+	for _, p := range st.Plan {
+		if _, ok := findNode(c, p.Tag); ok {
+			plan = append(plan, p)
+		}
+	}
+	st.Plan = plan
+
+	next := ""
+	for i := range st.Plan {
+		p := &st.Plan[i]
+		if p.Phase == ravendbv1.UpgradeNodeCompleted {
+			continue
+		}
+		sts, exists, err := u.loadSTSByNodeTag(ctx, kc, c, p.Tag)
+		if err != nil {
+			return "", err
+		}
+		if exists && currentStsImage(sts) == desiredImg {
+			markPlanned(st, p.Tag, ravendbv1.UpgradeNodeCompleted)
+			continue
+		}
+		if !strings.EqualFold(p.Tag, leader) {
+			return p.Tag, nil
+		}
+		next = p.Tag
+	}
+	return next, nil
+}
+
+// hold keeps a rollout from starting its next node while spec.upgrade.control is Pause or Abort.
+// a paused rollout keeps its plan and resumes from it, an aborted one is over and the remaining
+// nodes stay on their current image (a later Proceed plans a new rollout).
+func (u *upgrader) hold(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster, desiredImg string, control ravendbv1.UpgradeControl, res *RunResult) error {
+	switch control {
+	case ravendbv1.UpgradeControlPause:
+		if !rolloutActive(res.Upgrade, desiredImg) {
+			// show what is going to be upgraded once resumed
+			rollout, err := u.newRollout(ctx, kc, c, desiredImg, u.leaderTag(ctx, kc, c))
+			if err != nil {
+				return err
+			}
+			res.Upgrade = rollout
+		}
+		res.Upgrade.Phase = ravendbv1.UpgradePhasePaused
+		res.Upgrade.Control = control
+
+	case ravendbv1.UpgradeControlAbort:
+		if rolloutActive(res.Upgrade, desiredImg) {
+			now := timestampNow()
+			res.Upgrade.Phase = ravendbv1.UpgradePhaseAborted
+			res.Upgrade.CompletionTime = &now
+			res.Upgrade.Control = control
+		}
+	}
+	return nil
+}
+
+// beginNode makes tag the current node of the rollout. fromImg is what the node runs now.
+func beginNode(st *ravendbv1.UpgradeStatus, tag, fromImg string) {
+	st.CurrentNode = normalizeTag(tag)
+	if planIndex(st, tag) < 0 {
+		st.Plan = append(st.Plan, ravendbv1.UpgradeNodeProgress{Tag: normalizeTag(tag)})
+	}
+	if p := &st.Plan[planIndex(st, tag)]; p.FromImage == "" && fromImg != "" && fromImg != st.TargetImage {
+		p.FromImage = fromImg
+	}
+	markPlanned(st, tag, ravendbv1.UpgradeNodeUpgrading)
+}
+
+// endNode records the result of the current node and leaves the rollout idle between nodes
+func endNode(st *ravendbv1.UpgradeStatus, phase ravendbv1.UpgradeNodePhase) {
+	markPlanned(st, st.CurrentNode, phase)
+	st.CurrentNode = ""
+	st.Step = ""
+	st.GatePhase = ""
+	st.GateKind = ""
+	st.GateMessage = ""
+	resetGate(st)
+}
+
+// completeRollout finishes a running (or paused) rollout once no node is left to upgrade
+func completeRollout(st *ravendbv1.UpgradeStatus) {
+	if st == nil || st.CurrentNode != "" {
+		return
+	}
+	if st.Phase != ravendbv1.UpgradePhaseRunning && st.Phase != ravendbv1.UpgradePhasePaused {
+		return
+	}
+	for _, p := range st.Plan {
+		if p.Phase != ravendbv1.UpgradeNodeCompleted {
+			return
+		}
+	}
+	now := timestampNow()
+	st.Phase = ravendbv1.UpgradePhaseCompleted
+	st.CompletionTime = &now
+}
+
+func markPlanned(st *ravendbv1.UpgradeStatus, tag string, phase ravendbv1.UpgradeNodePhase) {
+	i := planIndex(st, tag)
+	if i < 0 {
+		return
+	}
+	now := timestampNow()
+	p := &st.Plan[i]
+	p.Phase = phase
+	switch phase {
+	case ravendbv1.UpgradeNodePending:
+		p.StartTime = nil
+		p.CompletionTime = nil
+	case ravendbv1.UpgradeNodeUpgrading:
+		p.StartTime = &now
+		p.CompletionTime = nil
+	case ravendbv1.UpgradeNodeCompleted, ravendbv1.UpgradeNodeFailed:
+		p.CompletionTime = &now
+	}
+}
+
+func planIndex(st *ravendbv1.UpgradeStatus, tag string) int {
+	for i := range st.Plan {
+		if strings.EqualFold(st.Plan[i].Tag, tag) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,296 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package upgrade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+)
+
+func planTags(st *ravendbv1.UpgradeStatus) []string {
+	tags := []string{}
+	for _, p := range st.Plan {
+		tags = append(tags, p.Tag)
+	}
+	return tags
+}
+
+func Test_RO1_StatusDescribesTheRollout(t *testing.T) {
+	pre := blocking(GatePodReady, GatePreStep, "pod not ready")
+	e := newRolloutEnv(t, pre, passing(GateNodeAlive, GatePostStep))
+
+	_, err := e.tick()
+	require.NoError(t, err)
+	st := e.c.Status.Upgrade
+	require.Equal(t, ravendbv1.UpgradePhaseRunning, st.Phase)
+	require.Equal(t, ravendbv1.UpgradeControlProceed, st.Control)
+	require.Equal(t, oldImage, st.SourceImage)
+	require.Equal(t, newImage, st.TargetImage)
+	require.Equal(t, int64(1), st.ObservedGeneration)
+	require.NotNil(t, st.StartTime)
+	require.Nil(t, st.CompletionTime)
+	require.Equal(t, []string{"B", "C", "A"}, planTags(st))
+
+	require.Equal(t, "B", st.CurrentNode)
+	require.Equal(t, ravendbv1.UpgradeStepPreGates, st.Step)
+	require.Equal(t, string(GatePreStep), st.GatePhase)
+	require.Equal(t, string(GatePodReady), st.GateKind)
+	require.Equal(t, "pod not ready", st.GateMessage)
+	require.NotNil(t, st.GateStartTime)
+
+	b := st.Plan[0]
+	require.Equal(t, ravendbv1.UpgradeNodeUpgrading, b.Phase)
+	require.Equal(t, oldImage, b.FromImage)
+	require.NotNil(t, b.StartTime)
+	require.Nil(t, b.CompletionTime)
+	for _, p := range st.Plan[1:] {
+		require.Equal(t, ravendbv1.UpgradeNodePending, p.Phase)
+		require.Nil(t, p.StartTime)
+	}
+
+	*pre.pass = true
+	_, err = e.tick()
+	require.NoError(t, err)
+	st = e.c.Status.Upgrade
+	require.Empty(t, st.CurrentNode)
+	require.Empty(t, st.Step)
+	require.Empty(t, st.GateKind)
+	require.Empty(t, st.GateMessage)
+	require.Equal(t, ravendbv1.UpgradeNodeCompleted, st.Plan[0].Phase)
+	require.NotNil(t, st.Plan[0].CompletionTime)
+	require.Equal(t, ravendbv1.NodeStatusCreated, e.c.Status.Nodes[1].Status)
+	require.Equal(t, newImage, e.c.Status.Nodes[1].LastAttemptedImage)
+}
+
+func Test_RO2_CompletedRolloutIsKept(t *testing.T) {
+	e := newRolloutEnv(t, passing(GatePodReady, GatePreStep), passing(GateNodeAlive, GatePostStep))
+	for i := 0; i < 4; i++ {
+		_, err := e.tick()
+		require.NoError(t, err)
+	}
+
+	st := e.c.Status.Upgrade
+	require.Equal(t, ravendbv1.UpgradePhaseCompleted, st.Phase)
+	require.NotNil(t, st.CompletionTime)
+	require.Equal(t, oldImage, st.SourceImage)
+	require.Equal(t, newImage, st.TargetImage)
+	for _, p := range st.Plan {
+		require.Equal(t, ravendbv1.UpgradeNodeCompleted, p.Phase)
+		require.False(t, p.CompletionTime.Before(p.StartTime))
+	}
+
+	// further ticks leave the finished rollout alone
+	completed := st.CompletionTime
+	res, err := e.tick()
+	require.NoError(t, err)
+	require.Zero(t, res.RequeueAfter)
+	require.Equal(t, completed, e.c.Status.Upgrade.CompletionTime)
+}
+
+func (e *rolloutEnv) setControl(control ravendbv1.UpgradeControl) {
+	e.c.Spec.Upgrade = &ravendbv1.UpgradeSpec{Control: control}
+}
+
+func Test_RO3_PauseHoldsBeforeTheNextNode(t *testing.T) {
+	e := newRolloutEnv(t, passing(GatePodReady, GatePreStep), passing(GateNodeAlive, GatePostStep))
+	e.setControl(ravendbv1.UpgradeControlPause)
+
+	// paused before the rollout started: the plan is shown, nothing is touched
+	res, err := e.tick()
+	require.NoError(t, err)
+	require.Zero(t, res.RequeueAfter)
+	st := e.c.Status.Upgrade
+	require.Equal(t, ravendbv1.UpgradePhasePaused, st.Phase)
+	require.Equal(t, ravendbv1.UpgradeControlPause, st.Control)
+	require.Equal(t, []string{"B", "C", "A"}, planTags(st))
+	require.Empty(t, st.CurrentNode)
+	require.Empty(t, e.applied)
+
+	e.setControl(ravendbv1.UpgradeControlProceed)
+	_, err = e.tick()
+	require.NoError(t, err)
+	require.Equal(t, []string{"B"}, e.applied)
+	require.Equal(t, ravendbv1.UpgradePhaseRunning, e.c.Status.Upgrade.Phase)
+
+	// paused between nodes: C and A stay on the old image
+	e.setControl(ravendbv1.UpgradeControlPause)
+	for i := 0; i < 2; i++ {
+		_, err = e.tick()
+		require.NoError(t, err)
+	}
+	require.Equal(t, []string{"B"}, e.applied)
+	require.Equal(t, ravendbv1.UpgradePhasePaused, e.c.Status.Upgrade.Phase)
+	require.Equal(t, oldImage, e.image("C"))
+
+	// resuming continues the same plan
+	e.setControl(ravendbv1.UpgradeControlProceed)
+	for i := 0; i < 3; i++ {
+		_, err = e.tick()
+		require.NoError(t, err)
+	}
+	require.Equal(t, []string{"B", "C", "A"}, e.applied)
+	require.Equal(t, ravendbv1.UpgradePhaseCompleted, e.c.Status.Upgrade.Phase)
+}
+
+func Test_RO4_PauseDuringTheGates(t *testing.T) {
+	pre := blocking(GatePodReady, GatePreStep, "pod not ready")
+	post := blocking(GateNodeAlive, GatePostStep, "node not alive")
+	e := newRolloutEnv(t, pre, post)
+
+	// a node in its pre gates still runs the old image and goes back to the plan
+	_, err := e.tick()
+	require.NoError(t, err)
+	require.Equal(t, "B", e.c.Status.Upgrade.CurrentNode)
+
+	e.setControl(ravendbv1.UpgradeControlPause)
+	_, err = e.tick()
+	require.NoError(t, err)
+	st := e.c.Status.Upgrade
+	require.Equal(t, ravendbv1.UpgradePhasePaused, st.Phase)
+	require.Empty(t, st.CurrentNode)
+	require.Equal(t, ravendbv1.UpgradeNodePending, e.planPhase("B"))
+	require.Empty(t, e.applied)
+
+	// a node which image was already changed finishes its post gates while paused
+	e.setControl(ravendbv1.UpgradeControlProceed)
+	*pre.pass = true
+	_, err = e.tick()
+	require.NoError(t, err)
+	require.Equal(t, []string{"B"}, e.applied)
+	require.Equal(t, ravendbv1.UpgradeStepPostGates, e.c.Status.Upgrade.Step)
+
+	e.setControl(ravendbv1.UpgradeControlPause)
+	_, err = e.tick()
+	require.NoError(t, err)
+	require.Equal(t, "B", e.c.Status.Upgrade.CurrentNode)
+	require.Contains(t, e.sts("B").Annotations, common.UpgradeImageAnnotation)
+
+	*post.pass = true
+	_, err = e.tick()
+	require.NoError(t, err)
+	require.Equal(t, ravendbv1.UpgradeNodeCompleted, e.planPhase("B"))
+
+	_, err = e.tick()
+	require.NoError(t, err)
+	require.Equal(t, ravendbv1.UpgradePhasePaused, e.c.Status.Upgrade.Phase)
+	require.Equal(t, []string{"B"}, e.applied)
+}
+
+func Test_RO5_AbortLeavesTheRemainingNodes(t *testing.T) {
+	e := newRolloutEnv(t, passing(GatePodReady, GatePreStep), passing(GateNodeAlive, GatePostStep))
+
+	_, err := e.tick()
+	require.NoError(t, err)
+
+	e.setControl(ravendbv1.UpgradeControlAbort)
+	for i := 0; i < 2; i++ {
+		res, err := e.tick()
+		require.NoError(t, err)
+		require.Zero(t, res.RequeueAfter)
+	}
+	st := e.c.Status.Upgrade
+	require.Equal(t, ravendbv1.UpgradePhaseAborted, st.Phase)
+	require.Equal(t, ravendbv1.UpgradeControlAbort, st.Control)
+	require.NotNil(t, st.CompletionTime)
+	require.Equal(t, []string{"B"}, e.applied)
+	require.Equal(t, oldImage, e.image("C"))
+	require.Equal(t, oldImage, e.image("A"))
+
+	// Proceed plans a new rollout of what is left
+	e.setControl(ravendbv1.UpgradeControlProceed)
+	_, err = e.tick()
+	require.NoError(t, err)
+	st = e.c.Status.Upgrade
+	require.Equal(t, ravendbv1.UpgradePhaseRunning, st.Phase)
+	require.Equal(t, []string{"C", "A"}, planTags(st))
+	require.Equal(t, []string{"B", "C"}, e.applied)
+}
+
+func Test_RO6_LeaderIsUpgradedLast(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(e *rolloutEnv)
+		wantPlan []string
+	}{
+		{"leader first in spec", func(e *rolloutEnv) {}, []string{"B", "C", "A"}},
+		{"leader in the middle", func(e *rolloutEnv) { e.srv.SetLeader("B") }, []string{"A", "C", "B"}},
+		{"leader unknown", func(e *rolloutEnv) {
+			for _, tag := range []string{"A", "B", "C"} {
+				e.srv.SetDown(tag, true)
+			}
+		}, []string{"A", "B", "C"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newRolloutEnv(t, passing(GatePodReady, GatePreStep), passing(GateNodeAlive, GatePostStep))
+			tt.setup(e)
+			for i := 0; i < 3; i++ {
+				_, err := e.tick()
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantPlan, planTags(e.c.Status.Upgrade))
+			require.Equal(t, tt.wantPlan, e.applied)
+		})
+	}
+}
+
+func Test_RO7_LeadershipMovesDuringTheRollout(t *testing.T) {
+	e := newRolloutEnv(t, passing(GatePodReady, GatePreStep), passing(GateNodeAlive, GatePostStep))
+
+	_, err := e.tick()
+	require.NoError(t, err)
+	require.Equal(t, []string{"B"}, e.applied)
+
+	// C took over, A is a follower now and goes before it
+	e.srv.SetLeader("C")
+	for i := 0; i < 2; i++ {
+		_, err = e.tick()
+		require.NoError(t, err)
+	}
+	require.Equal(t, []string{"B", "A", "C"}, e.applied)
+	require.Equal(t, []string{"B", "C", "A"}, planTags(e.c.Status.Upgrade))
+}
+
+func Test_RO8_NextPlannedSkipsFinishedAndRemovedNodes(t *testing.T) {
+	e := newRolloutEnv(t)
+	st, err := e.u.newRollout(context.Background(), e.kc, e.c, newImage, "A")
+	require.NoError(t, err)
+	require.Equal(t, []string{"B", "C", "A"}, planTags(st))
+
+	// B was upgraded by hand, C left the spec
+	sts := e.sts("B")
+	sts.Spec.Template.Spec.Containers[0].Image = newImage
+	require.NoError(t, e.kc.Update(context.Background(), sts))
+	e.c.Spec.Nodes = []ravendbv1.RavenDBNode{e.c.Spec.Nodes[0], e.c.Spec.Nodes[1]}
+
+	next, err := e.u.nextPlanned(context.Background(), e.kc, e.c, st, newImage, "A")
+	require.NoError(t, err)
+	require.Equal(t, "A", next)
+	require.Equal(t, []string{"B", "A"}, planTags(st))
+	require.Equal(t, ravendbv1.UpgradeNodeCompleted, st.Plan[0].Phase)
+
+	// the leader is only picked once no follower is left
+	markPlanned(st, "A", ravendbv1.UpgradeNodeCompleted)
+	next, err = e.u.nextPlanned(context.Background(), e.kc, e.c, st, newImage, "A")
+	require.NoError(t, err)
+	require.Empty(t, next)
+}
@@ -18,9 +18,9 @@ package upgrade
 
 import (
 	"context"
-	"fmt"
 	ravendbv1 "ravendb-operator/api/v1"
 	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/naming"
 	"strings"
 	"time"
 
@@ -77,13 +77,22 @@ func successStatus(tag, desired string) ravendbv1.RavenDBNodeStatus {
 	}
 }
 
-func statefulSetName(tag string) string {
-	return fmt.Sprintf("%s%s", common.Prefix, strings.ToLower(tag))
+func statefulSetName(c *ravendbv1.RavenDBCluster, tag string) string {
+	return naming.For(c).Node(tag)
 }
 
 // toggles the per-node STS annotation so the actor switches the image
 func (u *upgrader) setUpgradeAnnotation(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster, tag, value string) error {
-	stsName := statefulSetName(tag)
+	return u.setSTSAnnotation(ctx, kc, c, tag, common.UpgradeImageAnnotation, value)
+}
+
+// toggles the per-node STS annotation that puts the node back on its previous image
+func (u *upgrader) setRollbackAnnotation(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster, tag, image string) error {
+	return u.setSTSAnnotation(ctx, kc, c, tag, common.RollbackImageAnnotation, image)
+}
+
+func (u *upgrader) setSTSAnnotation(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster, tag, key, value string) error {
+	stsName := statefulSetName(c, tag)
 	var sts appsv1.StatefulSet
 
 	err := kc.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: stsName}, &sts)
@@ -101,9 +110,9 @@ func (u *upgrader) setUpgradeAnnotation(ctx context.Context, kc client.Client, c
 	}
 
 	if value == "" {
-		delete(sts.Annotations, common.UpgradeImageAnnotation)
+		delete(sts.Annotations, key)
 	} else {
-		sts.Annotations[common.UpgradeImageAnnotation] = value
+		sts.Annotations[key] = value
 	}
 
 	return kc.Patch(ctx, &sts, client.MergeFrom(old))
@@ -111,7 +120,7 @@ func (u *upgrader) setUpgradeAnnotation(ctx context.Context, kc client.Client, c
 
 func (u *upgrader) hasUpgradeAnnotation(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster, tag string) (bool, error) {
 	var sts appsv1.StatefulSet
-	if err := kc.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: statefulSetName(tag)}, &sts); err != nil {
+	if err := kc.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: statefulSetName(c, tag)}, &sts); err != nil {
 		return false, err
 	}
 	if sts.Annotations == nil {
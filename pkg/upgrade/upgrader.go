@@ -30,18 +30,30 @@ import (
 
 	ravendbv1 "ravendb-operator/api/v1"
 	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/ravendb"
 )
 
 type Upgrader interface {
-	Run(ctx context.Context, cluster *ravendbv1.RavenDBCluster, kc client.Client, applyNode ApplyNodeFn) ([]ravendbv1.RavenDBNodeStatus, error)
+	Run(ctx context.Context, cluster *ravendbv1.RavenDBCluster, kc client.Client, applyNode ApplyNodeFn) (RunResult, error)
 	SetEmitter(GateEmitter)
 	SetTiming(Timing)
+	SetGates(*GateRegistry)
+}
+
+// RunResult is the outcome of one upgrade tick.
+// Upgrade is the new rollout/state machine state (nil when the cluster was never upgraded) and
+// RequeueAfter tells the controller when the next tick is due.
+type RunResult struct {
+	Nodes        []ravendbv1.RavenDBNodeStatus
+	Upgrade      *ravendbv1.UpgradeStatus
+	RequeueAfter time.Duration
 }
 
 type upgrader struct {
 	buildGates func(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster) (*HealthCheckContext, error)
 	timing     Timing
 	emit       GateEmitter
+	gates      *GateRegistry
 }
 
 type GateState string
@@ -57,6 +69,7 @@ type ApplyNodeFn func(node ravendbv1.RavenDBNode) error
 type GateEmitter func(cluster *ravendbv1.RavenDBCluster, state GateState, phase GatePhase, kind GateKind, tag, info string)
 
 func (u *upgrader) SetEmitter(e GateEmitter) { u.emit = e }
+func (u *upgrader) SetGates(r *GateRegistry) { u.gates = r }
 func normalizeTag(t string) string           { return strings.ToUpper(strings.TrimSpace(t)) }
 
 func NewUpgrader(t Timing) Upgrader {
@@ -79,143 +92,298 @@ func NewUpgrader(t Timing) Upgrader {
 	return &upgrader{
 		buildGates: buildGatesDefault,
 		timing:     t,
+		gates:      DefaultGateRegistry(),
 	}
 }
 
 func buildGatesDefault(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster) (*HealthCheckContext, error) {
-	httpc, err := BuildHTTPSClientFromCluster(ctx, kc, c)
+	rc, err := ravendb.NewForCluster(ctx, kc, c)
 	if err != nil {
 		return nil, err
 	}
-	return NewChecks(httpc, c), nil
+	return NewChecks(rc).WithPods(kc, c), nil
 }
 
-// Run() performs exactly one "upgrade tick".
+// Run() performs exactly one "upgrade tick" and never sleeps.
+// the upgrade is a state machine persisted in status.upgrade:
+//
+//	(idle) -> PreGates -> Applying -> PostGates(node_alive) -> GracePeriod -> PostGates(...) -> (idle)
+//
+// status.upgrade also describes the whole rollout: source/target image, the ordered plan of nodes
+// with per node timestamps and the node being upgraded now. it is kept after the rollout completed.
+//
 // High-level steps:
-//  1. build gates + http client.
-//  2. figure out which single node we should work on now.
-//  3. if a node was chosen:
-//     a) if upgrading, run pre-checks and mark it as upgrading with an annotation.
-//     b) call applyNode(node) to mutate its image.
-//     c) if upgraded, run post-checks. On failure, mark node status as Failed.
-//  4. Return statuses for all nodes.
+//  1. if no node is in flight, figure out which single node we should work on now.
+//     a node without a StatefulSet is simply created (no gates), an image mismatch starts
+//     (or continues) a rollout and its next planned node enters the pre gates.
+//  2. advance the state machine: each gate is checked once per tick. a gate that is not ready yet
+//     returns RequeueAfter (backoff) instead of blocking the worker, until its deadline passes.
+//  3. on a failed gate the node is marked Failed and the upgrade restarts from the same node later.
+//     with rollbackPolicy=Automatic a failed post gate puts the node back on its previous image instead
+//     (see rollback.go).
+//  4. spec.upgrade.control is checked between nodes and between pre gates: Pause/Abort hand a node which
+//     image was not changed yet back to the plan, a node which image was already changed always finishes.
+//  5. Return statuses for all nodes plus the new state.
 func (u *upgrader) Run(
 	ctx context.Context,
 	cluster *ravendbv1.RavenDBCluster,
 	kc client.Client,
 	applyNode ApplyNodeFn,
-) ([]ravendbv1.RavenDBNodeStatus, error) {
-
-	// 1) build gates (HTTP client to cluster for checks)
-	gates, err := u.buildGates(ctx, kc, cluster)
-	if err != nil {
-		return nil, err
-	}
+) (RunResult, error) {
 
 	desiredImg := desiredNodeImage(cluster)
 	prev := buildPrevStatusMap(cluster.Status)
 
-	// 2) decide which node to work on in this tick
-	selectedTag, err := u.pickSelectedTag(ctx, kc, cluster, desiredImg)
-	if err != nil {
-		// on error, fall back to returning current statuses
-		out := make([]ravendbv1.RavenDBNodeStatus, 0, len(cluster.Spec.Nodes))
-		for _, n := range cluster.Spec.Nodes {
-			out = append(out, statusOrCreated(prev, n.Tag))
+	res := RunResult{
+		Nodes:   make([]ravendbv1.RavenDBNodeStatus, 0, len(cluster.Spec.Nodes)),
+		Upgrade: cluster.Status.Upgrade.DeepCopy(),
+	}
+	for _, n := range cluster.Spec.Nodes {
+		res.Nodes = append(res.Nodes, statusOrCreated(prev, n.Tag))
+	}
+
+	control := cluster.EffectiveUpgradeControl()
+	if rolloutActive(res.Upgrade, desiredImg) {
+		res.Upgrade.Control = control
+	}
+
+	// 1) nothing in flight - decide which node to work on in this tick
+	if !nodeInFlight(res.Upgrade) {
+		started, err := u.start(ctx, cluster, kc, applyNode, desiredImg, control, &res)
+		if err != nil || !started {
+			return res, err
 		}
-		return out, err
 	}
 
-	// if nothing to do, just return existing statuses
+	// 2) advance the state machine
+	return u.advance(ctx, cluster, kc, applyNode, desiredImg, res)
+}
+
+func nodeInFlight(st *ravendbv1.UpgradeStatus) bool {
+	return st != nil && st.CurrentNode != ""
+}
+
+// start picks the next node. it returns true when a node upgrade was started and has to be advanced.
+func (u *upgrader) start(ctx context.Context, cluster *ravendbv1.RavenDBCluster, kc client.Client, applyNode ApplyNodeFn, desiredImg string, control ravendbv1.UpgradeControl, res *RunResult) (bool, error) {
+	selectedTag, err := u.pickSelectedTag(ctx, kc, cluster, desiredImg)
+	if err != nil {
+		return false, err
+	}
 	if selectedTag == "" {
-		out := make([]ravendbv1.RavenDBNodeStatus, 0, len(cluster.Spec.Nodes))
-		for _, n := range cluster.Spec.Nodes {
-			out = append(out, statusOrCreated(prev, n.Tag))
+		// every node runs the desired image
+		completeRollout(res.Upgrade)
+		return false, nil
+	}
+
+	node, ok := findNode(cluster, selectedTag)
+	if !ok {
+		return false, nil
+	}
+
+	sts, stsExists, err := u.loadSTSByNodeTag(ctx, kc, cluster, node.Tag)
+	if err != nil {
+		setNodeStatus(res.Nodes, failedStatus(node.Tag, err.Error(), desiredImg))
+		return false, err
+	}
+
+	currentImg := ""
+	if sts != nil {
+		currentImg = currentStsImage(sts)
+	}
+	marked, _ := u.hasUpgradeAnnotation(ctx, kc, cluster, node.Tag)
+
+	// first creation (or nothing to upgrade) - no gates
+	if !isUpgrading(stsExists, desiredImg, currentImg, marked) {
+		if err := applyNode(node); err != nil {
+			setNodeStatus(res.Nodes, failedStatus(node.Tag, err.Error(), desiredImg))
+			return false, fmt.Errorf("apply node %s failed: %w", node.Tag, err)
 		}
-		return out, nil
+		return false, nil
+	}
+
+	// rolled back - wait for the spec to change before trying again
+	if !marked && rolledBack(res.Upgrade, desiredImg, cluster.Generation) {
+		return false, nil
 	}
 
-	// 3) iterate all nodes - only mutate the chosen one, keep the rest unchanged
-	statuses := make([]ravendbv1.RavenDBNodeStatus, 0, len(cluster.Spec.Nodes))
+	// paused or aborted - do not touch the next node (a marked node already got its image and has to finish)
+	if !marked && control != ravendbv1.UpgradeControlProceed {
+		return false, u.hold(ctx, kc, cluster, desiredImg, control, res)
+	}
 
-	for _, node := range cluster.Spec.Nodes {
-		// untouched nodes
-		if !strings.EqualFold(node.Tag, selectedTag) {
-			statuses = append(statuses, statusOrCreated(prev, node.Tag))
-			continue
+	leader := u.leaderTag(ctx, kc, cluster)
+	if !rolloutActive(res.Upgrade, desiredImg) {
+		rollout, err := u.newRollout(ctx, kc, cluster, desiredImg, leader)
+		if err != nil {
+			return false, err
 		}
+		res.Upgrade = rollout
+	}
+	res.Upgrade.Phase = ravendbv1.UpgradePhaseRunning
+	res.Upgrade.Control = control
+
+	if marked {
+		// the image was already handed to the StatefulSet (e.g. the status was lost) - resume from apply
+		beginNode(res.Upgrade, node.Tag, currentImg)
+		res.Upgrade.Step = ravendbv1.UpgradeStepApplying
+		return true, nil
+	}
+
+	// follow the plan (followers first, the leader last), fall back to the mismatched node if the plan has
+	// nothing left (e.g. a node was appended)
+	tag, err := u.nextPlanned(ctx, kc, cluster, res.Upgrade, desiredImg, leader)
+	if err != nil {
+		return false, err
+	}
+	fromImg := ""
+	if tag == "" || strings.EqualFold(tag, node.Tag) {
+		tag, fromImg = node.Tag, currentImg
+	}
+	beginNode(res.Upgrade, tag, fromImg)
+	u.enterGates(cluster, res.Upgrade, GatePreStep, 0)
+	return true, nil
+}
+
+// advance moves the state machine forward until it has to wait for something (or finishes).
+func (u *upgrader) advance(ctx context.Context, cluster *ravendbv1.RavenDBCluster, kc client.Client, applyNode ApplyNodeFn, desiredImg string, res RunResult) (RunResult, error) {
+	var hcc *HealthCheckContext
 
-		// we operate on this node
-		sts, stsExists, getErr := u.loadSTSByNodeTag(ctx, kc, cluster, node.Tag)
-		if getErr != nil {
-			// mark upgrade as failed
-			statuses = append(statuses, ravendbv1.RavenDBNodeStatus{Tag: node.Tag, Status: ravendbv1.NodeStatusFailed})
-			return statuses, getErr
+	// bounded, every step either progresses or returns
+	for i := 0; i < 16 && nodeInFlight(res.Upgrade); i++ {
+		st := res.Upgrade
+
+		node, ok := findNode(cluster, st.CurrentNode)
+		if !ok {
+			// node was removed from the spec while being upgraded
+			if idx := planIndex(st, st.CurrentNode); idx >= 0 {
+				st.Plan = append(st.Plan[:idx], st.Plan[idx+1:]...)
+			}
+			endNode(st, "")
+			return res, nil
 		}
 
-		currentImg := ""
-		if sts != nil {
-			currentImg = currentStsImage(sts)
+		if st.Step == ravendbv1.UpgradeStepPreGates && st.Control != "" && st.Control != ravendbv1.UpgradeControlProceed {
+			// the image of the node was not changed yet - hand it back to the plan and hold the rollout
+			endNode(st, ravendbv1.UpgradeNodePending)
+			return res, u.hold(ctx, kc, cluster, desiredImg, st.Control, &res)
 		}
-		marked, _ := u.hasUpgradeAnnotation(ctx, kc, cluster, node.Tag)
-		upgrading := isUpgrading(stsExists, desiredImg, currentImg, marked)
-
-		// BEFORE: if upgrading and not already marked, run gates + set annotations
-		if upgrading && !marked {
-			if err := u.preNode(ctx, cluster, gates, node.Tag); err != nil {
-				statuses = append(statuses, failedStatus(node.Tag, err.Error(), desiredImg))
-				return statuses, fmt.Errorf("pre-node gates failed for %s: %w", node.Tag, err)
+
+		switch st.Step {
+		case ravendbv1.UpgradeStepPreGates, ravendbv1.UpgradeStepPostGates:
+			if hcc == nil {
+				built, err := u.buildGates(ctx, kc, cluster)
+				if err != nil {
+					res.RequeueAfter = failedRetryInterval
+					return res, err
+				}
+				hcc = built
 			}
 
-			// mark upgrade intent with target image
-			if err := u.setUpgradeAnnotation(ctx, kc, cluster, node.Tag, desiredImg); err != nil {
-				statuses = append(statuses, failedStatus(node.Tag, "set upgrade annotation: "+err.Error(), desiredImg))
-				_ = u.setUpgradeAnnotation(ctx, kc, cluster, node.Tag, "")
-				return statuses, err
+			phase := GatePhase(st.GatePhase)
+			done, wait, err := u.stepGate(ctx, cluster, hcc, st)
+			if err != nil {
+				if phase == GatePreStep {
+					return u.failNode(ctx, kc, cluster, res, node.Tag, desiredImg, fmt.Errorf("pre-node gates failed for %s: %w", node.Tag, err))
+				}
+				if rollingBack(st) {
+					return u.finishRollback(ctx, kc, cluster, res, node.Tag, desiredImg, fmt.Errorf("post-rollback gates failed for %s: %w", node.Tag, err))
+				}
+				cause := fmt.Errorf("post-node gates failed for %s: %w", node.Tag, err)
+				started, rbErr := u.startRollback(ctx, kc, cluster, &res, node.Tag, desiredImg, cause)
+				if started {
+					continue
+				}
+				if rbErr != nil {
+					cause = fmt.Errorf("%w; %v", cause, rbErr)
+				}
+				return u.failNode(ctx, kc, cluster, res, node.Tag, desiredImg, cause)
+			}
+			if wait > 0 {
+				res.RequeueAfter = wait
+				return res, nil
+			}
+			if !done {
+				continue
 			}
-		}
 
-		// MUTATE
-		if err := applyNode(node); err != nil {
-			statuses = append(statuses, failedStatus(node.Tag, err.Error(), desiredImg))
-			if upgrading {
-				_ = u.setUpgradeAnnotation(ctx, kc, cluster, node.Tag, "")
+			if phase == GatePreStep {
+				// mark upgrade intent with target image
+				if err := u.setUpgradeAnnotation(ctx, kc, cluster, node.Tag, desiredImg); err != nil {
+					_ = u.setUpgradeAnnotation(ctx, kc, cluster, node.Tag, "")
+					return u.failNode(ctx, kc, cluster, res, node.Tag, desiredImg, fmt.Errorf("set upgrade annotation: %w", err))
+				}
+				st.Step = ravendbv1.UpgradeStepApplying
+				continue
 			}
-			return statuses, fmt.Errorf("apply node %s failed: %w", node.Tag, err)
-		}
 
-		// AFTER: only for real upgrades (not first creation)
-		if upgrading {
-			if err := u.postNode(ctx, cluster, gates, node.Tag); err != nil {
-				// just mark failed and clear annotation
-				statuses = append(statuses, failedStatus(
-					node.Tag,
-					err.Error(),
-					desiredImg,
-				))
-				_ = u.setUpgradeAnnotation(ctx, kc, cluster, node.Tag, "")
-				return statuses, fmt.Errorf("post-node gates failed for %s: %w", node.Tag, err)
+			if rollingBack(st) {
+				return u.finishRollback(ctx, kc, cluster, res, node.Tag, desiredImg, nil)
 			}
 
 			// success so cleanup annotation
 			_ = u.setUpgradeAnnotation(ctx, kc, cluster, node.Tag, "")
-			statuses = append(statuses, successStatus(node.Tag, desiredImg))
-			continue
+			setNodeStatus(res.Nodes, successStatus(node.Tag, desiredImg))
+			endNode(st, ravendbv1.UpgradeNodeCompleted)
+			res.RequeueAfter = nextNodeInterval
+			return res, nil
+
+		case ravendbv1.UpgradeStepApplying:
+			// MUTATE
+			if err := applyNode(node); err != nil {
+				return u.failNode(ctx, kc, cluster, res, node.Tag, desiredImg, fmt.Errorf("apply node %s failed: %w", node.Tag, err))
+			}
+			u.enterGates(cluster, st, GatePostStep, 0)
+
+		case ravendbv1.UpgradeStepGracePeriod:
+			// grace period so the node finishes bootstrapping before the gates.
+			if remaining := untilDeadline(st); remaining > 0 {
+				res.RequeueAfter = remaining
+				return res, nil
+			}
+			st.Step = ravendbv1.UpgradeStepPostGates
+			resetGate(st)
+
+		default:
+			// unknown step (e.g. written by a newer operator) - restart the node from its pre gates
+			u.enterGates(cluster, st, GatePreStep, 0)
 		}
+	}
 
-		statuses = append(statuses, statusOrCreated(prev, node.Tag))
+	if nodeInFlight(res.Upgrade) && res.RequeueAfter == 0 {
+		res.RequeueAfter = nextNodeInterval
 	}
+	return res, nil
+}
+
+// failNode marks the node Failed, drops the in-flight state and clears the upgrade annotation.
+// the rollout itself stays Running: the next tick (after failedRetryInterval) starts the same node
+// again from its pre gates.
+func (u *upgrader) failNode(ctx context.Context, kc client.Client, cluster *ravendbv1.RavenDBCluster, res RunResult, tag, desiredImg string, err error) (RunResult, error) {
+	setNodeStatus(res.Nodes, failedStatus(tag, err.Error(), desiredImg))
+	_ = u.setUpgradeAnnotation(ctx, kc, cluster, tag, "")
+	if res.Upgrade != nil {
+		endNode(res.Upgrade, ravendbv1.UpgradeNodeFailed)
+	}
+	res.RequeueAfter = failedRetryInterval
+	return res, err
+}
 
-	// 4) keep order like Spec.Nodes
-	byUpper := make(map[string]ravendbv1.RavenDBNodeStatus, len(statuses))
-	for _, s := range statuses {
-		byUpper[normalizeTag(s.Tag)] = s
+func findNode(c *ravendbv1.RavenDBCluster, tag string) (ravendbv1.RavenDBNode, bool) {
+	for _, n := range c.Spec.Nodes {
+		if strings.EqualFold(n.Tag, tag) {
+			return n, true
+		}
 	}
-	ordered := make([]ravendbv1.RavenDBNodeStatus, 0, len(cluster.Spec.Nodes))
-	for _, n := range cluster.Spec.Nodes {
-		ordered = append(ordered, byUpper[normalizeTag(n.Tag)])
+	return ravendbv1.RavenDBNode{}, false
+}
+
+func setNodeStatus(statuses []ravendbv1.RavenDBNodeStatus, s ravendbv1.RavenDBNodeStatus) {
+	for i := range statuses {
+		if strings.EqualFold(statuses[i].Tag, s.Tag) {
+			statuses[i] = s
+			return
+		}
 	}
-	return ordered, nil
 }
 
 // looks for a node which StatefulSet has the "upgrade image" annotation.
@@ -223,7 +391,7 @@ func (u *upgrader) Run(
 func (u *upgrader) findInFlightTag(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster) (string, error) {
 	for _, n := range c.Spec.Nodes {
 		var sts appsv1.StatefulSet
-		err := kc.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: statefulSetName(n.Tag)}, &sts)
+		err := kc.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: statefulSetName(c, n.Tag)}, &sts)
 		if err == nil {
 			if sts.Annotations != nil {
 				if _, ok := sts.Annotations[common.UpgradeImageAnnotation]; ok {
@@ -235,41 +403,6 @@ func (u *upgrader) findInFlightTag(ctx context.Context, kc client.Client, c *rav
 	return "", nil
 }
 
-func (u *upgrader) preNode(ctx context.Context, c *ravendbv1.RavenDBCluster, hcc *HealthCheckContext, tag string) error {
-	if err := u.waitNodeAlive(ctx, c, hcc, GatePreStep, tag); err != nil {
-		return err
-	}
-	if err := u.waitConnectivity(ctx, c, hcc, GatePreStep, tag); err != nil {
-		return err
-	}
-	if err := u.waitDB(ctx, c, hcc, GatePreStep, tag, tag); err != nil {
-		return err
-	}
-	return nil
-}
-
-// postNode runs checks AFTER we mutate the node (alive, connectivity, DBs full cluster).
-func (u *upgrader) postNode(ctx context.Context, c *ravendbv1.RavenDBCluster, hcc *HealthCheckContext, tag string) error {
-	if err := u.waitNodeAlive(ctx, c, hcc, GatePostStep, tag); err != nil {
-		return err
-	}
-
-	// grace period so the node finishes bootstrapping before the gates.
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-time.After(u.timing.GraceAfterReady):
-	}
-
-	if err := u.waitConnectivity(ctx, c, hcc, GatePostStep, tag); err != nil {
-		return err
-	}
-	if err := u.waitDB(ctx, c, hcc, GatePostStep, "", tag); err != nil {
-		return err
-	}
-	return nil
-}
-
 func desiredNodeImage(c *ravendbv1.RavenDBCluster) string {
 	return c.Spec.Image
 }
@@ -319,7 +452,7 @@ func (u *upgrader) pickSelectedTag(ctx context.Context, kc client.Client, c *rav
 
 	// if no in-flight upgrade is found, we look for the first node with no sts
 	for _, n := range c.Spec.Nodes {
-		name := statefulSetName(n.Tag)
+		name := statefulSetName(c, n.Tag)
 		var sts appsv1.StatefulSet
 		if err := kc.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: name}, &sts); kerrors.IsNotFound(err) {
 			return normalizeTag(n.Tag), nil
@@ -328,7 +461,7 @@ func (u *upgrader) pickSelectedTag(ctx context.Context, kc client.Client, c *rav
 
 	// lastly the first one with image mismatch
 	for _, n := range c.Spec.Nodes {
-		name := statefulSetName(n.Tag)
+		name := statefulSetName(c, n.Tag)
 		var sts appsv1.StatefulSet
 		if err := kc.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: name}, &sts); err == nil {
 			cur := currentStsImage(&sts)
@@ -343,7 +476,7 @@ func (u *upgrader) pickSelectedTag(ctx context.Context, kc client.Client, c *rav
 
 func (u *upgrader) loadSTSByNodeTag(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster, tag string) (*appsv1.StatefulSet, bool, error) {
 	var sts appsv1.StatefulSet
-	err := kc.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: statefulSetName(tag)}, &sts)
+	err := kc.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: statefulSetName(c, tag)}, &sts)
 	if err != nil {
 		if kerrors.IsNotFound(err) {
 			return nil, false, nil
@@ -395,7 +528,7 @@ func NewGateEventEmitter(kc client.Client, rec record.EventRecorder) GateEmitter
 		rec.Eventf(c, eventType, reason, "%s", msg)
 
 		if tag = strings.TrimSpace(tag); tag != "" {
-			stsName := fmt.Sprintf("%s%s", common.Prefix, strings.ToLower(tag))
+			stsName := statefulSetName(c, tag)
 			var sts appsv1.StatefulSet
 			//ignore errors (e.g., during initial creation when STS may not exist yet)
 			if err := kc.Get(
@@ -406,8 +539,5 @@ func NewGateEventEmitter(kc client.Client, rec record.EventRecorder) GateEmitter
 				rec.Eventf(&sts, eventType, reason, "%s", msg)
 			}
 		}
-
-		// avoid events from sharing identical timestamps which can cause the K8s event aggregator to fold them.
-		time.Sleep(200 * time.Millisecond)
 	}
 }
@@ -0,0 +1,296 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package upgrade
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/ravendb/fake"
+)
+
+const (
+	oldImage = "ravendb/ravendb:6.2.1"
+	newImage = "ravendb/ravendb:6.2.5"
+)
+
+// stubGate answers its checks with check, so a test decides when a gate passes
+type stubGate struct {
+	kind   GateKind
+	phases []GatePhase
+	check  func(req GateRequest) (bool, string, error)
+}
+
+func (g stubGate) Kind() GateKind                  { return g.kind }
+func (g stubGate) Phases() []GatePhase             { return g.phases }
+func (g stubGate) EnabledByDefault() bool          { return true }
+func (g stubGate) Interval(t Timing) time.Duration { return t.PingInterval }
+func (g stubGate) Check(_ context.Context, _ *HealthCheckContext, req GateRequest) (bool, string, error) {
+	return g.check(req)
+}
+
+func passing(kind GateKind, phase GatePhase) stubGate {
+	return stubGate{kind: kind, phases: []GatePhase{phase}, check: func(GateRequest) (bool, string, error) { return true, "", nil }}
+}
+
+// switchGate blocks with info until pass is set
+type switchGate struct {
+	stubGate
+	pass *bool
+}
+
+func blocking(kind GateKind, phase GatePhase, info string) switchGate {
+	pass := new(bool)
+	return switchGate{
+		stubGate: stubGate{kind: kind, phases: []GatePhase{phase}, check: func(GateRequest) (bool, string, error) { return *pass, info, nil }},
+		pass:     pass,
+	}
+}
+
+// rolloutEnv is a cluster of nodes A, B and C running oldImage, with spec.image set to newImage.
+// node A leads the RavenDB cluster.
+type rolloutEnv struct {
+	t       *testing.T
+	srv     *fake.Server
+	kc      client.Client
+	c       *ravendbv1.RavenDBCluster
+	u       *upgrader
+	applied []string
+}
+
+func newRolloutEnv(t *testing.T, gates ...Gate) *rolloutEnv {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+
+	c := &ravendbv1.RavenDBCluster{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "ravendb", Generation: 1}}
+	c.Spec.Image = newImage
+	var objs []client.Object
+	for _, tag := range []string{"A", "B", "C"} {
+		c.Spec.Nodes = append(c.Spec.Nodes, ravendbv1.RavenDBNode{Tag: tag, PublicServerUrl: fake.URL(tag)})
+		sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: statefulSetName(c, tag), Namespace: c.Namespace}}
+		sts.Spec.Template.Spec.Containers = []corev1.Container{{Name: common.App, Image: oldImage}}
+		objs = append(objs, sts)
+	}
+
+	e := &rolloutEnv{
+		t:   t,
+		srv: fake.New("A", "B", "C"),
+		kc:  fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		c:   c,
+	}
+	e.u = e.newUpgrader(gates...)
+	return e
+}
+
+// newUpgrader is what a (re)started operator builds: no state besides the cluster status
+func (e *rolloutEnv) newUpgrader(gates ...Gate) *upgrader {
+	u := NewUpgrader(Timing{}).(*upgrader)
+	u.SetTiming(Timing{PreMaxWait: time.Minute, PostMaxWait: time.Minute, PingInterval: time.Second, DBInterval: time.Second})
+	u.SetGates(NewGateRegistry(gates...))
+	u.buildGates = func(context.Context, client.Client, *ravendbv1.RavenDBCluster) (*HealthCheckContext, error) {
+		return NewChecks(e.srv.Client()).WithPods(e.kc, e.c), nil
+	}
+	return u
+}
+
+// applyNode does what the StatefulSetActor does with the image of an existing StatefulSet
+func (e *rolloutEnv) applyNode(node ravendbv1.RavenDBNode) error {
+	e.applied = append(e.applied, node.Tag)
+	sts := e.sts(node.Tag)
+	switch {
+	case sts.Annotations[common.RollbackImageAnnotation] != "":
+		sts.Spec.Template.Spec.Containers[0].Image = sts.Annotations[common.RollbackImageAnnotation]
+	case sts.Annotations[common.UpgradeImageAnnotation] != "":
+		sts.Spec.Template.Spec.Containers[0].Image = e.c.Spec.Image
+	}
+	return e.kc.Update(context.Background(), sts)
+}
+
+// tick runs one reconcile of the upgrader and persists its status like the controller does
+func (e *rolloutEnv) tick() (RunResult, error) {
+	e.t.Helper()
+	res, err := e.u.Run(context.Background(), e.c, e.kc, e.applyNode)
+	e.c.Status.Upgrade = res.Upgrade
+	e.c.Status.Nodes = res.Nodes
+	return res, err
+}
+
+func (e *rolloutEnv) sts(tag string) *appsv1.StatefulSet {
+	e.t.Helper()
+	var sts appsv1.StatefulSet
+	require.NoError(e.t, e.kc.Get(context.Background(), client.ObjectKey{Namespace: e.c.Namespace, Name: statefulSetName(e.c, tag)}, &sts))
+	return &sts
+}
+
+func (e *rolloutEnv) image(tag string) string {
+	return currentStsImage(e.sts(tag))
+}
+
+func (e *rolloutEnv) planPhase(tag string) ravendbv1.UpgradeNodePhase {
+	st := e.c.Status.Upgrade
+	if i := planIndex(st, tag); i >= 0 {
+		return st.Plan[i].Phase
+	}
+	return ""
+}
+
+func Test_U1_OneNodePerTick(t *testing.T) {
+	e := newRolloutEnv(t, passing(GatePodReady, GatePreStep), passing(GateNodeAlive, GatePostStep))
+
+	for i, tag := range []string{"B", "C", "A"} {
+		res, err := e.tick()
+		require.NoError(t, err)
+		require.Equal(t, nextNodeInterval, res.RequeueAfter, "tick %d", i)
+		require.Equal(t, newImage, e.image(tag))
+		require.Equal(t, ravendbv1.UpgradeNodeCompleted, e.planPhase(tag))
+		require.Empty(t, e.c.Status.Upgrade.CurrentNode)
+		require.NotContains(t, e.sts(tag).Annotations, common.UpgradeImageAnnotation)
+	}
+	require.Equal(t, []string{"B", "C", "A"}, e.applied)
+	require.Equal(t, ravendbv1.UpgradePhaseRunning, e.c.Status.Upgrade.Phase)
+
+	res, err := e.tick()
+	require.NoError(t, err)
+	require.Zero(t, res.RequeueAfter)
+	require.Equal(t, ravendbv1.UpgradePhaseCompleted, e.c.Status.Upgrade.Phase)
+}
+
+func Test_U2_BlockedGateRequeuesWithBackoff(t *testing.T) {
+	pre := blocking(GatePodReady, GatePreStep, "pod not ready")
+	e := newRolloutEnv(t, pre, passing(GateNodeAlive, GatePostStep))
+
+	for i, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, maxGateBackoff, maxGateBackoff} {
+		res, err := e.tick()
+		require.NoError(t, err)
+		require.Equal(t, want, res.RequeueAfter, "attempt %d", i+1)
+
+		st := e.c.Status.Upgrade
+		require.Equal(t, "B", st.CurrentNode)
+		require.Equal(t, ravendbv1.UpgradeStepPreGates, st.Step)
+		require.Equal(t, string(GatePreStep), st.GatePhase)
+		require.Equal(t, string(GatePodReady), st.GateKind)
+		require.Equal(t, int32(i+1), st.Attempt)
+		require.Equal(t, "pod not ready", st.GateMessage)
+		require.NotNil(t, st.Deadline)
+	}
+	require.Empty(t, e.applied)
+
+	*pre.pass = true
+	_, err := e.tick()
+	require.NoError(t, err)
+	require.Equal(t, []string{"B"}, e.applied)
+	require.Equal(t, ravendbv1.UpgradeNodeCompleted, e.planPhase("B"))
+}
+
+func Test_U3_GateTimeoutFailsTheNodeAndRetriesIt(t *testing.T) {
+	pre := blocking(GatePodReady, GatePreStep, "pod not ready")
+	e := newRolloutEnv(t, pre, passing(GateNodeAlive, GatePostStep))
+	e.u.timing.PreMaxWait = time.Nanosecond
+
+	res, err := e.tick()
+	var ge *GateError
+	require.True(t, errors.As(err, &ge))
+	require.Equal(t, GatePodReady, ge.Kind)
+	require.Equal(t, "pod not ready (timeout)", ge.Info)
+	require.Equal(t, failedRetryInterval, res.RequeueAfter)
+	require.Equal(t, ravendbv1.UpgradeNodeFailed, e.planPhase("B"))
+	require.Empty(t, e.c.Status.Upgrade.CurrentNode)
+	require.Equal(t, ravendbv1.UpgradePhaseRunning, e.c.Status.Upgrade.Phase)
+	require.NotContains(t, e.sts("B").Annotations, common.UpgradeImageAnnotation)
+	require.Empty(t, e.applied)
+
+	// the failed node is picked again and starts over from its pre gates
+	*pre.pass = true
+	e.u.timing.PreMaxWait = time.Minute
+	_, err = e.tick()
+	require.NoError(t, err)
+	require.Equal(t, []string{"B"}, e.applied)
+	require.Equal(t, ravendbv1.UpgradeNodeCompleted, e.planPhase("B"))
+}
+
+func Test_U4_ResumesAfterARestart(t *testing.T) {
+	post := blocking(GateNodeAlive, GatePostStep, "node not alive")
+	gates := []Gate{passing(GatePodReady, GatePreStep), post}
+	e := newRolloutEnv(t, gates...)
+
+	_, err := e.tick()
+	require.NoError(t, err)
+	require.Equal(t, ravendbv1.UpgradeStepPostGates, e.c.Status.Upgrade.Step)
+
+	// a new operator continues from status.upgrade, the node isn't applied again
+	e.u = e.newUpgrader(gates...)
+	*post.pass = true
+	_, err = e.tick()
+	require.NoError(t, err)
+	require.Equal(t, []string{"B"}, e.applied)
+	require.Equal(t, ravendbv1.UpgradeNodeCompleted, e.planPhase("B"))
+
+	// with the status lost, the marker on the StatefulSet resumes the node from apply
+	sts := e.sts("C")
+	sts.Annotations = map[string]string{common.UpgradeImageAnnotation: newImage}
+	require.NoError(t, e.kc.Update(context.Background(), sts))
+	e.c.Status.Upgrade = nil
+	e.u = e.newUpgrader(gates...)
+
+	_, err = e.tick()
+	require.NoError(t, err)
+	require.Equal(t, []string{"B", "C"}, e.applied)
+	require.Equal(t, newImage, e.image("C"))
+	require.Equal(t, ravendbv1.UpgradeNodeCompleted, e.planPhase("C"))
+}
+
+func Test_U5_GracePeriodAfterTheNodeAnswers(t *testing.T) {
+	e := newRolloutEnv(t,
+		passing(GatePodReady, GatePreStep),
+		passing(GateNodeAlive, GatePostStep),
+		passing(GateClusterConnectivity, GatePostStep),
+	)
+	e.u.timing.GraceAfterReady = 10 * time.Second
+
+	res, err := e.tick()
+	require.NoError(t, err)
+	st := e.c.Status.Upgrade
+	require.Equal(t, ravendbv1.UpgradeStepGracePeriod, st.Step)
+	require.Equal(t, string(GateClusterConnectivity), st.GateKind)
+	require.Greater(t, res.RequeueAfter, 9*time.Second)
+	require.LessOrEqual(t, res.RequeueAfter, 10*time.Second)
+
+	// nothing happens until the grace period is over
+	_, err = e.tick()
+	require.NoError(t, err)
+	require.Equal(t, ravendbv1.UpgradeStepGracePeriod, e.c.Status.Upgrade.Step)
+
+	past := metav1.NewTime(time.Now().Add(-time.Second))
+	e.c.Status.Upgrade.Deadline = &past
+	res, err = e.tick()
+	require.NoError(t, err)
+	require.Equal(t, nextNodeInterval, res.RequeueAfter)
+	require.Equal(t, ravendbv1.UpgradeNodeCompleted, e.planPhase("B"))
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/metrics"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -36,107 +37,177 @@ type Timing struct {
 func (u *upgrader) SetTiming(t Timing) { u.timing = t }
 func timestampNow() metav1.Time        { return metav1.Now() }
 
-func (u *upgrader) waitNodeAlive(ctx context.Context, c *ravendbv1.RavenDBCluster, hcc *HealthCheckContext, phase GatePhase, tag string) error {
-	return u.wait(ctx, c, phase, GateNodeAlive, tag, u.timing.PingInterval, func() (bool, string, error) {
-		return hcc.NodeAlive(ctx, tag)
-	})
-}
+const (
+	// cap for the per-gate backoff between checks
+	maxGateBackoff = 15 * time.Second
+	// a single gate check must not hold the worker for long
+	gateCheckTimeout = 20 * time.Second
+	// pause between a finished node and picking the next one
+	nextNodeInterval = 2 * time.Second
+	// pause before starting a failed node again
+	failedRetryInterval = 30 * time.Second
+)
 
-func (u *upgrader) waitConnectivity(ctx context.Context, c *ravendbv1.RavenDBCluster, hcc *HealthCheckContext, phase GatePhase, tag string) error {
-	return u.wait(ctx, c, phase, GateClusterConnectivity, tag, u.timing.PingInterval, func() (bool, string, error) {
-		return hcc.ClusterConnectivity(ctx)
-	})
+// enterGates moves the state machine to the gate at index idx of phase.
+// past the last enabled gate GateKind stays empty, which stepGate reports as done.
+func (u *upgrader) enterGates(c *ravendbv1.RavenDBCluster, st *ravendbv1.UpgradeStatus, phase GatePhase, idx int) {
+	gates := u.gatesFor(c, phase)
+	st.Step = ravendbv1.UpgradeStepPreGates
+	if phase == GatePostStep {
+		st.Step = ravendbv1.UpgradeStepPostGates
+	}
+	st.GatePhase = string(phase)
+	st.GateKind = ""
+	if idx < len(gates) {
+		st.GateKind = string(gates[idx].Kind())
+	}
+	resetGate(st)
 }
 
-func (u *upgrader) waitDB(ctx context.Context, c *ravendbv1.RavenDBCluster, hcc *HealthCheckContext, phase GatePhase, excluded string, tag string) error {
-	return u.wait(ctx, c, phase, GateDatabasesOnline, tag, u.timing.DBInterval, func() (bool, string, error) {
-		return hcc.DatabasesOnline(ctx, excluded)
-	})
+func resetGate(st *ravendbv1.UpgradeStatus) {
+	st.Attempt = 0
+	st.GateStartTime = nil
+	st.Deadline = nil
 }
 
-func (u *upgrader) wait(
-	ctx context.Context,
-	c *ravendbv1.RavenDBCluster,
-	phase GatePhase,
-	kind GateKind,
-	tag string,
-	interval time.Duration,
-	fn func() (bool, string, error),
-) error {
-
-	// choose how long we are allowed to wait
-	maxWait := u.maxWaitFor(phase)
-	maxSleep := 15 * time.Second
-
-	// announce we started current gate
-	if u.emit != nil {
-		u.emit(c, GateStart, phase, kind, tag, "")
+// stepGate checks the current gate exactly once.
+// it returns done when the last gate of the phase passed, or wait > 0 when the gate is not ready yet.
+// a hard error or a passed deadline returns a *GateError.
+func (u *upgrader) stepGate(ctx context.Context, c *ravendbv1.RavenDBCluster, hcc *HealthCheckContext, st *ravendbv1.UpgradeStatus) (bool, time.Duration, error) {
+	phase := GatePhase(st.GatePhase)
+	kind := GateKind(st.GateKind)
+	tag := st.CurrentNode
+
+	if kind == "" {
+		// every gate of the phase is disabled
+		resetGate(st)
+		return true, 0, nil
 	}
 
-	start := time.Now()
-	sleep := interval
-	attempt := 0
-	lastInfo := ""
-
-	for {
-		// if something canceled the context, stop right away
-		if err := ctx.Err(); err != nil {
-			msg := err.Error()
-			if msg == "" {
-				msg = "timeout"
-			}
-			if u.emit != nil {
-				u.emit(c, GateTimeout, phase, kind, tag, msg)
-			}
-			return &GateError{Phase: phase, Kind: kind, Tag: tag, Info: msg}
+	gates := u.gatesFor(c, phase)
+	idx := -1
+	for i := range gates {
+		if gates[i].Kind() == kind {
+			idx = i
+			break
 		}
-
-		// actual gate check
-		ok, info, err := fn()
-		if err != nil {
-			// hard error from the check -> fail immediately
-			if u.emit != nil {
-				u.emit(c, GateBlock, phase, kind, tag, err.Error())
-			}
-			return &GateError{Phase: phase, Kind: kind, Tag: tag, Info: err.Error()}
+	}
+	if idx < 0 {
+		// unknown or disabled gate (e.g. changed since the state was written) - restart the phase
+		u.enterGates(c, st, phase, 0)
+		return false, 0, nil
+	}
+	g := gates[idx]
+
+	// announce we started current gate and choose how long we are allowed to wait
+	if st.Deadline == nil {
+		now := timestampNow()
+		deadline := metav1.NewTime(now.Add(g.timeout))
+		st.GateStartTime = &now
+		st.Deadline = &deadline
+		st.Attempt = 0
+		if u.emit != nil {
+			u.emit(c, GateStart, phase, kind, tag, "")
 		}
+	}
+
+	// actual gate check
+	cctx, cancel := context.WithTimeout(ctx, gateCheckTimeout)
+	ok, info, err := g.Check(cctx, hcc, GateRequest{Cluster: c.Namespace + "/" + c.Name, ClusterUID: string(c.UID), Phase: phase, Tag: tag, Params: g.params})
+	cancel()
 
-		if ok { // success
-			if u.emit != nil {
-				u.emit(c, GatePass, phase, kind, tag, "")
-			}
-			return nil
+	if err != nil {
+		// hard error from the check -> fail immediately
+		u.observeGate(c, st, metrics.GateFailed)
+		st.GateMessage = summarizeError(err.Error())
+		if u.emit != nil {
+			u.emit(c, GateBlock, phase, kind, tag, err.Error())
 		}
+		return false, 0, &GateError{Phase: phase, Kind: kind, Tag: tag, Info: err.Error()}
+	}
 
-		// not ok yet -> store info and announce block
-		lastInfo = info
-		attempt++
+	if ok { // success
+		u.observeGate(c, st, metrics.GatePassed)
+		st.GateMessage = ""
 		if u.emit != nil {
-			u.emit(c, GateBlock, phase, kind, tag,
-				fmt.Sprintf("retry in %s (attempt %d): %s", sleep, attempt, summarizeError(lastInfo)))
+			u.emit(c, GatePass, phase, kind, tag, "")
 		}
+		if idx+1 >= len(gates) {
+			resetGate(st)
+			return true, 0, nil
+		}
+
+		u.enterGates(c, st, phase, idx+1)
 
-		// check if we did we run out of time
-		if time.Since(start) >= maxWait {
-			msg := lastInfo
-			if msg == "" {
-				msg = "timeout"
-			} else {
-				msg = msg + " (timeout)"
-			}
-			if u.emit != nil {
-				u.emit(c, GateTimeout, phase, kind, tag, msg)
-			}
-			return &GateError{Phase: phase, Kind: kind, Tag: tag, Info: msg}
+		// after the node answers again, give it a grace period before the cluster wide gates
+		if phase == GatePostStep && kind == GateNodeAlive && u.timing.GraceAfterReady > 0 {
+			now := timestampNow()
+			deadline := metav1.NewTime(now.Add(u.timing.GraceAfterReady))
+			st.Step = ravendbv1.UpgradeStepGracePeriod
+			st.GateStartTime = &now
+			st.Deadline = &deadline
 		}
+		return false, 0, nil
+	}
 
-		// sleep a bit and try again
-		time.Sleep(sleep)
-		sleep = sleep * 2
-		if sleep > maxSleep {
-			sleep = maxSleep
+	// not ok yet -> count the attempt and announce block
+	st.Attempt++
+	sleep := backoff(g.Interval(u.timing), st.Attempt)
+	st.GateMessage = summarizeError(info)
+	if u.emit != nil {
+		u.emit(c, GateBlock, phase, kind, tag,
+			fmt.Sprintf("retry in %s (attempt %d): %s", sleep, st.Attempt, summarizeError(info)))
+	}
+
+	// check if we did we run out of time
+	if untilDeadline(st) <= 0 {
+		u.observeGate(c, st, metrics.GateTimedOut)
+		msg := info
+		if msg == "" {
+			msg = "timeout"
+		} else {
+			msg = msg + " (timeout)"
 		}
+		st.GateMessage = summarizeError(msg)
+		if u.emit != nil {
+			u.emit(c, GateTimeout, phase, kind, tag, msg)
+		}
+		return false, 0, &GateError{Phase: phase, Kind: kind, Tag: tag, Info: msg}
+	}
+
+	u.observeGate(c, st, metrics.GateBlocked)
+	return false, sleep, nil
+}
+
+// observeGate records a check of the current gate, before the state moves on to the next one
+func (u *upgrader) observeGate(c *ravendbv1.RavenDBCluster, st *ravendbv1.UpgradeStatus, outcome string) {
+	var elapsed time.Duration
+	if st.GateStartTime != nil {
+		elapsed = time.Since(st.GateStartTime.Time)
+	}
+	metrics.GateChecked(c.Namespace, c.Name, st.GatePhase, st.GateKind, outcome, elapsed)
+}
+
+// exponential backoff per gate: interval, 2*interval, 4*interval... capped
+func backoff(interval time.Duration, attempt int32) time.Duration {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	sleep := interval
+	for i := int32(1); i < attempt; i++ {
+		sleep *= 2
+		if sleep >= maxGateBackoff {
+			return maxGateBackoff
+		}
+	}
+	return sleep
+}
+
+func untilDeadline(st *ravendbv1.UpgradeStatus) time.Duration {
+	if st.Deadline == nil {
+		return 0
 	}
+	return time.Until(st.Deadline.Time)
 }
 
 func (u *upgrader) maxWaitFor(phase GatePhase) time.Duration {
@@ -0,0 +1,116 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package version parses RavenDB versions out of image tags and decides which version changes are safe.
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version is the leading major.minor.patch of a RavenDB image tag
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("v%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// parses the version at the start of the tag, e.g. "7.1.3" in: "7.1.3-ubuntu.22.04-x64"
+var leadingSemverRE = regexp.MustCompile(`^(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// Parse reads the version at the start of an image tag, a missing patch is 0
+func Parse(tag string) (Version, error) {
+	m := leadingSemverRE.FindStringSubmatch(tag)
+	if len(m) == 0 {
+		return Version{}, fmt.Errorf("no leading semver in tag %q", tag)
+	}
+
+	maj, _ := strconv.Atoi(m[1])
+	min, _ := strconv.Atoi(m[2])
+	pat := 0
+	if len(m) > 3 && m[3] != "" {
+		pat, _ = strconv.Atoi(m[3])
+	}
+	return Version{maj, min, pat}, nil
+}
+
+// Compare returns -1, 0 or 1 when a is older than, equal to or newer than b
+func Compare(a, b Version) int {
+	if a.Major != b.Major {
+		if a.Major > b.Major {
+			return 1
+		}
+		return -1
+	}
+
+	if a.Minor != b.Minor {
+		if a.Minor > b.Minor {
+			return 1
+		}
+		return -1
+	}
+
+	if a.Patch != b.Patch {
+		if a.Patch > b.Patch {
+			return 1
+		}
+		return -1
+	}
+	return 0
+}
+
+// Tag returns the tag of an image reference, false when it has none
+func Tag(image string) (string, bool) {
+	if i := strings.Index(image, "@"); i != -1 {
+		image = image[:i]
+	}
+
+	lastSlash := strings.LastIndex(image, "/")
+	lastColon := strings.LastIndex(image, ":")
+
+	if lastColon == -1 || lastColon < lastSlash {
+		return "", false
+	}
+
+	tag := image[lastColon+1:]
+	if tag == "" {
+		return "", false
+	}
+	return tag, true
+}
+
+// DataFormatCompatible tells whether a node can go back from newImage to oldImage without
+// converting its data. RavenDB keeps the storage format within a major.minor line, so only
+// patch level differences are considered compatible.
+func DataFormatCompatible(oldImage, newImage string) (bool, string) {
+	oldTag, _ := Tag(oldImage)
+	newTag, _ := Tag(newImage)
+	oldVer, errOld := Parse(oldTag)
+	newVer, errNew := Parse(newTag)
+	if errOld != nil || errNew != nil {
+		return false, fmt.Sprintf("unable to parse RavenDB versions from %q and %q", oldImage, newImage)
+	}
+	if oldVer.Major != newVer.Major || oldVer.Minor != newVer.Minor {
+		return false, fmt.Sprintf("v%d.%d -> v%d.%d changes the data format", oldVer.Major, oldVer.Minor, newVer.Major, newVer.Minor)
+	}
+	return true, ""
+}
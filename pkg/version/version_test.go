@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_V1_Parse(t *testing.T) {
+	v, err := Parse("7.1.3-ubuntu.22.04-x64")
+	require.NoError(t, err)
+	require.Equal(t, Version{7, 1, 3}, v)
+	require.Equal(t, "v7.1.3", v.String())
+
+	v, err = Parse("6.2-ubuntu.22.04-x64")
+	require.NoError(t, err)
+	require.Equal(t, Version{6, 2, 0}, v)
+
+	_, err = Parse("ubuntu-latest")
+	require.Error(t, err)
+
+	require.Equal(t, 1, Compare(Version{7, 0, 0}, Version{6, 9, 9}))
+	require.Equal(t, -1, Compare(Version{6, 2, 1}, Version{6, 2, 5}))
+	require.Equal(t, 0, Compare(Version{6, 2, 5}, Version{6, 2, 5}))
+}
+
+func Test_V2_DataFormatCompatible(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     bool
+	}{
+		{"patch change", "ravendb/ravendb:6.2.1-ubuntu.22.04-x64", "ravendb/ravendb:6.2.5-ubuntu.22.04-x64", true},
+		{"minor change", "ravendb/ravendb:6.0.108-ubuntu.22.04-x64", "ravendb/ravendb:6.2.5-ubuntu.22.04-x64", false},
+		{"major change", "ravendb/ravendb:6.2.5-ubuntu.22.04-x64", "ravendb/ravendb:7.0.0-ubuntu.22.04-x64", false},
+		{"registry with a port", "registry:5000/ravendb/ravendb:6.2.1", "registry:5000/ravendb/ravendb:6.2.5", true},
+		{"no tag", "ravendb/ravendb", "ravendb/ravendb:6.2.5", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, why := DataFormatCompatible(tt.old, tt.new)
+			require.Equal(t, tt.want, ok)
+			if !ok {
+				require.NotEmpty(t, why)
+			}
+		})
+	}
+}
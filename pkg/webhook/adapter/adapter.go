@@ -16,7 +16,41 @@ limitations under the License.
 
 package adapter
 
+import corev1 "k8s.io/api/core/v1"
+
+// PodTemplate is spec.podTemplate or a node's podTemplate, Path is the field it was set in
+type PodTemplate struct {
+	Path                      string
+	Resources                 *corev1.ResourceRequirements
+	NodeSelector              map[string]string
+	Tolerations               []corev1.Toleration
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint
+	PriorityClassName         string
+	Labels                    map[string]string
+	Annotations               map[string]string
+}
+
+// Container is one of spec.sidecars or spec.initContainers, Path is the field it was set in
+type Container struct {
+	Path         string
+	Name         string
+	Ports        []corev1.ContainerPort
+	VolumeMounts []corev1.VolumeMount
+}
+
+// UpgradeGate is one of spec.upgrade.gates, Path is the field it was set in
+type UpgradeGate struct {
+	Path   string
+	Name   string
+	Params map[string]string
+}
+
 type ClusterAdapter interface {
+	// from the object metadata
+	GetName() string
+	GetNamespace() string
+	GetAnnotations() map[string]string
+
 	GetImage() string
 	GetIpp() string
 	SetIpp(string)
@@ -30,6 +64,7 @@ type ClusterAdapter interface {
 	GetNodePublicUrls() []string
 	GetNodeTcpUrls() []string
 	GetNodeCertSecretRefs() []*string
+	GetNodeWatchers() []bool
 	GetExternalAccessType() string
 	GetIngressClassName() string
 	GetIngressAnnotations() map[string]string
@@ -54,4 +89,11 @@ type ClusterAdapter interface {
 	GetAdditionalVolumeSources() []map[string]bool
 	GetClientCertSecretRef() string
 	GetCACertSecretRef() *string
+	GetPodTemplates() []PodTemplate
+	GetSidecars() []Container
+	GetUpgradeGates() []UpgradeGate
+	IsLogsRavenSet() bool
+	IsLogsAuditSet() bool
+	IsNetworkPolicyEnabled() bool
+	GetIngressControllerNamespace() string
 }
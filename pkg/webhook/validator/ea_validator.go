@@ -85,6 +85,13 @@ func (v *eaValidator) ValidateCreate(ctx context.Context, c ClusterAdapter) erro
 
 		errs = append(errs, validateIngressAnnotations(annotations)...)
 
+		// the NetworkPolicy admits the ingress controller by namespace
+		if c.IsNetworkPolicyEnabled() && c.GetIngressControllerNamespace() == "" {
+			errs = append(errs, fmt.Sprintf(
+				"spec.networkPolicy.ingressControllerNamespace is required for ingress class '%s', only nginx, traefik and haproxy have a default",
+				c.GetIngressClassName()))
+		}
+
 	default:
 		errs = append(errs, fmt.Sprintf("spec.externalAccessConfiguration.type has invalid value: '%s'", typeVal))
 	}
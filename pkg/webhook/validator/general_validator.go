@@ -50,14 +50,15 @@ func (v *generalValidator) ValidateCreate(ctx context.Context, c ClusterAdapter)
 	envVars := c.GetEnv()
 	clientCert := c.GetClientCertSecretRef()
 	caCert := c.GetCACertSecretRef()
+	ns := c.GetNamespace()
 
 	errs = append(errs, ValidateEmail(mode, email)...)
-	errs = append(errs, ValidateLicenseSecret(v, ctx, license)...)
-	errs = append(errs, ValidateClusterCertSecret(v, ctx, mode, clusterCert)...)
+	errs = append(errs, ValidateLicenseSecret(v, ctx, ns, license)...)
+	errs = append(errs, ValidateClusterCertSecret(v, ctx, ns, mode, clusterCert)...)
 	errs = append(errs, ValidateDomain(domain)...)
 	errs = append(errs, ValidateEnv(envVars)...)
-	errs = append(errs, ValidateClientCertSecret(v, ctx, clientCert)...)
-	errs = append(errs, ValidateCACertSecret(v, ctx, mode, caCert)...)
+	errs = append(errs, ValidateClientCertSecret(v, ctx, ns, clientCert)...)
+	errs = append(errs, ValidateCACertSecret(v, ctx, ns, mode, caCert)...)
 
 	if len(errs) > 0 {
 		return fmt.Errorf("%s", strings.Join(errs, "\n"))
@@ -91,10 +92,10 @@ func ValidateEmail(mode, email string) []string {
 	return errs
 }
 
-func ValidateLicenseSecret(v *generalValidator, ctx context.Context, license string) []string {
+func ValidateLicenseSecret(v *generalValidator, ctx context.Context, ns, license string) []string {
 	var errs []string
 
-	secret, err := v.getSecret(ctx, license)
+	secret, err := v.getSecret(ctx, ns, license)
 	if err != nil {
 		errs = append(errs, fmt.Sprintf("spec.licenseSecretRef: %v", err))
 		return errs
@@ -114,7 +115,7 @@ func ValidateLicenseSecret(v *generalValidator, ctx context.Context, license str
 	return errs
 }
 
-func ValidateClusterCertSecret(v *generalValidator, ctx context.Context, mode, clusterCert string) []string {
+func ValidateClusterCertSecret(v *generalValidator, ctx context.Context, ns, mode, clusterCert string) []string {
 	var errs []string
 
 	if mode == "LetsEncrypt" {
@@ -129,7 +130,7 @@ func ValidateClusterCertSecret(v *generalValidator, ctx context.Context, mode, c
 		return errs
 	}
 
-	secret, err := v.getSecret(ctx, clusterCert)
+	secret, err := v.getSecret(ctx, ns, clusterCert)
 	if err != nil {
 		errs = append(errs, fmt.Sprintf("spec.clusterCertSecretRef: %v", err))
 		return errs
@@ -182,18 +183,19 @@ func isValidFQDN(s string) bool {
 	return ip == nil
 }
 
-func (v *generalValidator) getSecret(ctx context.Context, name string) (*corev1.Secret, error) {
+// secrets are looked up in the namespace of the RavenDBCluster
+func (v *generalValidator) getSecret(ctx context.Context, ns, name string) (*corev1.Secret, error) {
 	var secret corev1.Secret
-	if err := v.client.Get(ctx, client.ObjectKey{Name: name, Namespace: "ravendb"}, &secret); err != nil {
-		return nil, fmt.Errorf("secret '%s' not found", name)
+	if err := v.client.Get(ctx, client.ObjectKey{Name: name, Namespace: ns}, &secret); err != nil {
+		return nil, fmt.Errorf("secret '%s' not found in namespace '%s'", name, ns)
 	}
 	return &secret, nil
 }
 
-func ValidateClientCertSecret(v *generalValidator, ctx context.Context, clientCert string) []string {
+func ValidateClientCertSecret(v *generalValidator, ctx context.Context, ns, clientCert string) []string {
 	var errs []string
 
-	secret, err := v.getSecret(ctx, clientCert)
+	secret, err := v.getSecret(ctx, ns, clientCert)
 	if err != nil {
 		errs = append(errs, fmt.Sprintf("spec.clientCertSecretRef: %v", err))
 		return errs
@@ -213,7 +215,7 @@ func ValidateClientCertSecret(v *generalValidator, ctx context.Context, clientCe
 	return errs
 }
 
-func ValidateCACertSecret(v *generalValidator, ctx context.Context, mode string, caCert *string) []string {
+func ValidateCACertSecret(v *generalValidator, ctx context.Context, ns, mode string, caCert *string) []string {
 	var errs []string
 
 	if mode == "LetsEncrypt" {
@@ -228,7 +230,7 @@ func ValidateCACertSecret(v *generalValidator, ctx context.Context, mode string,
 		return errs
 	}
 
-	secret, err := v.getSecret(ctx, *caCert)
+	secret, err := v.getSecret(ctx, ns, *caCert)
 	if err != nil {
 		errs = append(errs, fmt.Sprintf("spec.caCertSecretRef: %v", err))
 		return errs
@@ -257,14 +259,75 @@ func ValidateImmutableOnceCreated(_ context.Context, oldC, newC adapter.ClusterA
 	if oldC.GetDomain() != newC.GetDomain() {
 		errs = append(errs, "spec.domain is immutable after creation")
 	}
-	if strings.Join(oldC.GetNodeTags(), "|") != strings.Join(newC.GetNodeTags(), "|") {
-		errs = append(errs, "spec.nodes[].tag is immutable after creation")
+
+	errs = append(errs, validateNodeListChange(oldC, newC)...)
+
+	return errs
+}
+
+// nodes can be appended (scale out) or removed (scale in), but the nodes that stay are immutable,
+// keep their order, and the first node (the one that formed the cluster) can't be removed.
+func validateNodeListChange(oldC, newC adapter.ClusterAdapter) []string {
+	var errs []string
+
+	oldTags, newTags := oldC.GetNodeTags(), newC.GetNodeTags()
+	oldPub, newPub := oldC.GetNodePublicUrls(), newC.GetNodePublicUrls()
+	oldTcp, newTcp := oldC.GetNodeTcpUrls(), newC.GetNodeTcpUrls()
+	oldWatch, newWatch := oldC.GetNodeWatchers(), newC.GetNodeWatchers()
+
+	oldIdx := make(map[string]int, len(oldTags))
+	for i, t := range oldTags {
+		oldIdx[t] = i
+	}
+	newIdx := make(map[string]int, len(newTags))
+	for i, t := range newTags {
+		newIdx[t] = i
+	}
+
+	removed := 0
+	for _, t := range oldTags {
+		if _, ok := newIdx[t]; !ok {
+			removed++
+		}
 	}
-	if strings.Join(oldC.GetNodePublicUrls(), "|") != strings.Join(newC.GetNodePublicUrls(), "|") {
-		errs = append(errs, "spec.nodes[].publicServerUrl is immutable after creation")
+	added := len(newTags) - (len(oldTags) - removed)
+
+	if len(oldTags) > 0 {
+		if _, ok := newIdx[oldTags[0]]; !ok {
+			errs = append(errs, fmt.Sprintf("spec.nodes: node '%s' formed the cluster and can't be removed", oldTags[0]))
+		}
+	}
+
+	if removed > 0 && added > 0 {
+		errs = append(errs, "spec.nodes[].tag is immutable after creation (nodes can be appended or removed, not both in one update)")
+		return errs
 	}
-	if strings.Join(oldC.GetNodeTcpUrls(), "|") != strings.Join(newC.GetNodeTcpUrls(), "|") {
-		errs = append(errs, "spec.nodes[].publicServerUrlTcp is immutable after creation")
+
+	last := -1
+	for i, t := range newTags {
+		oi, existed := oldIdx[t]
+		if !existed {
+			if i < len(newTags)-added {
+				errs = append(errs, "spec.nodes[].tag is immutable after creation (new nodes may only be appended)")
+				return errs
+			}
+			continue
+		}
+		if oi < last {
+			errs = append(errs, "spec.nodes[].tag is immutable after creation (nodes can't be reordered)")
+			return errs
+		}
+		last = oi
+
+		if oldPub[oi] != newPub[i] {
+			errs = append(errs, "spec.nodes[].publicServerUrl is immutable after creation")
+		}
+		if oldTcp[oi] != newTcp[i] {
+			errs = append(errs, "spec.nodes[].publicServerUrlTcp is immutable after creation")
+		}
+		if oldWatch[oi] != newWatch[i] {
+			errs = append(errs, "spec.nodes[].watcher is immutable after creation")
+		}
 	}
 
 	return errs
@@ -19,11 +19,11 @@ package validator
 import (
 	"context"
 	"fmt"
-	"regexp"
-	"strconv"
 	"strings"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"ravendb-operator/pkg/version"
 )
 
 type imageValidator struct {
@@ -50,8 +50,8 @@ func (v *imageValidator) ValidateUpdate(ctx context.Context, oldC, newC ClusterA
 		return err
 	}
 
-	oldTag, _ := extractTag(oldC.GetImage())
-	newTag, _ := extractTag(newC.GetImage())
+	oldTag, _ := version.Tag(oldC.GetImage())
+	newTag, _ := version.Tag(newC.GetImage())
 	return compareTagsDowngrade(oldTag, newTag)
 }
 
@@ -66,7 +66,7 @@ func validateImage(image string) error {
 	if hasDigest(image) {
 		return fmt.Errorf("digest references are not allowed. use a concrete tag (e.g., ':7.1.3-ubuntu.22.04-x64')")
 	}
-	tag, ok := extractTag(image)
+	tag, ok := version.Tag(image)
 	if !ok || tag == "" {
 		return fmt.Errorf("image must specify a tag; implicit ':latest' is not allowed")
 	}
@@ -80,8 +80,8 @@ func validateImage(image string) error {
 }
 
 func compareTagsDowngrade(oldTag, newTag string) error {
-	oldVer, errOld := parseRavenVersion(oldTag)
-	newVer, errNew := parseRavenVersion(newTag)
+	oldVer, errOld := version.Parse(oldTag)
+	newVer, errNew := version.Parse(newTag)
 	if errOld != nil || errNew != nil {
 		return fmt.Errorf(
 			"unable to parse RavenDB versions from tags (old=%q err=%v, new=%q err=%v). "+
@@ -89,12 +89,8 @@ func compareTagsDowngrade(oldTag, newTag string) error {
 			oldTag, errOld, newTag, errNew,
 		)
 	}
-	if compareSemver(oldVer, newVer) == 1 {
-		return fmt.Errorf(
-			"downgrade is not allowed: %s (v%d.%d.%d) -> %s (v%d.%d.%d)",
-			oldTag, oldVer.major, oldVer.minor, oldVer.patch,
-			newTag, newVer.major, newVer.minor, newVer.patch,
-		)
+	if version.Compare(oldVer, newVer) == 1 {
+		return fmt.Errorf("downgrade is not allowed: %s (%s) -> %s (%s)", oldTag, oldVer, newTag, newVer)
 	}
 	return nil
 }
@@ -107,25 +103,6 @@ func hasDigest(image string) bool {
 	return strings.Contains(image, "@sha256:")
 }
 
-func extractTag(image string) (string, bool) {
-	if i := strings.Index(image, "@"); i != -1 {
-		image = image[:i]
-	}
-
-	lastSlash := strings.LastIndex(image, "/")
-	lastColon := strings.LastIndex(image, ":")
-
-	if lastColon == -1 || lastColon < lastSlash {
-		return "", false
-	}
-
-	tag := image[lastColon+1:]
-	if tag == "" {
-		return "", false
-	}
-	return tag, true
-}
-
 func isFloatingTag(tag string) bool {
 	return strings.Contains(tag, "latest")
 }
@@ -133,51 +110,3 @@ func isFloatingTag(tag string) bool {
 func isUbuntuTag(tag string) bool {
 	return strings.Contains(tag, "ubuntu.")
 }
-
-// parses the version at the start of the tag, e.g. "7.1.3" in: "7.1.3-ubuntu.22.04-x64"
-var leadingSemverRE = regexp.MustCompile(`^(\d+)\.(\d+)(?:\.(\d+))?`)
-
-type semver struct {
-	major int
-	minor int
-	patch int
-}
-
-func parseRavenVersion(tag string) (semver, error) {
-	m := leadingSemverRE.FindStringSubmatch(tag)
-	if len(m) == 0 {
-		return semver{}, fmt.Errorf("no leading semver in tag %q", tag)
-	}
-
-	maj, _ := strconv.Atoi(m[1])
-	min, _ := strconv.Atoi(m[2])
-	pat := 0
-	if len(m) > 3 && m[3] != "" {
-		pat, _ = strconv.Atoi(m[3])
-	}
-	return semver{maj, min, pat}, nil
-}
-
-func compareSemver(a, b semver) int {
-	if a.major != b.major {
-		if a.major > b.major {
-			return 1
-		}
-		return -1
-	}
-
-	if a.minor != b.minor {
-		if a.minor > b.minor {
-			return 1
-		}
-		return -1
-	}
-
-	if a.patch != b.patch {
-		if a.patch > b.patch {
-			return 1
-		}
-		return -1
-	}
-	return 0
-}
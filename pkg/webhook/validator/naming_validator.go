@@ -0,0 +1,193 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validator
+
+import (
+	"context"
+	"fmt"
+	"ravendb-operator/pkg/naming"
+	"ravendb-operator/pkg/webhook/adapter"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// namingValidator checks the names the operator derives from the cluster name (see pkg/naming):
+// they must be valid, and must not belong to anything else in the namespace (e.g. another RavenDBCluster).
+type namingValidator struct {
+	client client.Reader
+}
+
+func NewNamingValidator(c client.Reader) *namingValidator {
+	return &namingValidator{client: c}
+}
+
+func (v *namingValidator) Name() string {
+	return "naming-validator"
+}
+
+func (v *namingValidator) ValidateCreate(ctx context.Context, c ClusterAdapter) error {
+	return v.validate(ctx, c)
+}
+
+// children are checked on update as well, new nodes get new StatefulSets and Services
+func (v *namingValidator) ValidateUpdate(ctx context.Context, _, newC ClusterAdapter) error {
+	return v.validate(ctx, newC)
+}
+
+func (v *namingValidator) validate(ctx context.Context, c ClusterAdapter) error {
+	var errs []string
+
+	errs = append(errs, ValidateChildNames(c)...)
+	if len(errs) == 0 {
+		errs = append(errs, ValidateChildNameConflicts(ctx, v.client, c)...)
+		errs = append(errs, ValidateIngressHostConflicts(ctx, v.client, c)...)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+func ValidateChildNames(c ClusterAdapter) []string {
+	var errs []string
+	names := naming.For(c)
+
+	for _, tag := range c.GetNodeTags() {
+		name := names.Node(tag)
+		if len(name) > naming.MaxStatefulSetNameLength {
+			errs = append(errs, fmt.Sprintf("metadata.name: StatefulSet name '%s' for node '%s' must be no more than %d characters, use a shorter cluster name", name, tag, naming.MaxStatefulSetNameLength))
+			continue
+		}
+		for _, msg := range validation.IsDNS1035Label(name) {
+			errs = append(errs, fmt.Sprintf("metadata.name: Service name '%s' for node '%s' is invalid: %s", name, tag, msg))
+		}
+	}
+
+	name := names.GoverningService()
+	for _, msg := range validation.IsDNS1035Label(name) {
+		errs = append(errs, fmt.Sprintf("metadata.name: '%s' is invalid: %s", name, msg))
+	}
+
+	return errs
+}
+
+// ValidateChildNameConflicts rejects the cluster when an object it would create already exists
+// and isn't controlled by this cluster
+func ValidateChildNameConflicts(ctx context.Context, r client.Reader, c ClusterAdapter) []string {
+	var errs []string
+	names := naming.For(c)
+
+	type child struct {
+		kind string
+		obj  client.Object
+		name string
+	}
+	var children []child
+	for _, tag := range c.GetNodeTags() {
+		children = append(children,
+			child{"StatefulSet", &appsv1.StatefulSet{}, names.Node(tag)},
+			child{"Service", &corev1.Service{}, names.Node(tag)},
+		)
+	}
+	children = append(children,
+		child{"Service", &corev1.Service{}, names.GoverningService()},
+		child{"ConfigMap", &corev1.ConfigMap{}, names.CertHookConfigMap()},
+		child{"PodDisruptionBudget", &policyv1.PodDisruptionBudget{}, names.PodDisruptionBudget()},
+	)
+	// a legacy cluster takes over the node ServiceAccount, Role and RoleBinding that were created by hand
+	if !names.Legacy() {
+		children = append(children,
+			child{"ServiceAccount", &corev1.ServiceAccount{}, names.NodeServiceAccount()},
+			child{"Role", &rbacv1.Role{}, names.NodeRole()},
+			child{"RoleBinding", &rbacv1.RoleBinding{}, names.NodeRoleBinding()},
+		)
+	}
+	if c.IsNetworkPolicyEnabled() {
+		children = append(children, child{"NetworkPolicy", &networkingv1.NetworkPolicy{}, names.NetworkPolicy()})
+	}
+	if c.IsIngressContextSet() {
+		children = append(children, child{"Ingress", &networkingv1.Ingress{}, names.Ingress()})
+	}
+
+	for _, ch := range children {
+		err := r.Get(ctx, client.ObjectKey{Namespace: c.GetNamespace(), Name: ch.name}, ch.obj)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("metadata.name: failed to check %s '%s': %v", ch.kind, ch.name, err))
+			continue
+		}
+		if !controlledBy(ch.obj, c) {
+			errs = append(errs, fmt.Sprintf("metadata.name: %s '%s' already exists in namespace '%s' and doesn't belong to this cluster", ch.kind, ch.name, c.GetNamespace()))
+		}
+	}
+
+	return errs
+}
+
+// ValidateIngressHostConflicts rejects node hosts ("<tag>.<domain>") another Ingress in the namespace already routes.
+// clusters sharing a namespace need their own domains.
+func ValidateIngressHostConflicts(ctx context.Context, r client.Reader, c ClusterAdapter) []string {
+	var errs []string
+	if !c.IsIngressContextSet() {
+		return errs
+	}
+
+	var list networkingv1.IngressList
+	if err := r.List(ctx, &list, client.InNamespace(c.GetNamespace())); err != nil {
+		return append(errs, fmt.Sprintf("spec.domain: failed to list ingresses: %v", err))
+	}
+
+	hosts := map[string]bool{}
+	for _, tag := range c.GetNodeTags() {
+		hosts[fmt.Sprintf("%s.%s", tag, c.GetDomain())] = true
+		hosts[fmt.Sprintf("%s-tcp.%s", tag, c.GetDomain())] = true
+	}
+
+	for i := range list.Items {
+		ing := &list.Items[i]
+		if controlledBy(ing, c) {
+			continue
+		}
+		for _, rule := range ing.Spec.Rules {
+			if hosts[rule.Host] {
+				errs = append(errs, fmt.Sprintf("spec.domain: host '%s' is already routed by ingress '%s'", rule.Host, ing.Name))
+			}
+		}
+	}
+
+	return errs
+}
+
+func controlledBy(obj client.Object, c adapter.ClusterAdapter) bool {
+	for _, o := range obj.GetOwnerReferences() {
+		if o.Controller != nil && *o.Controller && o.Kind == "RavenDBCluster" && o.Name == c.GetName() {
+			return true
+		}
+	}
+	return false
+}
@@ -74,13 +74,14 @@ func (v *nodeValidator) ValidateCreate(ctx context.Context, c ClusterAdapter) er
 	}
 	errs = append(errs, ValidateNodesNotEmpty(tags)...)
 	errs = append(errs, ValidateUniqueTags(tags)...)
+	errs = append(errs, ValidateFirstNodeIsMember(c.GetNodeWatchers())...)
 	errs = append(errs, ValidateUniqueUrls(pubUrls, tcpUrls)...)
 	errs = append(errs, ValidatePortsConsistency(pubUrls, tcpUrls, extAccType)...)
 
 	for _, n := range input {
 		errs = append(errs, ValidateNodeUrl(n.Tag, n.PublicUrl, domain, "https", "publicServerUrl", n.Tag+".")...)
 		errs = append(errs, ValidateNodeUrl(n.Tag, n.TcpUrl, domain, "tcp", "publicServerUrlTcp", n.Tag+"-tcp.")...)
-		errs = append(errs, ValidateNodeCertSecret(ctx, v, mode, n.Tag, n.CertSecret)...)
+		errs = append(errs, ValidateNodeCertSecret(ctx, v, c.GetNamespace(), mode, n.Tag, n.CertSecret)...)
 	}
 
 	if len(errs) > 0 {
@@ -100,6 +101,14 @@ func ValidateNodesNotEmpty(tags []string) []string {
 	return nil
 }
 
+// the first node forms the cluster, so it can't start as a watcher
+func ValidateFirstNodeIsMember(watchers []bool) []string {
+	if len(watchers) > 0 && watchers[0] {
+		return []string{"spec.nodes[0].watcher: the first node forms the cluster and must be a member"}
+	}
+	return nil
+}
+
 func ValidateUniqueTags(tags []string) []string {
 	var errs []string
 	seen := map[string]bool{}
@@ -206,7 +215,7 @@ func ValidateNodeUrl(tag, rawUrl, domain, expectedScheme, labelPrefix, expectedH
 	return errs
 }
 
-func ValidateNodeCertSecret(ctx context.Context, v *nodeValidator, mode, tag, secretName string) []string {
+func ValidateNodeCertSecret(ctx context.Context, v *nodeValidator, ns, mode, tag, secretName string) []string {
 	var errs []string
 	label := fmt.Sprintf("spec.nodes[tag=%s].certsSecretRef", tag)
 
@@ -221,7 +230,7 @@ func ValidateNodeCertSecret(ctx context.Context, v *nodeValidator, mode, tag, se
 		return errs
 	}
 
-	secret, err := v.getSecret(ctx, secretName)
+	secret, err := v.getSecret(ctx, ns, secretName)
 	if err != nil {
 		errs = append(errs, fmt.Sprintf("%s: %v", label, err))
 		return errs
@@ -255,11 +264,11 @@ func extractPort(raw string) string {
 	return port
 }
 
-func (v *nodeValidator) getSecret(ctx context.Context, name string) (*corev1.Secret, error) {
+func (v *nodeValidator) getSecret(ctx context.Context, ns, name string) (*corev1.Secret, error) {
 	var secret corev1.Secret
-	err := v.client.Get(ctx, client.ObjectKey{Name: name, Namespace: "ravendb"}, &secret)
+	err := v.client.Get(ctx, client.ObjectKey{Name: name, Namespace: ns}, &secret)
 	if err != nil {
-		return nil, fmt.Errorf("secret '%s' not found", name)
+		return nil, fmt.Errorf("secret '%s' not found in namespace '%s'", name, ns)
 	}
 	return &secret, nil
 }
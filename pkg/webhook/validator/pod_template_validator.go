@@ -0,0 +1,183 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validator
+
+import (
+	"context"
+	"fmt"
+	"ravendb-operator/pkg/webhook/adapter"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// the labels the operator sets on the node pods and selects them by (common.Label*)
+var reservedPodLabels = []string{
+	"app.kubernetes.io/name",
+	"app.kubernetes.io/instance",
+	"app.kubernetes.io/managed-by",
+	"nodeTag",
+}
+
+type podTemplateValidator struct {
+	client client.Reader
+}
+
+func NewPodTemplateValidator(c client.Reader) *podTemplateValidator {
+	return &podTemplateValidator{client: c}
+}
+
+func (v *podTemplateValidator) Name() string {
+	return "pod-template-validator"
+}
+
+func (v *podTemplateValidator) ValidateCreate(ctx context.Context, c ClusterAdapter) error {
+	var errs []string
+
+	for _, pt := range c.GetPodTemplates() {
+		errs = append(errs, ValidatePodLabels(pt.Path, pt.Labels)...)
+		errs = append(errs, ValidatePodAnnotations(pt.Path, pt.Annotations)...)
+		errs = append(errs, ValidatePodResources(pt.Path, pt.Resources)...)
+		errs = append(errs, ValidateTolerations(pt.Path, pt.Tolerations)...)
+		errs = append(errs, ValidateTopologySpreadConstraints(pt.Path, pt.TopologySpreadConstraints)...)
+		errs = append(errs, ValidatePriorityClassName(pt)...)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+func (v *podTemplateValidator) ValidateUpdate(ctx context.Context, _, newC ClusterAdapter) error {
+	return v.ValidateCreate(ctx, newC)
+}
+
+func ValidatePodLabels(path string, labels map[string]string) []string {
+	var errs []string
+
+	for _, k := range sortedKeys(labels) {
+		for _, r := range reservedPodLabels {
+			if k == r {
+				errs = append(errs, fmt.Sprintf("%s.labels: '%s' is set by the operator and can't be overridden", path, k))
+			}
+		}
+		for _, msg := range validation.IsQualifiedName(k) {
+			errs = append(errs, fmt.Sprintf("%s.labels: invalid key '%s': %s", path, k, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(labels[k]) {
+			errs = append(errs, fmt.Sprintf("%s.labels[%s]: invalid value '%s': %s", path, k, labels[k], msg))
+		}
+	}
+
+	return errs
+}
+
+func ValidatePodAnnotations(path string, annotations map[string]string) []string {
+	var errs []string
+
+	for _, k := range sortedKeys(annotations) {
+		for _, msg := range validation.IsQualifiedName(strings.ToLower(k)) {
+			errs = append(errs, fmt.Sprintf("%s.annotations: invalid key '%s': %s", path, k, msg))
+		}
+	}
+
+	return errs
+}
+
+// a request above its limit is rejected by the API server only once the StatefulSet creates the pod
+func ValidatePodResources(path string, res *corev1.ResourceRequirements) []string {
+	var errs []string
+	if res == nil {
+		return errs
+	}
+
+	for name, limit := range res.Limits {
+		if req, ok := res.Requests[name]; ok && req.Cmp(limit) > 0 {
+			errs = append(errs, fmt.Sprintf("%s.resources: %s request %s must not exceed the limit %s", path, name, req.String(), limit.String()))
+		}
+	}
+	sort.Strings(errs)
+
+	return errs
+}
+
+func ValidateTolerations(path string, tolerations []corev1.Toleration) []string {
+	var errs []string
+
+	for i, t := range tolerations {
+		label := fmt.Sprintf("%s.tolerations[%d]", path, i)
+		if t.Operator == corev1.TolerationOpExists && t.Value != "" {
+			errs = append(errs, fmt.Sprintf("%s: value must be empty when operator is Exists", label))
+		}
+		if t.Key == "" && t.Operator != corev1.TolerationOpExists {
+			errs = append(errs, fmt.Sprintf("%s: operator must be Exists when key is empty", label))
+		}
+		if t.TolerationSeconds != nil && t.Effect != corev1.TaintEffectNoExecute {
+			errs = append(errs, fmt.Sprintf("%s: tolerationSeconds is only allowed with effect NoExecute", label))
+		}
+	}
+
+	return errs
+}
+
+func ValidateTopologySpreadConstraints(path string, constraints []corev1.TopologySpreadConstraint) []string {
+	var errs []string
+
+	for i, c := range constraints {
+		label := fmt.Sprintf("%s.topologySpreadConstraints[%d]", path, i)
+		if c.MaxSkew < 1 {
+			errs = append(errs, fmt.Sprintf("%s.maxSkew: must be at least 1", label))
+		}
+		if c.TopologyKey == "" {
+			errs = append(errs, fmt.Sprintf("%s.topologyKey: must be set", label))
+		}
+		if c.WhenUnsatisfiable != corev1.DoNotSchedule && c.WhenUnsatisfiable != corev1.ScheduleAnyway {
+			errs = append(errs, fmt.Sprintf("%s.whenUnsatisfiable: must be DoNotSchedule or ScheduleAnyway", label))
+		}
+		if c.LabelSelector == nil {
+			errs = append(errs, fmt.Sprintf("%s.labelSelector: must be set, without it the constraint doesn't count any pod", label))
+		}
+	}
+
+	return errs
+}
+
+func ValidatePriorityClassName(pt adapter.PodTemplate) []string {
+	var errs []string
+	if pt.PriorityClassName == "" {
+		return errs
+	}
+
+	for _, msg := range validation.IsDNS1123Subdomain(pt.PriorityClassName) {
+		errs = append(errs, fmt.Sprintf("%s.priorityClassName: %s", pt.Path, msg))
+	}
+
+	return errs
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
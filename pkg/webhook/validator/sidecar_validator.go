@@ -0,0 +1,186 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validator
+
+import (
+	"context"
+	"fmt"
+	"ravendb-operator/pkg/webhook/adapter"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// what the RavenDB container already uses in the pod (common.App, common.*PortName, common.Internal*Port)
+const builtinContainerName = "ravendb"
+
+var (
+	builtinPortNames   = map[string]bool{"https": true, "tcp": true}
+	builtinPortNumbers = map[int32]bool{443: true, 38888: true}
+)
+
+// the operator's volumes a sidecar may mount (read only), the license and the cert hook scripts stay private
+const (
+	dataVolumeName  = "ravendb-data"
+	certVolumeName  = "ravendb-cert"
+	logsVolumeName  = "ravendb-logs"
+	auditVolumeName = "ravendb-audit"
+)
+
+var privateVolumeNames = map[string]bool{"ravendb-license": true, "ravendb-cert-hook": true}
+
+type sidecarValidator struct {
+	client client.Reader
+}
+
+func NewSidecarValidator(c client.Reader) *sidecarValidator {
+	return &sidecarValidator{client: c}
+}
+
+func (v *sidecarValidator) Name() string {
+	return "sidecar-validator"
+}
+
+func (v *sidecarValidator) ValidateCreate(ctx context.Context, c ClusterAdapter) error {
+	var errs []string
+
+	sidecars := c.GetSidecars()
+	if len(sidecars) == 0 {
+		return nil
+	}
+
+	errs = append(errs, ValidateSidecarNames(sidecars)...)
+	errs = append(errs, ValidateSidecarPorts(sidecars)...)
+	errs = append(errs, ValidateSidecarVolumeMounts(sidecars, mountableVolumes(c))...)
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+func (v *sidecarValidator) ValidateUpdate(ctx context.Context, _, newC ClusterAdapter) error {
+	return v.ValidateCreate(ctx, newC)
+}
+
+func ValidateSidecarNames(sidecars []adapter.Container) []string {
+	var errs []string
+	seen := map[string]string{}
+
+	for _, s := range sidecars {
+		for _, msg := range validation.IsDNS1123Label(s.Name) {
+			errs = append(errs, fmt.Sprintf("%s.name: '%s' is invalid: %s", s.Path, s.Name, msg))
+		}
+		if s.Name == builtinContainerName {
+			errs = append(errs, fmt.Sprintf("%s.name: '%s' is the RavenDB container", s.Path, s.Name))
+			continue
+		}
+		if other, exists := seen[s.Name]; exists {
+			errs = append(errs, fmt.Sprintf("%s.name: '%s' is already used by %s", s.Path, s.Name, other))
+			continue
+		}
+		seen[s.Name] = s.Path
+	}
+
+	return errs
+}
+
+// every container of the pod shares its network namespace, a sidecar can't listen where RavenDB
+// or another sidecar does. init containers run before the others and may reuse ports.
+func ValidateSidecarPorts(sidecars []adapter.Container) []string {
+	var errs []string
+	names := map[string]string{}
+	numbers := map[string]string{}
+
+	for _, s := range sidecars {
+		for i, p := range s.Ports {
+			label := fmt.Sprintf("%s.ports[%d]", s.Path, i)
+
+			if p.Name != "" {
+				if builtinPortNames[p.Name] {
+					errs = append(errs, fmt.Sprintf("%s.name: '%s' is a port of the RavenDB container", label, p.Name))
+				} else if other, exists := names[p.Name]; exists {
+					errs = append(errs, fmt.Sprintf("%s.name: '%s' is already used by %s", label, p.Name, other))
+				} else {
+					names[p.Name] = label
+				}
+			}
+
+			if strings.HasPrefix(s.Path, "spec.initContainers") {
+				continue
+			}
+			if builtinPortNumbers[p.ContainerPort] {
+				errs = append(errs, fmt.Sprintf("%s.containerPort: %d is a port of the RavenDB container", label, p.ContainerPort))
+				continue
+			}
+			protocol := p.Protocol
+			if protocol == "" {
+				protocol = corev1.ProtocolTCP
+			}
+			key := fmt.Sprintf("%d/%s", p.ContainerPort, protocol)
+			if other, exists := numbers[key]; exists {
+				errs = append(errs, fmt.Sprintf("%s.containerPort: %s is already used by %s", label, key, other))
+				continue
+			}
+			numbers[key] = label
+		}
+	}
+
+	return errs
+}
+
+func ValidateSidecarVolumeMounts(sidecars []adapter.Container, mountable map[string]bool) []string {
+	var errs []string
+
+	for _, s := range sidecars {
+		paths := map[string]bool{}
+		for i, m := range s.VolumeMounts {
+			label := fmt.Sprintf("%s.volumeMounts[%d]", s.Path, i)
+
+			switch {
+			case privateVolumeNames[m.Name]:
+				errs = append(errs, fmt.Sprintf("%s.name: volume '%s' can't be mounted by a sidecar", label, m.Name))
+			case !mountable[m.Name]:
+				errs = append(errs, fmt.Sprintf("%s.name: volume '%s' doesn't exist, use spec.storage.additionalVolumes or one of %s, %s, %s, %s",
+					label, m.Name, dataVolumeName, certVolumeName, logsVolumeName, auditVolumeName))
+			}
+
+			if paths[m.MountPath] {
+				errs = append(errs, fmt.Sprintf("%s.mountPath: '%s' is mounted twice", label, m.MountPath))
+			}
+			paths[m.MountPath] = true
+		}
+	}
+
+	return errs
+}
+
+func mountableVolumes(c ClusterAdapter) map[string]bool {
+	mountable := map[string]bool{dataVolumeName: true, certVolumeName: true}
+	if c.IsLogsRavenSet() {
+		mountable[logsVolumeName] = true
+	}
+	if c.IsLogsAuditSet() {
+		mountable[auditVolumeName] = true
+	}
+	for _, name := range c.GetAdditionalVolumeNames() {
+		mountable[name] = true
+	}
+	return mountable
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package validator
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// GateCatalog knows the upgrade gates, pkg/upgrade's GateRegistry implements it. the validators can't
+// import pkg/upgrade (it depends on the API types), the manager registers the validator with the registry.
+type GateCatalog interface {
+	GateNames() []string
+	ValidateGateParams(name string, params map[string]string) error
+}
+
+// upgradeGateValidator rejects spec.upgrade.gates entries the upgrader would ignore (unknown names)
+// or fail on in the middle of a rollout (bad params)
+type upgradeGateValidator struct {
+	gates GateCatalog
+}
+
+func NewUpgradeGateValidator(gates GateCatalog) *upgradeGateValidator {
+	return &upgradeGateValidator{gates: gates}
+}
+
+func (v *upgradeGateValidator) Name() string {
+	return "upgrade-gate-validator"
+}
+
+func (v *upgradeGateValidator) ValidateCreate(ctx context.Context, c ClusterAdapter) error {
+	var errs []string
+	names := v.gates.GateNames()
+
+	for _, g := range c.GetUpgradeGates() {
+		if !slices.Contains(names, g.Name) {
+			errs = append(errs, fmt.Sprintf("%s.name: unknown gate '%s', known gates are %s", g.Path, g.Name, strings.Join(names, ", ")))
+			continue
+		}
+		if err := v.gates.ValidateGateParams(g.Name, g.Params); err != nil {
+			errs = append(errs, fmt.Sprintf("%s.params: %v", g.Path, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+func (v *upgradeGateValidator) ValidateUpdate(ctx context.Context, _, newC ClusterAdapter) error {
+	return v.ValidateCreate(ctx, newC)
+}
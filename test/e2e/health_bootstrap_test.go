@@ -26,8 +26,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func TestBootstrap_B1_JobSucceeded_E2E(t *testing.T) {
-	testutil.RecreateTestEnv(t, rbacPath)
+func TestBootstrap_B1_Succeeded_E2E(t *testing.T) {
+	testutil.RecreateTestEnv(t)
 
 	cli, key := testutil.CreateCluster(t, testutil.BaseClusterLE, testutil.ClusterCase{
 		Name:      "bootstrap-b1-succeeded",
@@ -43,11 +43,14 @@ func TestBootstrap_B1_JobSucceeded_E2E(t *testing.T) {
 	require.True(t, ok)
 	require.Equal(t, string(ravendbv1.ReasonCompleted), cond.Reason)
 	require.Equal(t, ravendbv1.PhaseRunning, cur.Status.Phase)
+	require.NotNil(t, cur.Status.Bootstrap)
+	require.Equal(t, ravendbv1.BootstrapPhaseCompleted, cur.Status.Bootstrap.Phase)
+	require.Len(t, cur.Status.Bootstrap.Steps, len(ravendbv1.BootstrapSteps))
 
 }
 
-func TestBootstrap_B2_JobRunning_E2E(t *testing.T) {
-	testutil.RecreateTestEnv(t, rbacPath)
+func TestBootstrap_B2_InProgress_E2E(t *testing.T) {
+	testutil.RecreateTestEnv(t)
 
 	cli, key := testutil.CreateCluster(t, testutil.BaseClusterLE, testutil.ClusterCase{
 		Name:      "bootstrap-b2-running",
@@ -61,6 +64,6 @@ func TestBootstrap_B2_JobRunning_E2E(t *testing.T) {
 	require.NoError(t, cli.Get(context.Background(), key, cur))
 	cond, ok := testutil.GetCondition(cur, ravendbv1.ConditionBootstrapCompleted)
 	require.True(t, ok)
-	require.Equal(t, string(ravendbv1.ReasonBootstrapJobRunning), cond.Reason)
+	require.Equal(t, string(ravendbv1.ReasonBootstrapInProgress), cond.Reason)
 	require.Equal(t, ravendbv1.PhaseDeploying, cur.Status.Phase)
 }
@@ -29,7 +29,7 @@ import (
 )
 
 func TestNodes_N1_AllPodsHealthy_E2E(t *testing.T) {
-	testutil.RecreateTestEnv(t, rbacPath)
+	testutil.RecreateTestEnv(t)
 
 	cli, key := testutil.CreateCluster(t, testutil.BaseClusterLE, testutil.ClusterCase{
 		Name:      "nodes-n1-healthy",
@@ -47,7 +47,7 @@ func TestNodes_N1_AllPodsHealthy_E2E(t *testing.T) {
 }
 
 func TestNodes_N2_PodPending_E2E(t *testing.T) {
-	testutil.RecreateTestEnv(t, rbacPath)
+	testutil.RecreateTestEnv(t)
 
 	cli, key := testutil.CreateCluster(t, testutil.BaseClusterLE, testutil.ClusterCase{
 		Name:      "nodes-n2-pending",
@@ -55,7 +55,7 @@ func TestNodes_N2_PodPending_E2E(t *testing.T) {
 	})
 	testutil.RegisterClusterCleanup(t, cli, key, timeout)
 
-	podKey := testutil.ObjectKeyForPod(key.Namespace, "a")
+	podKey := testutil.ObjectKeyForPod(key, "a")
 	pod := testutil.WaitForPod(t, cli, podKey.Namespace, podKey.Name, 2*time.Minute)
 	pod.Status.Phase = corev1.PodPending
 	require.NoError(t, cli.Status().Update(context.Background(), pod))
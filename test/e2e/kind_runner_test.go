@@ -39,7 +39,6 @@ var (
 	nginxIngressFilePath  = "test/e2e/manifests/nginx-ingress-ravendb.yaml"
 	crdBasePath           = "config/crd/bases"
 	crdDefaultPath        = "config/default"
-	rbacPath              = "config/rbac"
 	dockerfileName        = "Dockerfile"
 )
 
@@ -29,7 +29,7 @@ import (
 )
 
 func TestUpgrade_62_71_happy_E2E(t *testing.T) {
-	testutil.RecreateTestEnv(t, rbacPath)
+	testutil.RecreateTestEnv(t)
 
 	const toImage = "ravendb/ravendb:7.1.3-ubuntu.22.04-x64"
 	cli, key := testutil.CreateCluster(t, testutil.BaseClusterLE, testutil.ClusterCase{
@@ -42,25 +42,25 @@ func TestUpgrade_62_71_happy_E2E(t *testing.T) {
 	testutil.WaitCondition(t, cli, key, ravendbv1.ConditionReady, metav1.ConditionTrue, timeout, 2*time.Second)
 
 	require.NoError(t,
-		ExtractServerCertToTmp(t.Context(), testutil.DefaultNS, "ravendb-a-0", "", "/ravendb/certs/server.pfx", ""),
+		ExtractServerCertToTmp(t.Context(), testutil.DefaultNS, testutil.PodName(key, "a"), "", "/ravendb/certs/server.pfx", ""),
 		"extract pem/key in pod",
 	)
 
 	require.NoError(t,
-		CreateDatabaseRF3(t.Context(), testutil.DefaultNS, "ravendb-a-0", "", "e2e_db"),
+		CreateDatabaseRF3(t.Context(), testutil.DefaultNS, testutil.PodName(key, "a"), "", "e2e_db"),
 		"failed to create RF3 DB",
 	)
 
 	testutil.PatchSpecImage(t, cli, key, toImage)
 
-	testutil.WaitPodImage(t, cli, testutil.DefaultNS, "ravendb-a-0", toImage, timeout)
-	t.Logf("ravendb-a-0 now running %s", toImage)
+	testutil.WaitPodImage(t, cli, testutil.DefaultNS, testutil.PodName(key, "a"), toImage, timeout)
+	t.Logf("%s now running %s", testutil.PodName(key, "a"), toImage)
 
-	testutil.WaitPodImage(t, cli, testutil.DefaultNS, "ravendb-b-0", toImage, timeout)
-	t.Logf("ravendb-b-0 now running %s", toImage)
+	testutil.WaitPodImage(t, cli, testutil.DefaultNS, testutil.PodName(key, "b"), toImage, timeout)
+	t.Logf("%s now running %s", testutil.PodName(key, "b"), toImage)
 
-	testutil.WaitPodImage(t, cli, testutil.DefaultNS, "ravendb-c-0", toImage, timeout)
-	t.Logf("ravendb-c-0 now running %s", toImage)
+	testutil.WaitPodImage(t, cli, testutil.DefaultNS, testutil.PodName(key, "c"), toImage, timeout)
+	t.Logf("%s now running %s", testutil.PodName(key, "c"), toImage)
 
 	testutil.WaitCondition(t, cli, key, ravendbv1.ConditionReady, metav1.ConditionTrue, timeout, 2*time.Second)
 	t.Logf("cluster marked as upgraded and healthy ConditionReady=True")
@@ -104,14 +104,14 @@ func TestUpgrade_62_71_happy_E2E(t *testing.T) {
 	RequirePodsRavenVersion(
 		t,
 		testutil.DefaultNS,
-		[]string{"ravendb-a-0", "ravendb-b-0", "ravendb-c-0"},
+		[]string{testutil.PodName(key, "a"), testutil.PodName(key, "b"), testutil.PodName(key, "c")},
 		"7.1.3",
 		20*time.Second,
 	)
 }
 
 func TestUpgrade_62_71_pre_cluster_conn_fail_on_a_bc_b_down_E2E(t *testing.T) {
-	testutil.RecreateTestEnv(t, rbacPath)
+	testutil.RecreateTestEnv(t)
 
 	const toImage = "ravendb/ravendb:7.1.3-ubuntu.22.04-x64"
 
@@ -134,8 +134,8 @@ func TestUpgrade_62_71_pre_cluster_conn_fail_on_a_bc_b_down_E2E(t *testing.T) {
 	)
 	require.NoError(t, err, "patch secret ravendb-certs-b failed")
 
-	_, err = testutil.RunKubectl(ctx, "-n", testutil.DefaultNS, "delete", "pod", "ravendb-b-0", "--wait=false")
-	require.NoError(t, err, "delete pod ravendb-b-0 failed")
+	_, err = testutil.RunKubectl(ctx, "-n", testutil.DefaultNS, "delete", "pod", testutil.PodName(key, "b"), "--wait=false")
+	require.NoError(t, err, "delete pod %s failed", testutil.PodName(key, "b"))
 
 	testutil.PatchSpecImage(t, cli, key, toImage)
 	fetch := func() (string, error) { return testutil.OperatorEventsTSVAll(t.Context()) }
@@ -157,27 +157,26 @@ func TestUpgrade_62_71_pre_cluster_conn_fail_on_a_bc_b_down_E2E(t *testing.T) {
 	RequirePodsRavenVersion(
 		t,
 		testutil.DefaultNS,
-		[]string{"ravendb-a-0", "ravendb-c-0"},
+		[]string{testutil.PodName(key, "a"), testutil.PodName(key, "c")},
 		"6.2.9",
 		20*time.Second,
 	)
 }
 
 func TestUpgrade_62_71_degraded_db_placement_on_a_c_E2E(t *testing.T) {
-	testutil.RecreateTestEnv(t, rbacPath)
+	testutil.RecreateTestEnv(t)
 
 	const (
 		toImage = "ravendb/ravendb:7.1.3-ubuntu.22.04-x64"
 		dbName  = "my_db"
 		ns      = testutil.DefaultNS
-		podA    = "ravendb-a-0"
-		podB    = "ravendb-b-0"
-		podC    = "ravendb-c-0"
 	)
 	cli, key := testutil.CreateCluster(t, testutil.BaseClusterLE, testutil.ClusterCase{
 		Name:      "upgrade-62_71_degraded_db_placement_on_a_c",
 		Namespace: ns,
 	})
+	podA := testutil.PodName(key, "a")
+	podC := testutil.PodName(key, "c")
 
 	testutil.RegisterClusterCleanup(t, cli, key, timeout)
 
@@ -188,8 +187,8 @@ func TestUpgrade_62_71_degraded_db_placement_on_a_c_E2E(t *testing.T) {
 
 	require.NoError(t, SabotageDatabase(t.Context(), ns, podA, "", dbName), "sabotage A")
 	require.NoError(t, SabotageDatabase(t.Context(), ns, podC, "", dbName), "sabotage C")
-	_, _ = testutil.RunKubectl(t.Context(), "-n", ns, "delete", "pod", "ravendb-a-0", "--wait=false")
-	_, _ = testutil.RunKubectl(t.Context(), "-n", ns, "delete", "pod", "ravendb-c-0", "--wait=false")
+	_, _ = testutil.RunKubectl(t.Context(), "-n", ns, "delete", "pod", podA, "--wait=false")
+	_, _ = testutil.RunKubectl(t.Context(), "-n", ns, "delete", "pod", podC, "--wait=false")
 
 	time.Sleep(15 * time.Second) // let topology stablizie
 	testutil.PatchSpecImage(t, cli, key, toImage)
@@ -227,7 +226,7 @@ func TestUpgrade_62_71_degraded_db_placement_on_a_c_E2E(t *testing.T) {
 	RequirePodsRavenVersion(
 		t,
 		testutil.DefaultNS,
-		[]string{"ravendb-a-0"},
+		[]string{testutil.PodName(key, "a")},
 		"7.1.3",
 		20*time.Second,
 	)
@@ -235,7 +234,7 @@ func TestUpgrade_62_71_degraded_db_placement_on_a_c_E2E(t *testing.T) {
 	RequirePodsRavenVersion(
 		t,
 		testutil.DefaultNS,
-		[]string{"ravendb-c-0"},
+		[]string{testutil.PodName(key, "c")},
 		"6.2.9",
 		20*time.Second,
 	)
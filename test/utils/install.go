@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -52,20 +51,6 @@ func ApplyCRDsFromDir(dir string) env.Func {
 	}
 }
 
-func InstallNodeRBAC(ns, basePath string) env.Func {
-	return func(ctx context.Context, _ *envconf.Config) (context.Context, error) {
-		files := []string{
-			filepath.Join(basePath, "ravendb_ops_rbac.yaml"),
-		}
-		for _, f := range files {
-			if _, err := RunKubectl(ctx, "apply", "-f", PathFromRoot(f), "-n", ns); err != nil {
-				return ctx, err
-			}
-		}
-		return ctx, nil
-	}
-}
-
 func BuildAndLoadOperator(image, dockerfile, repoRoot string) env.Func {
 	return func(ctx context.Context, _ *envconf.Config) (context.Context, error) {
 		if os.Getenv("RAVEN_OPERATOR_IMAGE_PREBUILT") != "1" {